@@ -1,16 +1,28 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
 	
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
 	"github.com/user/llm-knowledge-extractor/internal/database"
 	"github.com/user/llm-knowledge-extractor/internal/handlers"
 	"github.com/user/llm-knowledge-extractor/internal/llm"
+	"github.com/user/llm-knowledge-extractor/internal/middleware"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+	"github.com/user/llm-knowledge-extractor/internal/retention"
 )
 
 func main() {
@@ -28,39 +40,167 @@ func main() {
 		dbPath = "./data/knowledge.db"
 	}
 	
-	dbDir := filepath.Dir(dbPath)
-	if err := os.MkdirAll(dbDir, 0755); err != nil {
-		log.Fatalf("Failed to create database directory: %v", err)
+	if dbPath != database.MemoryPath {
+		dbDir := filepath.Dir(dbPath)
+		if err := os.MkdirAll(dbDir, 0755); err != nil {
+			log.Fatalf("Failed to create database directory: %v", err)
+		}
+	}
+	
+	dbOptions := database.DefaultOptions()
+	dbOptions.BusyTimeout = getEnvDuration("DB_BUSY_TIMEOUT", dbOptions.BusyTimeout)
+	dbOptions.MaxOpenConns = getEnvInt("DB_MAX_OPEN_CONNS", dbOptions.MaxOpenConns)
+	dbOptions.StatsCacheTTL = getEnvDuration("STATS_CACHE_TTL", dbOptions.StatsCacheTTL)
+	dbOptions.ReadPath = os.Getenv("DB_READ_PATH")
+	if journalMode := os.Getenv("DB_JOURNAL_MODE"); journalMode != "" {
+		dbOptions.JournalMode = journalMode
 	}
 	
-	db, err := database.New(dbPath)
+	db, err := database.NewWithOptions(dbPath, dbOptions)
 	if err != nil {
 		log.Fatalf("Failed to initialize database: %v", err)
 	}
 	defer db.Close()
 	
+	var tenants *database.Tenants
+	if getEnvBool("MULTI_TENANT", false) {
+		tenantDir := os.Getenv("TENANT_DB_DIR")
+		if tenantDir == "" {
+			tenantDir = "./data/tenants"
+		}
+		tenants, err = database.NewTenants(tenantDir, dbOptions)
+		if err != nil {
+			log.Fatalf("Failed to initialize tenant databases: %v", err)
+		}
+		defer tenants.Close()
+		for _, id := range getEnvList("TENANTS") {
+			if _, err := tenants.Provision(id); err != nil {
+				log.Fatalf("Failed to provision tenant %s: %v", id, err)
+			}
+		}
+		log.Printf("Multi-tenancy enabled: one database per %s under %s", middleware.TenantHeader, tenantDir)
+	}
+	
+	validSentiments := getEnvList("VALID_SENTIMENTS")
+	
 	llmConfig := llm.Config{
-		Provider:     os.Getenv("LLM_PROVIDER"),
+		Provider:        os.Getenv("LLM_PROVIDER"),
+		FixturePath:     os.Getenv("LLM_FIXTURE_PATH"),
+		ValidSentiments: validSentiments,
+		MaxRetries:      getEnvInt("LLM_MAX_RETRIES", 2),
+		RetryBackoff:    getEnvDuration("LLM_RETRY_BACKOFF", 200*time.Millisecond),
+		HealthCacheTTL:  getEnvDuration("LLM_HEALTH_CACHE_TTL", 5*time.Second),
+		RequestTimeout:  getEnvDuration("LLM_REQUEST_TIMEOUT", 30*time.Second),
+		BreakerFailures: getEnvInt("LLM_BREAKER_FAILURES", 5),
+		BreakerCooldown: getEnvDuration("LLM_BREAKER_COOLDOWN", 30*time.Second),
+		Debug:           getEnvBool("LLM_DEBUG", false),
+		AzureEndpoint:   os.Getenv("AZURE_OPENAI_ENDPOINT"),
+		AzureAPIKey:     os.Getenv("AZURE_OPENAI_KEY"),
+		AzureDeployment: os.Getenv("AZURE_OPENAI_DEPLOYMENT"),
+		AzureAPIVersion: os.Getenv("AZURE_OPENAI_API_VERSION"),
+		GeminiAPIKey:    os.Getenv("GEMINI_API_KEY"),
+		GeminiBaseURL:   os.Getenv("GEMINI_BASE_URL"),
 	}
 	
 	if llmConfig.Provider == "" {
 		llmConfig.Provider = "mock"
 		log.Println("No LLM provider configured, using mock provider")
 	}
+	llmConfig.Model = llm.ResolveModel(llmConfig.Provider, os.Getenv)
+	if llmConfig.Model == "" && llmConfig.Provider == "azure" {
+		// Azure picks the model by deployment, so record that instead.
+		llmConfig.Model = llmConfig.AzureDeployment
+	}
+	if llmConfig.Model != "" {
+		log.Printf("Using %s model %s", llmConfig.Provider, llmConfig.Model)
+	}
+	
+	if llmConfig.Debug {
+		log.Println("WARNING: LLM_DEBUG is on; raw prompts and responses are logged (redacted and truncated)")
+	}
 	
 	llmProvider, err := llm.NewProvider(llmConfig)
 	if err != nil {
 		log.Fatalf("Failed to initialize LLM provider: %v", err)
 	}
 	
-	handler := handlers.New(db, llmProvider)
+	startupCheck := llm.StartupCheck{
+		Mode:     os.Getenv("LLM_STARTUP_CHECK"),
+		FailFast: getEnvBool("FAIL_ON_UNAVAILABLE_LLM", false),
+	}
+	if err := startupCheck.Run(context.Background(), llmConfig.Provider, llmProvider); err != nil {
+		log.Fatalf("LLM startup check failed: %v", err)
+	}
+	
+	handlerConfig := handlers.Config{
+		IdempotencyTTL:          getEnvDuration("IDEMPOTENCY_TTL", 24*time.Hour),
+		ConfidenceWarnThreshold: getEnvFloat("CONFIDENCE_WARN_THRESHOLD", 0),
+		MinConfidenceToStore:    getEnvFloat("MIN_CONFIDENCE_TO_STORE", 0),
+		ResultCacheTTL:          getEnvDuration("ANALYSIS_CACHE_TTL", 0),
+		MaxSummaryChars:         getEnvInt("MAX_SUMMARY_CHARS", 2000),
+		BatchMaxFailures:        getEnvInt("BATCH_MAX_FAILURES", 10),
+		BatchMaxTotalChars:      getEnvInt("BATCH_MAX_TOTAL_CHARS", 100000),
+		BatchItemTimeout:        getEnvDuration("BATCH_ITEM_TIMEOUT", 30*time.Second),
+		WorkerPoolSize:          getEnvInt("WORKER_POOL_SIZE", 5),
+		SearchDefaultLimit:      getEnvInt("SEARCH_DEFAULT_LIMIT", 50),
+		SearchMaxLimit:          getEnvInt("SEARCH_MAX_LIMIT", 100),
+		TopicAliases:            getEnvMap("TOPIC_ALIASES"),
+		ValidSentiments:         validSentiments,
+		StopWordsFile:           os.Getenv("STOPWORDS_FILE"),
+		MaxMetadataItems:        getEnvInt("MAX_METADATA_ITEMS", 20),
+		MaxTextLength:           getEnvInt("MAX_TEXT_LENGTH", 50000),
+		KeywordMinLength:        getEnvInt("KEYWORD_MIN_LENGTH", 3),
+		DisableKeywordBackfill:  !getEnvBool("KEYWORD_BACKFILL", true),
+		RetryAfter:              getEnvDuration("LLM_RETRY_AFTER", 5*time.Second),
+		InvalidUTF8:             os.Getenv("INVALID_UTF8"),
+		LLMProvider:             llmConfig.Provider,
+		LLMModel:                llmConfig.Model,
+		ProviderConcurrency:     make(map[string]int),
+	}
+	for _, name := range append([]string{llmConfig.Provider}, getEnvList("LLM_EXTRA_PROVIDERS")...) {
+		if limit := getEnvInt("LLM_PROVIDER_CONCURRENCY_"+strings.ToUpper(name), 0); limit > 0 {
+			handlerConfig.ProviderConcurrency[strings.ToLower(name)] = limit
+		}
+	}
+	if getEnvBool("RECORD_NODE_ID", false) {
+		handlerConfig.NodeID = nodeID()
+		log.Printf("Recording node ID %s on new analyses", handlerConfig.NodeID)
+	}
+	if err := handlerConfig.Validate(); err != nil {
+		log.Fatalf("Invalid configuration: %v", err)
+	}
+	
+	handler := handlers.New(db, llmProvider, handlerConfig)
+	
+	// Extra providers are built like the default one, with the same retry,
+	// timeout and breaker settings, and picked per request by name.
+	for _, name := range getEnvList("LLM_EXTRA_PROVIDERS") {
+		extraConfig := llmConfig
+		extraConfig.Provider = strings.ToLower(name)
+		extraConfig.Model = llm.ResolveModel(extraConfig.Provider, os.Getenv)
+		if extraConfig.Model == "" && extraConfig.Provider == "azure" {
+			extraConfig.Model = extraConfig.AzureDeployment
+		}
+		extraProvider, err := llm.NewProvider(extraConfig)
+		if err != nil {
+			log.Fatalf("Failed to initialize LLM provider %s: %v", extraConfig.Provider, err)
+		}
+		if err := startupCheck.Run(context.Background(), extraConfig.Provider, extraProvider); err != nil {
+			log.Fatalf("LLM startup check failed: %v", err)
+		}
+		handler.AddProvider(extraConfig.Provider, extraConfig.Model, extraProvider)
+		log.Printf("Registered extra LLM provider %s", extraConfig.Provider)
+	}
 	
 	r := gin.Default()
+	if err := middleware.TrustProxies(r, getEnvList("TRUSTED_PROXIES")); err != nil {
+		log.Fatalf("Invalid TRUSTED_PROXIES: %v", err)
+	}
 	
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Idempotency-Key, "+middleware.TenantHeader+", "+response.FormatHeader)
 		
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -70,16 +210,210 @@ func main() {
 		c.Next()
 	})
 	
+	if getEnvBool("LEGACY_RESPONSE_FORMAT", false) {
+		r.Use(response.Legacy())
+	}
+	if getEnvBool("PRETTY_JSON", false) {
+		r.Use(response.Pretty())
+	}
+	if getEnvBool("FAULT_INJECTION", false) {
+		faults := middleware.FaultConfig{
+			Rate:   getEnvFloat("FAULT_RATE", 0),
+			Status: getEnvInt("FAULT_STATUS", http.StatusServiceUnavailable),
+		}
+		log.Printf("WARNING: fault injection enabled (rate %v, status %d); never run this in production", faults.Rate, faults.Status)
+		r.Use(middleware.Faults(faults))
+	}
+	r.Use(middleware.MaxInFlight(getEnvInt("MAX_IN_FLIGHT", 100), getEnvDuration("MAX_IN_FLIGHT_RETRY_AFTER", time.Second)))
+	r.Use(middleware.Gzip(getEnvInt("GZIP_MIN_BYTES", 1024)))
+	r.Use(middleware.MaxBodySize(int64(getEnvInt("MAX_REQUEST_BYTES", 1<<20))))
 	
-	r.POST("/analyze", handler.AnalyzeText)
-	r.POST("/batch-analyze", handler.BatchAnalyzeText)
+	// Routes registered before the tenant middleware don't need a tenant.
+	r.GET("/version", handler.Version)
+	if tenants != nil {
+		r.Use(middleware.Tenant(tenants))
+	}
+	
+	jsonOnly := middleware.ContentTypes("application/json")
+	r.POST("/analyze", jsonOnly, handler.AnalyzeText)
+	r.POST("/analyze/chunked", jsonOnly, handler.AnalyzeChunked)
+	r.POST("/batch-analyze", jsonOnly, handler.BatchAnalyzeText)
+	r.POST("/batch-analyze/stream", jsonOnly, handler.BatchAnalyzeStream)
+	r.POST("/batch-analyze/retry", jsonOnly, handler.RetryBatchAnalyze)
+	r.POST("/batch-analyze/file", middleware.ContentTypes("multipart/form-data"), handler.BatchAnalyzeFile)
+	r.POST("/import", jsonOnly, handler.ImportAnalyses)
+	r.POST("/validate", jsonOnly, handler.ValidateText)
+	r.POST("/keywords", handler.ExtractKeywords)
+	r.POST("/summarize", handler.Summarize)
 	r.GET("/search", handler.SearchAnalyses)
+	r.GET("/export.ndjson", handler.ExportNDJSON)
+	r.GET("/export.zip", handler.ExportZip)
+	r.DELETE("/analyses", handler.DeleteAnalyses)
+	r.GET("/analyses/clusters", handler.ClusterAnalyses)
+	r.GET("/analyses/:id/markdown", handler.ExportMarkdown)
+	r.GET("/analyses/:id/html", handler.ExportHTML)
+	r.GET("/analyses/:id/typed", handler.GetAnalysisTyped)
+	r.GET("/analyses/:id/history", handler.GetAnalysisHistory)
+	r.POST("/analyses/:id/notes", handler.AddNote)
+	r.GET("/analyses/:id/notes", handler.ListNotes)
+	r.POST("/analyses/:id/correct-sentiment", handler.CorrectSentiment)
+	r.GET("/analyses/:id/corrections", handler.ListCorrections)
+	r.POST("/analyses/:id/reextract-keywords", handler.ReextractKeywords)
+	r.POST("/analyses/reextract-keywords", handler.BulkReextractKeywords)
+	r.GET("/stats", handler.Stats)
+	r.GET("/stats/topics/:topic/timeline", handler.TopicTimeline)
+	r.GET("/stats/keyword-graph", handler.KeywordGraph)
+	
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	
+	var background sync.WaitGroup
+	if retentionDays := getEnvInt("RETENTION_DAYS", 0); retentionDays > 0 {
+		maxAge := time.Duration(retentionDays) * 24 * time.Hour
+		cleaner := retention.NewCleaner(db, maxAge)
+		if tenants != nil {
+			cleaner = retention.NewTenantCleaner(tenants, maxAge)
+		}
+		interval := getEnvDuration("RETENTION_INTERVAL", time.Hour)
+		log.Printf("Retention cleanup enabled: %d days, every %s", retentionDays, interval)
+		
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			cleaner.Run(ctx, interval)
+		}()
+	}
+	
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%s", port),
+		Handler: r,
+	}
 	
 	log.Printf("Starting server on port %s", port)
 	log.Printf("Database path: %s", dbPath)
 	log.Printf("LLM Provider: %s", llmConfig.Provider)
 	
-	if err := r.Run(fmt.Sprintf(":%s", port)); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+	
+	<-ctx.Done()
+	log.Println("Shutting down")
+	
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown failed: %v", err)
+	}
+	background.Wait()
+}
+
+func getEnvInt(key string, fallback int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid value for %s: %q, using default %d", key, value, fallback)
+		return fallback
+	}
+	
+	return parsed
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		log.Printf("Invalid value for %s: %q, using default %v", key, value, fallback)
+		return fallback
+	}
+	
+	return parsed
+}
+
+func getEnvBool(key string, fallback bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid value for %s: %q, using default %t", key, value, fallback)
+		return fallback
+	}
+	
+	return parsed
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid value for %s: %q, using default %s", key, value, fallback)
+		return fallback
+	}
+	
+	return parsed
+}
+
+// nodeID names this instance for RECORD_NODE_ID: NODE_ID when set, otherwise
+// the hostname.
+func nodeID() string {
+	if id := strings.TrimSpace(os.Getenv("NODE_ID")); id != "" {
+		return id
+	}
+	hostname, err := os.Hostname()
+	if err != nil {
+		log.Fatalf("RECORD_NODE_ID is set but NODE_ID is empty and the hostname is unavailable: %v", err)
+	}
+	return hostname
+}
+
+func getEnvList(key string) []string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
 	}
+	
+	var result []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	
+	return result
+}
+
+func getEnvMap(key string) map[string]string {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	
+	result := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		k, v, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(k) == "" {
+			log.Printf("Ignoring invalid entry in %s: %q", key, pair)
+			continue
+		}
+		result[strings.TrimSpace(k)] = strings.TrimSpace(v)
+	}
+	
+	return result
 }
\ No newline at end of file
@@ -1,14 +1,21 @@
 package analyzer
 
 import (
+	"fmt"
+	"math"
 	"regexp"
 	"sort"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
+const DefaultMinWordLength = 3
+
 type KeywordExtractor struct {
-	stopWords map[string]bool
+	stopWords     map[string]bool
+	language      string
+	minWordLength int
 }
 
 func NewKeywordExtractor() *KeywordExtractor {
@@ -37,17 +44,52 @@ func NewKeywordExtractor() *KeywordExtractor {
 		"had": true, "were": true, "said": true, "did": true, "having": true,
 		"may": true, "being": true,
 	}
-	return &KeywordExtractor{stopWords: stopWords}
+	return &KeywordExtractor{stopWords: stopWords, language: DefaultLanguage, minWordLength: DefaultMinWordLength}
+}
+
+func (ke *KeywordExtractor) Language() string {
+	return ke.language
+}
+
+// SetMinWordLength changes the shortest keyword, in characters, the extractor
+// returns by default. Values below 1 are ignored.
+func (ke *KeywordExtractor) SetMinWordLength(n int) {
+	if n > 0 {
+		ke.minWordLength = n
+	}
 }
 
 func (ke *KeywordExtractor) ExtractKeywords(text string, topN int) []string {
-	nouns := ke.extractNouns(text)
+	return ke.ExtractKeywordsWithMinLength(text, topN, ke.minWordLength)
+}
+
+// ExtractKeywordsWithMinLength returns up to topN keywords, most frequent
+// first. Words are counted case-insensitively, so each lowercase word appears
+// once and ties on count are broken alphabetically by it; the order is total,
+// and which of several equally frequent words survive the topN cutoff never
+// depends on map iteration order.
+func (ke *KeywordExtractor) ExtractKeywordsWithMinLength(text string, topN, minLength int) []string {
+	if minLength <= 0 {
+		minLength = ke.minWordLength
+	}
+	
+	var nouns []wordToken
+	if ke.language == DefaultLanguage {
+		nouns = ke.extractNouns(text)
+	} else {
+		nouns = tokenize(unicodeWordPattern, text)
+	}
 	
 	wordFreq := make(map[string]int)
+	casing := make(map[string]*casingVote)
 	for _, noun := range nouns {
-		word := strings.ToLower(noun)
-		if !ke.stopWords[word] && len(word) > 2 {
+		word := strings.ToLower(noun.word)
+		if !ke.stopWords[word] && utf8.RuneCountInString(word) >= minLength {
 			wordFreq[word]++
+			if casing[word] == nil {
+				casing[word] = &casingVote{}
+			}
+			casing[word].add(noun)
 		}
 	}
 	
@@ -70,30 +112,95 @@ func (ke *KeywordExtractor) ExtractKeywords(text string, topN int) []string {
 	
 	result := make([]string, 0, topN)
 	for i := 0; i < len(counts) && i < topN; i++ {
-		result = append(result, counts[i].word)
+		result = append(result, casing[counts[i].word].form(counts[i].word))
 	}
 	
 	return result
 }
 
-func (ke *KeywordExtractor) extractNouns(text string) []string {
-	wordPattern := regexp.MustCompile(`\b[A-Za-z]+\b`)
-	words := wordPattern.FindAllString(text, -1)
+type wordToken struct {
+	word          string
+	sentenceStart bool
+}
+
+func tokenize(pattern *regexp.Regexp, text string) []wordToken {
+	var tokens []wordToken
+	for _, loc := range pattern.FindAllStringIndex(text, -1) {
+		prefix := strings.TrimRightFunc(text[:loc[0]], unicode.IsSpace)
+		start := prefix == "" || strings.ContainsAny(prefix[len(prefix)-1:], ".!?")
+		tokens = append(tokens, wordToken{word: text[loc[0]:loc[1]], sentenceStart: start})
+	}
+	return tokens
+}
+
+// casingVote decides how a keyword is displayed. Acronyms and capitalized
+// words in mid-sentence vote for their original form, lowercase occurrences
+// vote for lowercase, and capitalized sentence-initial words abstain. The
+// original form is kept only if every vote agrees on it.
+type casingVote struct {
+	cased string
+	mixed bool
+}
+
+func (v *casingVote) add(token wordToken) {
+	var form string
+	switch {
+	case isAcronym(token.word):
+		form = token.word
+	case strings.ToLower(token.word) == token.word:
+		form = ""
+	case token.sentenceStart:
+		return
+	default:
+		form = token.word
+	}
 	
-	var nouns []string
-	for _, word := range words {
-		if ke.isLikelyNoun(word) {
-			nouns = append(nouns, word)
+	if form == "" {
+		v.mixed = true
+	} else if v.cased == "" {
+		v.cased = form
+	} else if v.cased != form {
+		v.mixed = true
+	}
+}
+
+func (v *casingVote) form(lower string) string {
+	if v.mixed || v.cased == "" {
+		return lower
+	}
+	return v.cased
+}
+
+var acronymPattern = regexp.MustCompile(`^[A-Z]{2,}$`)
+
+func isAcronym(word string) bool {
+	return acronymPattern.MatchString(word)
+}
+
+var nounPattern = regexp.MustCompile(`\b[A-Za-z]+\b`)
+
+func (ke *KeywordExtractor) extractNouns(text string) []wordToken {
+	var nouns []wordToken
+	for _, token := range tokenize(nounPattern, text) {
+		if ke.isLikelyNoun(token) {
+			nouns = append(nouns, token)
 		}
 	}
 	
 	return nouns
 }
 
-func (ke *KeywordExtractor) isLikelyNoun(word string) bool {
-	word = strings.ToLower(word)
+// isLikelyNoun reports whether token looks like a noun. A capitalized word
+// counts as a proper noun only in mid-sentence, where its capital letter is
+// not just the start of the sentence.
+func (ke *KeywordExtractor) isLikelyNoun(token wordToken) bool {
+	if isAcronym(token.word) {
+		return true
+	}
 	
-	if len(word) < 3 {
+	word := strings.ToLower(token.word)
+	
+	if len(word) < 2 {
 		return false
 	}
 	
@@ -109,8 +216,7 @@ func (ke *KeywordExtractor) isLikelyNoun(word string) bool {
 		}
 	}
 	
-	firstChar := rune(word[0])
-	if unicode.IsUpper(firstChar) {
+	if !token.sentenceStart && unicode.IsUpper(rune(token.word[0])) {
 		return true
 	}
 	
@@ -132,38 +238,110 @@ func (ke *KeywordExtractor) isLikelyNoun(word string) bool {
 	return commonNouns[word]
 }
 
+type ConfidenceFactor struct {
+	Name   string  `json:"name"`
+	Score  float64 `json:"score"`
+	Weak   bool    `json:"weak"`
+	Reason string  `json:"reason,omitempty"`
+}
+
+type ConfidenceBreakdown struct {
+	Score   float64            `json:"score"`
+	Factors []ConfidenceFactor `json:"factors"`
+}
+
+func (b ConfidenceBreakdown) WeakReasons() []string {
+	var reasons []string
+	for _, factor := range b.Factors {
+		if factor.Weak {
+			reasons = append(reasons, factor.Reason)
+		}
+	}
+	return reasons
+}
+
+// ConfidenceDecimals is the number of decimal places confidence scores are
+// rounded to, so summed factors store as 0.7 rather than 0.7000000000000001.
+const ConfidenceDecimals = 2
+
+// RoundConfidence rounds a score to ConfidenceDecimals places, halves away
+// from zero.
+func RoundConfidence(score float64) float64 {
+	scale := math.Pow(10, ConfidenceDecimals)
+	return math.Round(score*scale) / scale
+}
+
 func CalculateConfidence(text string, summary string, topics []string) float64 {
+	return CalculateConfidenceBreakdown(text, summary, topics).Score
+}
+
+func CalculateConfidenceBreakdown(text string, summary string, topics []string) ConfidenceBreakdown {
 	if text == "" || summary == "" {
-		return 0.0
+		return ConfidenceBreakdown{
+			Score: 0.0,
+			Factors: []ConfidenceFactor{
+				{Name: "input", Weak: true, Reason: "text or summary is empty"},
+			},
+		}
 	}
 	
 	textLen := len(strings.Fields(text))
 	summaryLen := len(strings.Fields(summary))
 	
 	baseConfidence := 0.5
+	var factors []ConfidenceFactor
 	
+	textFactor := ConfidenceFactor{Name: "text_length"}
 	if textLen > 50 {
-		baseConfidence += 0.2
+		textFactor.Score = 0.2
 	} else if textLen > 20 {
-		baseConfidence += 0.1
+		textFactor.Score = 0.1
+		textFactor.Weak = true
+		textFactor.Reason = fmt.Sprintf("text is fairly short (%d words)", textLen)
+	} else {
+		textFactor.Weak = true
+		textFactor.Reason = fmt.Sprintf("text is very short (%d words)", textLen)
 	}
+	factors = append(factors, textFactor)
 	
+	summaryFactor := ConfidenceFactor{Name: "summary_length"}
 	if summaryLen > 5 && summaryLen < 50 {
-		baseConfidence += 0.1
+		summaryFactor.Score = 0.1
+	} else {
+		summaryFactor.Weak = true
+		summaryFactor.Reason = fmt.Sprintf("summary length of %d words is outside the expected range", summaryLen)
 	}
+	factors = append(factors, summaryFactor)
 	
+	topicFactor := ConfidenceFactor{Name: "topic_count"}
 	if len(topics) >= 3 {
-		baseConfidence += 0.1
+		topicFactor.Score = 0.1
+	} else {
+		topicFactor.Weak = true
+		topicFactor.Reason = fmt.Sprintf("only %d topics were identified", len(topics))
 	}
+	factors = append(factors, topicFactor)
 	
+	compressionFactor := ConfidenceFactor{Name: "compression_ratio"}
 	compressionRatio := float64(summaryLen) / float64(textLen)
 	if compressionRatio > 0.05 && compressionRatio < 0.3 {
-		baseConfidence += 0.1
+		compressionFactor.Score = 0.1
+	} else {
+		compressionFactor.Weak = true
+		compressionFactor.Reason = fmt.Sprintf("summary-to-text ratio of %.2f is outside the expected range", compressionRatio)
+	}
+	factors = append(factors, compressionFactor)
+	
+	for _, factor := range factors {
+		baseConfidence += factor.Score
 	}
 	
 	if baseConfidence > 1.0 {
 		baseConfidence = 1.0
 	}
 	
-	return baseConfidence
+	return ConfidenceBreakdown{
+		Score:   RoundConfidence(baseConfidence),
+		Factors: factors,
+	}
 }
\ No newline at end of file
@@ -1,6 +1,8 @@
 package analyzer
 
 import (
+	"strconv"
+	"strings"
 	"testing"
 	
 	"github.com/stretchr/testify/assert"
@@ -62,33 +64,92 @@ func TestKeywordExtractor_ExtractKeywords(t *testing.T) {
 	}
 }
 
+func TestKeywordExtractor_PreservesAcronymCasing(t *testing.T) {
+	ke := NewKeywordExtractor()
+	
+	keywords := ke.ExtractKeywords(
+		"NASA engineers benchmarked the GPU cluster. The GPU outperformed older hardware, and NASA plans a second GPU order.",
+		3,
+	)
+	assert.Equal(t, []string{"GPU", "NASA", "cluster"}, keywords)
+	
+	keywords = ke.ExtractKeywords("The Server crashed. Restarting the server fixed it, and the server stayed up.", 1)
+	assert.Equal(t, []string{"server"}, keywords, "mixed casing falls back to lowercase")
+	
+	keywords = ke.ExtractKeywords("Reports from the Commission arrived. Analysts at the Commission disagreed.", 1)
+	assert.Equal(t, []string{"Commission"}, keywords, "consistently capitalized nouns keep their casing")
+}
+
+func TestKeywordExtractor_KeepsMidSentenceProperNouns(t *testing.T) {
+	ke := NewKeywordExtractor()
+	
+	keywords := ke.ExtractKeywords("We flew to Lisbon for the summit. Friends met us in Lisbon.", 3)
+	assert.Equal(t, []string{"Lisbon"}, keywords, "sentence-initial capitals do not make a word a noun")
+}
+
+func TestKeywordExtractor_TiesAreDeterministic(t *testing.T) {
+	ke := NewKeywordExtractor()
+	text := "Station staff noted the migration. Caution signs went up before the auction. Adoption of the migration plan followed."
+	
+	expected := []string{"migration", "adoption", "auction"}
+	for i := 0; i < 50; i++ {
+		assert.Equal(t, expected, ke.ExtractKeywords(text, 3), "run %d", i)
+	}
+	
+	all := ke.ExtractKeywords(text, 100)
+	assert.Greater(t, len(all), 3)
+	assert.Equal(t, expected, all[:3], "truncation keeps a prefix of the full ordering")
+}
+
+func TestKeywordExtractor_MinWordLength(t *testing.T) {
+	text := "CO readings rose. The CO sensor and the CO alarm confirmed combustion in the chamber."
+	
+	ke := NewKeywordExtractor()
+	assert.NotContains(t, ke.ExtractKeywords(text, 3), "CO")
+	
+	keywords := ke.ExtractKeywordsWithMinLength(text, 3, 2)
+	assert.Equal(t, "CO", keywords[0])
+	
+	ke.SetMinWordLength(2)
+	assert.Equal(t, keywords, ke.ExtractKeywords(text, 3))
+	
+	ke.SetMinWordLength(0)
+	assert.Equal(t, keywords, ke.ExtractKeywords(text, 3), "non-positive values are ignored")
+}
+
 func TestKeywordExtractor_IsLikelyNoun(t *testing.T) {
 	ke := NewKeywordExtractor()
 	
 	tests := []struct {
-		word     string
-		expected bool
+		word          string
+		sentenceStart bool
+		expected      bool
 	}{
-		{"organization", true},
-		{"development", true},
-		{"happiness", true},
-		{"teacher", true},
-		{"manager", true},
-		{"democracy", true},
-		{"friendship", true},
-		{"technology", true},
-		{"running", true},
-		{"data", true},
-		{"api", true},
-		{"run", false},
-		{"is", false},
-		{"at", false},
-		{"go", false},
+		{"organization", false, true},
+		{"development", false, true},
+		{"happiness", false, true},
+		{"teacher", false, true},
+		{"manager", false, true},
+		{"democracy", false, true},
+		{"friendship", false, true},
+		{"technology", false, true},
+		{"running", false, true},
+		{"data", false, true},
+		{"api", false, true},
+		{"GPU", false, true},
+		{"NASA", false, true},
+		{"Lisbon", false, true},
+		{"Lisbon", true, false},
+		{"lisbon", false, false},
+		{"run", false, false},
+		{"is", false, false},
+		{"at", false, false},
+		{"go", false, false},
 	}
 	
 	for _, tt := range tests {
 		t.Run(tt.word, func(t *testing.T) {
-			result := ke.isLikelyNoun(tt.word)
+			result := ke.isLikelyNoun(wordToken{word: tt.word, sentenceStart: tt.sentenceStart})
 			assert.Equal(t, tt.expected, result)
 		})
 	}
@@ -150,6 +211,47 @@ func TestCalculateConfidence(t *testing.T) {
 	}
 }
 
+func TestCalculateConfidenceBreakdown(t *testing.T) {
+	text := "This is a short text about testing."
+	summary := "A text about testing."
+	topics := []string{"testing"}
+	
+	breakdown := CalculateConfidenceBreakdown(text, summary, topics)
+	assert.Equal(t, CalculateConfidence(text, summary, topics), breakdown.Score)
+	assert.Len(t, breakdown.Factors, 4)
+	
+	reasons := breakdown.WeakReasons()
+	assert.Contains(t, reasons, "text is very short (7 words)")
+	assert.Contains(t, reasons, "only 1 topics were identified")
+	
+	empty := CalculateConfidenceBreakdown("", "", nil)
+	assert.Equal(t, 0.0, empty.Score)
+	assert.Equal(t, []string{"text or summary is empty"}, empty.WeakReasons())
+}
+
+func TestRoundConfidence(t *testing.T) {
+	assert.Equal(t, 0.7, RoundConfidence(0.5+0.1+0.1))
+	assert.Equal(t, 0.67, RoundConfidence(2.0/3))
+	assert.Equal(t, 0.13, RoundConfidence(0.125))
+	assert.Equal(t, 1.0, RoundConfidence(1.0000000000000002))
+	
+	texts := []string{
+		"Short text.",
+		"This is a short text about testing and nothing else at all, written in one line for the test.",
+		strings.Repeat("Longer texts about databases and indexes score higher. ", 12),
+	}
+	summaries := []string{"Tiny.", "A summary of a few words about testing.", strings.Repeat("word ", 60)}
+	for _, text := range texts {
+		for _, summary := range summaries {
+			score := CalculateConfidence(text, summary, []string{"a", "b", "c"})
+			formatted := strconv.FormatFloat(score, 'f', -1, 64)
+			if dot := strings.IndexByte(formatted, '.'); dot >= 0 {
+				assert.LessOrEqual(t, len(formatted)-dot-1, ConfidenceDecimals, formatted)
+			}
+		}
+	}
+}
+
 func BenchmarkKeywordExtraction(b *testing.B) {
 	ke := NewKeywordExtractor()
 	text := `Machine learning is a subset of artificial intelligence that focuses on 
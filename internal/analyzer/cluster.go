@@ -0,0 +1,105 @@
+package analyzer
+
+// Jaccard returns the size of the intersection of a and b over the size of
+// their union, or 0 when both sets are empty.
+func Jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 0
+	}
+	
+	shared := 0
+	for item := range a {
+		if b[item] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
+
+// ClusterSets groups the feature sets into at most k clusters by average
+// linkage agglomerative clustering on Jaccard similarity: every set starts
+// alone and the two most similar clusters are merged until k remain. Each
+// cluster lists the indexes of its sets in ascending order; clusters are
+// ordered by their first index. Ties merge the lowest indexes first, so the
+// result is deterministic. Time is cubic in len(sets), so callers should
+// bound the input.
+func ClusterSets(sets [][]string, k int) [][]int {
+	n := len(sets)
+	if k < 1 {
+		k = 1
+	}
+	
+	features := make([]map[string]bool, n)
+	for i, set := range sets {
+		features[i] = make(map[string]bool, len(set))
+		for _, item := range set {
+			features[i][item] = true
+		}
+	}
+	
+	similarity := make([][]float64, n)
+	for i := range similarity {
+		similarity[i] = make([]float64, n)
+		for j := 0; j < i; j++ {
+			similarity[i][j] = Jaccard(features[i], features[j])
+			similarity[j][i] = similarity[i][j]
+		}
+	}
+	
+	members := make([][]int, n)
+	active := make([]bool, n)
+	for i := range members {
+		members[i] = []int{i}
+		active[i] = true
+	}
+	
+	for remaining := n; remaining > k; remaining-- {
+		bestI, bestJ, best := -1, -1, -1.0
+		for i := 0; i < n; i++ {
+			if !active[i] {
+				continue
+			}
+			for j := i + 1; j < n; j++ {
+				if active[j] && similarity[i][j] > best {
+					bestI, bestJ, best = i, j, similarity[i][j]
+				}
+			}
+		}
+		
+		// Lance-Williams update for average linkage: the merged cluster's
+		// similarity to any other is the size-weighted mean of its parts'.
+		sizeI, sizeJ := float64(len(members[bestI])), float64(len(members[bestJ]))
+		for other := 0; other < n; other++ {
+			if !active[other] || other == bestI || other == bestJ {
+				continue
+			}
+			merged := (sizeI*similarity[bestI][other] + sizeJ*similarity[bestJ][other]) / (sizeI + sizeJ)
+			similarity[bestI][other] = merged
+			similarity[other][bestI] = merged
+		}
+		
+		members[bestI] = mergeSorted(members[bestI], members[bestJ])
+		active[bestJ] = false
+	}
+	
+	clusters := make([][]int, 0, k)
+	for i := 0; i < n; i++ {
+		if active[i] {
+			clusters = append(clusters, members[i])
+		}
+	}
+	return clusters
+}
+
+func mergeSorted(a, b []int) []int {
+	result := make([]int, 0, len(a)+len(b))
+	for len(a) > 0 && len(b) > 0 {
+		if a[0] < b[0] {
+			result, a = append(result, a[0]), a[1:]
+		} else {
+			result, b = append(result, b[0]), b[1:]
+		}
+	}
+	result = append(result, a...)
+	return append(result, b...)
+}
\ No newline at end of file
@@ -0,0 +1,39 @@
+package analyzer
+
+import (
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJaccard(t *testing.T) {
+	set := func(items ...string) map[string]bool {
+		result := make(map[string]bool)
+		for _, item := range items {
+			result[item] = true
+		}
+		return result
+	}
+	
+	assert.Equal(t, 0.0, Jaccard(set(), set()))
+	assert.Equal(t, 1.0, Jaccard(set("a", "b"), set("a", "b")))
+	assert.Equal(t, 0.5, Jaccard(set("a", "b", "c"), set("b", "c", "d")))
+	assert.Equal(t, 0.0, Jaccard(set("a"), set("b")))
+}
+
+func TestClusterSets(t *testing.T) {
+	sets := [][]string{
+		{"go", "concurrency", "channels"},
+		{"cooking", "pasta", "italy"},
+		{"go", "channels", "goroutines"},
+		{"cooking", "pasta", "sauce"},
+		{"go", "concurrency", "goroutines"},
+		{"italy", "pasta", "sauce"},
+	}
+	
+	assert.Equal(t, [][]int{{0, 2, 4}, {1, 3, 5}}, ClusterSets(sets, 2))
+	assert.Len(t, ClusterSets(sets, 6), 6)
+	assert.Len(t, ClusterSets(sets, 10), 6)
+	assert.Equal(t, [][]int{{0, 1, 2, 3, 4, 5}}, ClusterSets(sets, 0))
+	assert.Empty(t, ClusterSets(nil, 3))
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"sort"
+)
+
+// Cooccurrence counts the sets containing both A and B, with A < B.
+type Cooccurrence struct {
+	A     string
+	B     string
+	Count int
+}
+
+// CountCooccurrences counts, for every pair of distinct items, how many of
+// the sets contain both; repeats within a set count once. Pairs seen in
+// fewer than minCount sets are dropped. The rest are ordered by count, most
+// frequent first, then by A and B, and cut to limit when it is positive;
+// the second result reports whether that cut anything. Time is quadratic in
+// the size of each set, so callers should bound it.
+func CountCooccurrences(sets [][]string, minCount, limit int) ([]Cooccurrence, bool) {
+	type pair struct{ a, b string }
+	counts := make(map[pair]int)
+	
+	for _, set := range sets {
+		items := distinctSorted(set)
+		for i := 0; i < len(items); i++ {
+			for j := i + 1; j < len(items); j++ {
+				counts[pair{items[i], items[j]}]++
+			}
+		}
+	}
+	
+	result := make([]Cooccurrence, 0)
+	for p, count := range counts {
+		if count >= minCount {
+			result = append(result, Cooccurrence{A: p.a, B: p.b, Count: count})
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		if result[i].A != result[j].A {
+			return result[i].A < result[j].A
+		}
+		return result[i].B < result[j].B
+	})
+	
+	if limit > 0 && len(result) > limit {
+		return result[:limit], true
+	}
+	return result, false
+}
+
+func distinctSorted(set []string) []string {
+	seen := make(map[string]bool, len(set))
+	items := make([]string, 0, len(set))
+	for _, item := range set {
+		if item != "" && !seen[item] {
+			seen[item] = true
+			items = append(items, item)
+		}
+	}
+	sort.Strings(items)
+	return items
+}
\ No newline at end of file
@@ -0,0 +1,41 @@
+package analyzer
+
+import (
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCountCooccurrences(t *testing.T) {
+	sets := [][]string{
+		{"go", "channels", "concurrency"},
+		{"go", "channels", "go"},
+		{"channels", "go", "goroutines"},
+		{"pasta", "sauce"},
+		{"solo"},
+		nil,
+	}
+	
+	pairs, truncated := CountCooccurrences(sets, 1, 0)
+	assert.False(t, truncated)
+	assert.Equal(t, []Cooccurrence{
+		{"channels", "go", 3},
+		{"channels", "concurrency", 1},
+		{"channels", "goroutines", 1},
+		{"concurrency", "go", 1},
+		{"go", "goroutines", 1},
+		{"pasta", "sauce", 1},
+	}, pairs)
+	
+	pairs, truncated = CountCooccurrences(sets, 2, 0)
+	assert.False(t, truncated)
+	assert.Equal(t, []Cooccurrence{{"channels", "go", 3}}, pairs)
+	
+	pairs, truncated = CountCooccurrences(sets, 1, 2)
+	assert.True(t, truncated)
+	assert.Equal(t, []Cooccurrence{{"channels", "go", 3}, {"channels", "concurrency", 1}}, pairs)
+	
+	pairs, truncated = CountCooccurrences(nil, 1, 10)
+	assert.False(t, truncated)
+	assert.Empty(t, pairs)
+}
\ No newline at end of file
@@ -0,0 +1,116 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+const DefaultLanguage = "en"
+
+var SupportedLanguages = []string{"en", "es", "fr", "de"}
+
+var unicodeWordPattern = regexp.MustCompile(`\p{L}+`)
+
+var languageStopWords = map[string][]string{
+	"es": {
+		"el", "la", "los", "las", "un", "una", "unos", "unas", "de", "del",
+		"al", "a", "en", "y", "o", "que", "por", "para", "con", "sin",
+		"se", "su", "sus", "es", "son", "fue", "era", "ser", "estar", "está",
+		"están", "como", "más", "pero", "muy", "también", "este", "esta", "estos", "estas",
+		"ese", "esa", "lo", "le", "les", "ya", "hay", "cuando", "donde", "sobre",
+		"entre", "hasta", "desde", "porque", "nos", "mi", "tu", "yo", "él", "ella",
+		"ellos", "todo", "todos", "otro", "otra", "han", "ha", "sido", "tiene", "tienen",
+	},
+	"fr": {
+		"le", "la", "les", "un", "une", "des", "de", "du", "au", "aux",
+		"et", "ou", "que", "qui", "dans", "en", "par", "pour", "avec", "sans",
+		"sur", "sous", "est", "sont", "été", "être", "avoir", "a", "ont", "ce",
+		"cet", "cette", "ces", "il", "elle", "ils", "elles", "nous", "vous", "je",
+		"tu", "on", "se", "sa", "son", "ses", "leur", "leurs", "mais", "plus",
+		"très", "aussi", "comme", "ne", "pas", "y", "tout", "tous", "fait", "entre",
+	},
+	"de": {
+		"der", "die", "das", "den", "dem", "des", "ein", "eine", "einer", "einen",
+		"einem", "und", "oder", "aber", "in", "im", "an", "am", "auf", "aus",
+		"mit", "von", "vom", "zu", "zum", "zur", "für", "über", "unter", "bei",
+		"ist", "sind", "war", "waren", "sein", "hat", "haben", "wird", "werden", "wurde",
+		"nicht", "auch", "als", "wie", "so", "dass", "es", "er", "sie", "wir",
+		"ich", "du", "ihr", "sich", "noch", "nur", "sehr", "mehr", "nach", "durch",
+	},
+}
+
+var detectionStopWords = func() map[string]map[string]bool {
+	sets := map[string]map[string]bool{
+		DefaultLanguage: NewKeywordExtractor().stopWords,
+	}
+	for code, words := range languageStopWords {
+		sets[code] = stopWordSet(words)
+	}
+	return sets
+}()
+
+// DetectLanguage guesses the language of text by counting stop-word hits for
+// each supported language. Text with too few hits to tell is treated as
+// English.
+func DetectLanguage(text string) string {
+	words := unicodeWordPattern.FindAllString(strings.ToLower(text), -1)
+	
+	best, bestHits := DefaultLanguage, 0
+	for _, code := range SupportedLanguages {
+		hits := 0
+		for _, word := range words {
+			if detectionStopWords[code][word] {
+				hits++
+			}
+		}
+		if hits > bestHits {
+			best, bestHits = code, hits
+		}
+	}
+	
+	if bestHits < 2 {
+		return DefaultLanguage
+	}
+	return best
+}
+
+func stopWordSet(words []string) map[string]bool {
+	set := make(map[string]bool, len(words))
+	for _, word := range words {
+		set[word] = true
+	}
+	return set
+}
+
+// NewKeywordExtractorForLanguage returns an extractor using the stop words for
+// the given ISO 639-1 code, falling back to English for unknown codes. The
+// English noun heuristics don't carry over, so other languages rank any
+// non-stop word.
+func NewKeywordExtractorForLanguage(code string) *KeywordExtractor {
+	code = strings.ToLower(strings.TrimSpace(code))
+	words, ok := languageStopWords[code]
+	if !ok {
+		return NewKeywordExtractor()
+	}
+	
+	return &KeywordExtractor{
+		stopWords:     stopWordSet(words),
+		language:      code,
+		minWordLength: DefaultMinWordLength,
+	}
+}
+
+// NewLanguageKeywordExtractors builds an extractor for every supported
+// language, each extended with the stop words from stopWordsFile.
+func NewLanguageKeywordExtractors(stopWordsFile string) map[string]*KeywordExtractor {
+	extra := loadStopWordsFile(stopWordsFile)
+	
+	extractors := make(map[string]*KeywordExtractor, len(SupportedLanguages))
+	for _, code := range SupportedLanguages {
+		ke := NewKeywordExtractorForLanguage(code)
+		ke.AddStopWords(extra)
+		extractors[code] = ke
+	}
+	
+	return extractors
+}
\ No newline at end of file
@@ -0,0 +1,67 @@
+package analyzer
+
+import (
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			name:     "English",
+			text:     "The team shipped the new release and it was well received by the users.",
+			expected: "en",
+		},
+		{
+			name:     "Spanish",
+			text:     "El equipo presentó los resultados de la investigación sobre el cambio climático en la región.",
+			expected: "es",
+		},
+		{
+			name:     "French",
+			text:     "Les chercheurs ont publié une étude sur les effets du climat dans les régions côtières.",
+			expected: "fr",
+		},
+		{
+			name:     "German",
+			text:     "Die Forscher haben eine Studie über die Auswirkungen des Klimas auf die Küsten veröffentlicht.",
+			expected: "de",
+		},
+		{
+			name:     "Too little signal defaults to English",
+			text:     "Kubernetes",
+			expected: "en",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, DetectLanguage(tt.text))
+		})
+	}
+}
+
+func TestNewKeywordExtractorForLanguage_Spanish(t *testing.T) {
+	text := "La investigación sobre el clima muestra que el clima de la región cambia. La investigación continúa en la universidad."
+	
+	ke := NewKeywordExtractorForLanguage("es")
+	assert.Equal(t, "es", ke.Language())
+	
+	keywords := ke.ExtractKeywords(text, 3)
+	assert.Equal(t, []string{"clima", "investigación", "cambia"}, keywords)
+	for _, keyword := range keywords {
+		assert.NotContains(t, []string{"la", "el", "que", "de", "sobre", "en"}, keyword)
+	}
+}
+
+func TestNewKeywordExtractorForLanguage_UnknownFallsBackToEnglish(t *testing.T) {
+	ke := NewKeywordExtractorForLanguage("xx")
+	
+	assert.Equal(t, DefaultLanguage, ke.Language())
+	assert.Equal(t, NewKeywordExtractor().stopWords, ke.stopWords)
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package analyzer
+
+import (
+	"strings"
+	"unicode"
+	
+	"golang.org/x/text/unicode/norm"
+)
+
+var quoteReplacer = strings.NewReplacer(
+	"\u2018", "'", "\u2019", "'", "\u201a", "'", "\u201b", "'",
+	"\u201c", `"`, "\u201d", `"`, "\u201e", `"`, "\u201f", `"`,
+)
+
+// NormalizeText cleans up pasted text before it is analyzed or stored: it
+// applies NFC, folds smart quotes to ASCII, drops control and zero-width
+// characters, turns every kind of space into a plain one and collapses runs
+// of spaces. Line breaks are kept, with blank lines squeezed to one.
+func NormalizeText(text string) string {
+	text = quoteReplacer.Replace(norm.NFC.String(text))
+	
+	var b strings.Builder
+	b.Grow(len(text))
+	pendingSpace := false
+	newlines := 0
+	
+	for _, r := range text {
+		switch {
+		case r == '\n':
+			newlines++
+			pendingSpace = false
+			continue
+		case r == '\r':
+			continue
+		case unicode.IsSpace(r):
+			pendingSpace = true
+			continue
+		case unicode.IsControl(r) || unicode.Is(unicode.Cf, r):
+			continue
+		}
+		
+		if b.Len() > 0 {
+			switch {
+			case newlines > 1:
+				b.WriteString("\n\n")
+			case newlines == 1:
+				b.WriteByte('\n')
+			case pendingSpace:
+				b.WriteByte(' ')
+			}
+		}
+		newlines = 0
+		pendingSpace = false
+		b.WriteRune(r)
+	}
+	
+	return b.String()
+}
\ No newline at end of file
@@ -0,0 +1,40 @@
+package analyzer
+
+import (
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{"plain text", "Plain text.", "Plain text."},
+		{"smart quotes", "\u201cHello,\u201d she said. \u2018It\u2019s fine.\u2019", `"Hello," she said. 'It's fine.'`},
+		{"non-breaking space", "new\u00a0york\u202fcity", "new york city"},
+		{"zero-width characters", "data\u200bbase\u200c and\ufeff\u2060 more", "database and more"},
+		{"control characters", "bell\a and\x00 null", "bell and null"},
+		{"whitespace runs", "  too   many \t spaces  ", "too many spaces"},
+		{"line breaks kept", "first line  \r\nsecond line", "first line\nsecond line"},
+		{"blank lines squeezed", "para one\n\n\n \n\npara two\n", "para one\n\npara two"},
+		{"nfc composition", "cafe\u0301", "caf\u00e9"},
+		{"only invisible characters", "\u200b\u00a0\ufeff", ""},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeText(tt.input))
+		})
+	}
+}
+
+func TestNormalizeText_KeywordCounts(t *testing.T) {
+	extractor := NewKeywordExtractor()
+	clean := "The server failed. The server restarted. The cluster recovered."
+	pasted := "The\u00a0server failed. The ser\u200bver restarted.  The cluster\u00a0recovered."
+	
+	assert.Equal(t, extractor.ExtractKeywords(clean, 2), extractor.ExtractKeywords(NormalizeText(pasted), 2))
+}
\ No newline at end of file
@@ -0,0 +1,100 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+var sentimentWordPattern = regexp.MustCompile(`[A-Za-z']+`)
+
+var positiveWords = map[string]bool{
+	"good": true, "great": true, "excellent": true, "amazing": true, "awesome": true,
+	"fantastic": true, "wonderful": true, "love": true, "loved": true, "like": true,
+	"happy": true, "glad": true, "pleased": true, "delighted": true, "enjoy": true,
+	"enjoyed": true, "best": true, "better": true, "improve": true, "improved": true,
+	"improvement": true, "success": true, "successful": true, "win": true, "won": true,
+	"benefit": true, "beneficial": true, "positive": true, "efficient": true, "reliable": true,
+	"impressive": true, "innovative": true, "helpful": true, "easy": true, "fast": true,
+	"growth": true, "gain": true, "gains": true, "profit": true, "strong": true,
+	"recommend": true, "perfect": true, "brilliant": true, "exciting": true, "excited": true,
+}
+
+var negativeWords = map[string]bool{
+	"bad": true, "terrible": true, "awful": true, "horrible": true, "poor": true,
+	"worst": true, "worse": true, "hate": true, "hated": true, "dislike": true,
+	"sad": true, "angry": true, "upset": true, "disappointed": true, "disappointing": true,
+	"fail": true, "failed": true, "failure": true, "broken": true, "bug": true,
+	"bugs": true, "crash": true, "crashed": true, "slow": true, "difficult": true,
+	"problem": true, "problems": true, "issue": true, "issues": true, "loss": true,
+	"losses": true, "decline": true, "declined": true, "weak": true, "risk": true,
+	"negative": true, "unreliable": true, "useless": true, "expensive": true, "wrong": true,
+	"error": true, "errors": true, "painful": true, "annoying": true, "frustrating": true,
+}
+
+var negationWords = map[string]bool{
+	"not": true, "no": true, "never": true, "nothing": true, "hardly": true, "without": true,
+}
+
+// negationWindow is how many following words a negator flips.
+const negationWindow = 3
+
+func sentimentWords(text string) []string {
+	return sentimentWordPattern.FindAllString(strings.ToLower(text), -1)
+}
+
+func isNegation(word string) bool {
+	return negationWords[word] || strings.HasSuffix(word, "n't")
+}
+
+// AnalyzeSentiment scores text against the bundled lexicon, returning a label
+// and a polarity in [-1, 1]. A negator ("not", "never", "isn't") flips the
+// polarity of sentiment words within the next few words.
+func AnalyzeSentiment(text string) (string, float64) {
+	var positive, negative int
+	negated := 0
+	
+	for _, word := range sentimentWords(text) {
+		if isNegation(word) {
+			negated = negationWindow
+			continue
+		}
+		
+		polarity := 0
+		if positiveWords[word] {
+			polarity = 1
+		} else if negativeWords[word] {
+			polarity = -1
+		}
+		
+		if polarity != 0 && negated > 0 {
+			polarity = -polarity
+			negated = 0
+		} else if negated > 0 {
+			negated--
+		}
+		
+		if polarity > 0 {
+			positive++
+		} else if polarity < 0 {
+			negative++
+		}
+	}
+	
+	if positive+negative == 0 {
+		return SentimentLabel(0), 0
+	}
+	
+	score := float64(positive-negative) / float64(positive+negative)
+	return SentimentLabel(score), score
+}
+
+func SentimentLabel(score float64) string {
+	switch {
+	case score > 0.1:
+		return "positive"
+	case score < -0.1:
+		return "negative"
+	default:
+		return "neutral"
+	}
+}
\ No newline at end of file
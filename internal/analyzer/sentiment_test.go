@@ -0,0 +1,78 @@
+package analyzer
+
+import (
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyzeSentiment(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		sign  int
+		label string
+	}{
+		{
+			name:  "Positive sentence",
+			text:  "The new release is excellent and the team did a great job.",
+			sign:  1,
+			label: "positive",
+		},
+		{
+			name:  "Negative sentence",
+			text:  "The update was terrible, with constant crashes and annoying bugs.",
+			sign:  -1,
+			label: "negative",
+		},
+		{
+			name:  "Neutral sentence",
+			text:  "The meeting is scheduled for Tuesday at noon.",
+			sign:  0,
+			label: "neutral",
+		},
+		{
+			name:  "Mixed sentence leaning positive",
+			text:  "Despite one problem, the launch was a great success and customers love it.",
+			sign:  1,
+			label: "positive",
+		},
+		{
+			name:  "Negated positive",
+			text:  "The service was not good at all.",
+			sign:  -1,
+			label: "negative",
+		},
+		{
+			name:  "Negated negative with contraction",
+			text:  "Honestly, the upgrade wasn't bad.",
+			sign:  1,
+			label: "positive",
+		},
+		{
+			name:  "Negation only reaches nearby words",
+			text:  "It is not the case that anyone expected this, but the result was great.",
+			sign:  1,
+			label: "positive",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			label, score := AnalyzeSentiment(tt.text)
+			assert.GreaterOrEqual(t, score, -1.0)
+			assert.LessOrEqual(t, score, 1.0)
+			
+			switch tt.sign {
+			case 1:
+				assert.Greater(t, score, 0.0)
+			case -1:
+				assert.Less(t, score, 0.0)
+			default:
+				assert.Equal(t, 0.0, score)
+			}
+			
+			assert.Equal(t, tt.label, label)
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,66 @@
+package analyzer
+
+import (
+	"bufio"
+	"log"
+	"os"
+	"strings"
+)
+
+// LoadStopWords reads one stop word per line, ignoring blank lines and lines
+// starting with '#'.
+func LoadStopWords(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		word := strings.ToLower(strings.TrimSpace(scanner.Text()))
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+	
+	return words, scanner.Err()
+}
+
+func (ke *KeywordExtractor) AddStopWords(words []string) int {
+	added := 0
+	for _, word := range words {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" && !ke.stopWords[word] {
+			ke.stopWords[word] = true
+			added++
+		}
+	}
+	return added
+}
+
+// NewKeywordExtractorWithStopWordsFile merges the stop words in path into the
+// built-in set. A missing or unreadable file is logged and the built-in set is
+// used on its own.
+func NewKeywordExtractorWithStopWordsFile(path string) *KeywordExtractor {
+	ke := NewKeywordExtractor()
+	ke.AddStopWords(loadStopWordsFile(path))
+	return ke
+}
+
+func loadStopWordsFile(path string) []string {
+	if path == "" {
+		return nil
+	}
+	
+	words, err := LoadStopWords(path)
+	if err != nil {
+		log.Printf("Warning: failed to load stop words from %s: %v", path, err)
+		return nil
+	}
+	
+	log.Printf("Loaded %d additional stop words from %s", len(words), path)
+	return words
+}
\ No newline at end of file
@@ -0,0 +1,42 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewKeywordExtractorWithStopWordsFile(t *testing.T) {
+	text := "The platform team shipped the platform migration. The deployment and monitoring improved the platform."
+	
+	baseline := NewKeywordExtractor().ExtractKeywords(text, 5)
+	require.Contains(t, baseline, "platform")
+	require.Contains(t, baseline, "deployment")
+	
+	path := filepath.Join(t.TempDir(), "stopwords.txt")
+	require.NoError(t, os.WriteFile(path, []byte("# project-specific noise\nPlatform\n\n  deployment  \n"), 0644))
+	
+	keywords := NewKeywordExtractorWithStopWordsFile(path).ExtractKeywords(text, 5)
+	assert.NotContains(t, keywords, "platform")
+	assert.NotContains(t, keywords, "deployment")
+	assert.NotEmpty(t, keywords)
+}
+
+func TestNewKeywordExtractorWithStopWordsFile_MissingFile(t *testing.T) {
+	ke := NewKeywordExtractorWithStopWordsFile(filepath.Join(t.TempDir(), "missing.txt"))
+	
+	require.NotNil(t, ke)
+	assert.Equal(t, NewKeywordExtractor().stopWords, ke.stopWords)
+}
+
+func TestLoadStopWords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stopwords.txt")
+	require.NoError(t, os.WriteFile(path, []byte("alpha\n# comment\n\nBeta\n"), 0644))
+	
+	words, err := LoadStopWords(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"alpha", "beta"}, words)
+}
\ No newline at end of file
@@ -0,0 +1,83 @@
+package analyzer
+
+import (
+	"regexp"
+	"strings"
+)
+
+var topicSeparatorPattern = regexp.MustCompile(`[\s_-]+`)
+
+// NormalizeTopic lowercases and trims topic and collapses runs of spaces,
+// hyphens and underscores into a single space.
+func NormalizeTopic(topic string) string {
+	topic = strings.ToLower(strings.TrimSpace(topic))
+	topic = topicSeparatorPattern.ReplaceAllString(topic, " ")
+	return strings.TrimSpace(topic)
+}
+
+type TopicNormalizer struct {
+	aliases map[string]string
+}
+
+func NewTopicNormalizer(aliases map[string]string) *TopicNormalizer {
+	normalized := make(map[string]string, len(aliases))
+	for alias, canonical := range aliases {
+		if key := NormalizeTopic(alias); key != "" {
+			normalized[key] = NormalizeTopic(canonical)
+		}
+	}
+	return &TopicNormalizer{aliases: normalized}
+}
+
+func (n *TopicNormalizer) Normalize(topic string) string {
+	topic = NormalizeTopic(topic)
+	if canonical, ok := n.aliases[topic]; ok {
+		return canonical
+	}
+	return topic
+}
+
+// NormalizeAll normalizes each topic, dropping empties and duplicates that
+// only differed before canonicalization.
+func (n *TopicNormalizer) NormalizeAll(topics []string) []string {
+	result := make([]string, 0, len(topics))
+	seen := make(map[string]bool, len(topics))
+	
+	for _, topic := range topics {
+		topic = n.Normalize(topic)
+		if topic == "" || seen[topic] {
+			continue
+		}
+		seen[topic] = true
+		result = append(result, topic)
+	}
+	
+	return result
+}
+
+// SuppressTopicKeywords drops keywords that duplicate a topic once both are
+// normalized. candidates must be ranked best first; with backfill the next
+// candidates fill the freed slots so up to n keywords are still returned,
+// otherwise only the first n candidates are considered.
+func SuppressTopicKeywords(candidates, topics []string, n int, backfill bool) []string {
+	if !backfill && len(candidates) > n {
+		candidates = candidates[:n]
+	}
+	
+	topicSet := make(map[string]bool, len(topics))
+	for _, topic := range topics {
+		topicSet[NormalizeTopic(topic)] = true
+	}
+	
+	result := make([]string, 0, n)
+	for _, keyword := range candidates {
+		if len(result) == n {
+			break
+		}
+		if !topicSet[NormalizeTopic(keyword)] {
+			result = append(result, keyword)
+		}
+	}
+	
+	return result
+}
\ No newline at end of file
@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTopic(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Machine Learning", "machine learning"},
+		{"machine-learning", "machine learning"},
+		{"  Machine__Learning  ", "machine learning"},
+		{"deep - learning", "deep learning"},
+		{"C++", "c++"},
+		{"   ", ""},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, NormalizeTopic(tt.input))
+		})
+	}
+}
+
+func TestTopicNormalizer(t *testing.T) {
+	normalizer := NewTopicNormalizer(map[string]string{
+		"ML": "Machine Learning",
+		"ai": "artificial-intelligence",
+	})
+	
+	assert.Equal(t, "machine learning", normalizer.Normalize("ml"))
+	assert.Equal(t, "artificial intelligence", normalizer.Normalize(" AI "))
+	assert.Equal(t, "robotics", normalizer.Normalize("Robotics"))
+	
+	assert.Equal(t,
+		[]string{"machine learning", "robotics"},
+		normalizer.NormalizeAll([]string{"Machine Learning", "machine-learning", "ML", "", "Robotics"}),
+	)
+}
+
+func TestSuppressTopicKeywords(t *testing.T) {
+	candidates := []string{"Machine_Learning", "deployment", "Cluster", "pipeline", "server"}
+	topics := []string{"machine learning", "cluster"}
+	
+	assert.Equal(t, []string{"deployment", "pipeline", "server"}, SuppressTopicKeywords(candidates, topics, 3, true))
+	assert.Equal(t, []string{"deployment"}, SuppressTopicKeywords(candidates, topics, 3, false))
+	assert.Equal(t, []string{"deployment", "pipeline"}, SuppressTopicKeywords(candidates[:4], topics, 3, true))
+	assert.Equal(t, []string{"Machine_Learning", "deployment", "Cluster"}, SuppressTopicKeywords(candidates, nil, 3, true))
+}
\ No newline at end of file
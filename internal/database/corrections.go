@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func (db *DB) CorrectAnalysis(analysis *models.TextAnalysis, correction *models.Correction) error {
+	return db.CorrectAnalysisContext(context.Background(), analysis, correction)
+}
+
+// CorrectAnalysisContext saves an analysis whose metadata an analyst has
+// corrected, the same way UpdateAnalysisContext does, and logs correction in
+// the same transaction. It sets correction.ID and returns ErrNotFound if the
+// analysis is missing.
+func (db *DB) CorrectAnalysisContext(ctx context.Context, analysis *models.TextAnalysis, correction *models.Correction) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	if err := db.updateAnalysis(ctx, tx, analysis); err != nil {
+		return err
+	}
+	
+	result, err := tx.ExecContext(
+		ctx,
+		"INSERT INTO corrections (analysis_id, field, original, corrected, corrected_by, created_at) VALUES (?, ?, ?, ?, ?, ?)",
+		correction.AnalysisID,
+		correction.Field,
+		correction.Original,
+		correction.Corrected,
+		correction.CorrectedBy,
+		formatTimestamp(correction.CreatedAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save correction: %w", err)
+	}
+	if correction.ID, err = result.LastInsertId(); err != nil {
+		return fmt.Errorf("failed to read correction id: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit correction: %w", err)
+	}
+	db.stats.invalidate()
+	
+	return nil
+}
+
+func (db *DB) GetCorrections(analysisID string) ([]*models.Correction, error) {
+	return db.GetCorrectionsContext(context.Background(), analysisID)
+}
+
+// GetCorrectionsContext returns the corrections made to an analysis, oldest
+// first.
+func (db *DB) GetCorrectionsContext(ctx context.Context, analysisID string) ([]*models.Correction, error) {
+	rows, err := db.conn.QueryContext(
+		ctx,
+		"SELECT id, analysis_id, field, original, corrected, corrected_by, created_at FROM corrections WHERE analysis_id = ? ORDER BY created_at, id",
+		analysisID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query corrections: %w", err)
+	}
+	defer rows.Close()
+	
+	corrections := make([]*models.Correction, 0)
+	for rows.Next() {
+		var correction models.Correction
+		err := rows.Scan(
+			&correction.ID,
+			&correction.AnalysisID,
+			&correction.Field,
+			&correction.Original,
+			&correction.Corrected,
+			&correction.CorrectedBy,
+			timestamp{&correction.CreatedAt},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan correction: %w", err)
+		}
+		corrections = append(corrections, &correction)
+	}
+	
+	return corrections, rows.Err()
+}
\ No newline at end of file
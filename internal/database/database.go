@@ -1,38 +1,248 @@
 package database
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 	
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/user/llm-knowledge-extractor/internal/models"
 )
 
+var (
+	ErrInvalidCursor   = errors.New("invalid pagination cursor")
+	ErrInvalidPath     = errors.New("invalid database path")
+	ErrNotFound        = errors.New("analysis not found")
+	ErrInvalidInterval = errors.New("invalid timeline interval")
+)
+
+// timelineBuckets maps each timeline interval to the SQL expression giving
+// the UTC start date of a row's bucket. Weeks start on Monday.
+var timelineBuckets = map[string]string{
+	"day":   "strftime('%Y-%m-%d', created_at)",
+	"week":  "date(created_at, 'weekday 0', '-6 days')",
+	"month": "strftime('%Y-%m-01', created_at)",
+}
+
+// MemoryPath opens a private in-memory database; its contents are lost on Close.
+const MemoryPath = ":memory:"
+
 type DB struct {
 	conn *sql.DB
+	// read serves GetAnalysis, searches and stats: a query-only connection
+	// to Options.ReadPath when set, otherwise conn.
+	read *sql.DB
+	
+	insertAnalysisStmt *sql.Stmt
+	insertTagStmt      *sql.Stmt
+	getAnalysisStmt    *sql.Stmt
+	
+	stats statsCache
+}
+
+const (
+	insertAnalysisQuery = `
+		INSERT INTO analyses (id, text, summary, metadata, confidence, created_at, processing_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	insertTagQuery   = "INSERT OR IGNORE INTO tags (analysis_id, tag) VALUES (?, ?)"
+	getAnalysisQuery = `
+		SELECT id, text, summary, metadata, confidence, created_at, processing_ms
+		FROM analyses
+		WHERE id = ?
+	`
+)
+
+type Options struct {
+	BusyTimeout  time.Duration
+	JournalMode  string
+	MaxOpenConns int
+	// StatsCacheTTL is how long a GetStats result is reused; any save,
+	// update or delete drops it early. Zero disables the cache.
+	StatsCacheTTL time.Duration
+	// ReadPath is an optional read replica, a path or file: DSN like the
+	// primary's. It is opened query-only and must already hold the schema;
+	// empty sends reads to the primary.
+	ReadPath string
+	
+	queryOnly bool
+}
+
+func DefaultOptions() Options {
+	return Options{
+		BusyTimeout:   5 * time.Second,
+		JournalMode:   "WAL",
+		MaxOpenConns:  4,
+		StatsCacheTTL: 5 * time.Second,
+	}
 }
 
 func New(dbPath string) (*DB, error) {
-	conn, err := sql.Open("sqlite3", dbPath)
+	return NewWithOptions(dbPath, DefaultOptions())
+}
+
+func NewWithOptions(dbPath string, opts Options) (*DB, error) {
+	if err := validatePath(dbPath); err != nil {
+		return nil, err
+	}
+	
+	// Every connection to :memory: gets its own empty database, so the pool
+	// must be pinned to a single connection.
+	if dbPath == MemoryPath {
+		opts.MaxOpenConns = 1
+	}
+	
+	conn, err := sql.Open("sqlite3", buildDSN(dbPath, opts))
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
 	
+	if opts.MaxOpenConns > 0 {
+		conn.SetMaxOpenConns(opts.MaxOpenConns)
+		conn.SetMaxIdleConns(opts.MaxOpenConns)
+	}
+	
 	if err := conn.Ping(); err != nil {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 	
-	db := &DB{conn: conn}
+	db := &DB{conn: conn, read: conn, stats: statsCache{ttl: opts.StatsCacheTTL}}
 	
 	if err := db.createTables(); err != nil {
 		return nil, fmt.Errorf("failed to create tables: %w", err)
 	}
 	
+	if err := db.migrateTimestamps(); err != nil {
+		return nil, err
+	}
+	
+	if opts.ReadPath != "" {
+		if db.read, err = openReadReplica(opts); err != nil {
+			db.Close()
+			return nil, err
+		}
+	}
+	
+	if err := db.prepareStatements(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to prepare statements: %w", err)
+	}
+	
 	return db, nil
 }
 
+// openReadReplica opens the query-only connection to opts.ReadPath. It is
+// never migrated, so the journal mode is left to whatever maintains it.
+func openReadReplica(opts Options) (*sql.DB, error) {
+	if opts.ReadPath == MemoryPath {
+		return nil, fmt.Errorf("%w: read replica cannot be %s", ErrInvalidPath, MemoryPath)
+	}
+	if !strings.HasPrefix(opts.ReadPath, "file:") {
+		if _, err := os.Stat(opts.ReadPath); err != nil {
+			return nil, fmt.Errorf("%w: read replica: %v", ErrInvalidPath, err)
+		}
+	}
+	
+	readOpts := Options{BusyTimeout: opts.BusyTimeout, queryOnly: true}
+	conn, err := sql.Open("sqlite3", buildDSN(opts.ReadPath, readOpts))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open read replica: %w", err)
+	}
+	if opts.MaxOpenConns > 0 {
+		conn.SetMaxOpenConns(opts.MaxOpenConns)
+		conn.SetMaxIdleConns(opts.MaxOpenConns)
+	}
+	if err := conn.Ping(); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to ping read replica: %w", err)
+	}
+	return conn, nil
+}
+
+func validatePath(dbPath string) error {
+	if dbPath == "" {
+		return fmt.Errorf("%w: path is empty", ErrInvalidPath)
+	}
+	if dbPath == MemoryPath || strings.HasPrefix(dbPath, "file:") {
+		return nil
+	}
+	
+	info, err := os.Stat(dbPath)
+	switch {
+	case err == nil && info.IsDir():
+		return fmt.Errorf("%w: %s is a directory", ErrInvalidPath, dbPath)
+	case err == nil:
+		f, err := os.OpenFile(dbPath, os.O_RDWR, 0)
+		if err != nil {
+			return fmt.Errorf("%w: %s is not writable: %v", ErrInvalidPath, dbPath, err)
+		}
+		return f.Close()
+	case !os.IsNotExist(err):
+		return fmt.Errorf("%w: %v", ErrInvalidPath, err)
+	}
+	
+	dir := filepath.Dir(dbPath)
+	if info, err := os.Stat(dir); err != nil {
+		return fmt.Errorf("%w: directory %s: %v", ErrInvalidPath, dir, err)
+	} else if !info.IsDir() {
+		return fmt.Errorf("%w: %s is not a directory", ErrInvalidPath, dir)
+	}
+	
+	probe, err := os.CreateTemp(dir, ".write-check-*")
+	if err != nil {
+		return fmt.Errorf("%w: directory %s is not writable: %v", ErrInvalidPath, dir, err)
+	}
+	probe.Close()
+	return os.Remove(probe.Name())
+}
+
+func (db *DB) prepareStatements() error {
+	var err error
+	
+	if db.insertAnalysisStmt, err = db.conn.Prepare(insertAnalysisQuery); err != nil {
+		return err
+	}
+	if db.insertTagStmt, err = db.conn.Prepare(insertTagQuery); err != nil {
+		return err
+	}
+	if db.getAnalysisStmt, err = db.read.Prepare(getAnalysisQuery); err != nil {
+		return err
+	}
+	
+	return nil
+}
+
+func buildDSN(dbPath string, opts Options) string {
+	params := url.Values{}
+	if opts.BusyTimeout > 0 {
+		params.Set("_busy_timeout", strconv.FormatInt(opts.BusyTimeout.Milliseconds(), 10))
+	}
+	if opts.JournalMode != "" {
+		params.Set("_journal_mode", opts.JournalMode)
+	}
+	if opts.queryOnly {
+		params.Set("_query_only", "true")
+	}
+	
+	if len(params) == 0 {
+		return dbPath
+	}
+	
+	separator := "?"
+	if strings.Contains(dbPath, "?") {
+		separator = "&"
+	}
+	return dbPath + separator + params.Encode()
+}
+
 func (db *DB) createTables() error {
 	query := `
 	CREATE TABLE IF NOT EXISTS analyses (
@@ -47,6 +257,58 @@ func (db *DB) createTables() error {
 	
 	CREATE INDEX IF NOT EXISTS idx_created_at ON analyses(created_at);
 	CREATE INDEX IF NOT EXISTS idx_confidence ON analyses(confidence);
+	
+	CREATE TABLE IF NOT EXISTS tags (
+		analysis_id TEXT NOT NULL,
+		tag TEXT NOT NULL,
+		PRIMARY KEY (analysis_id, tag)
+	);
+	
+	CREATE INDEX IF NOT EXISTS idx_tags_tag ON tags(tag);
+	
+	CREATE TABLE IF NOT EXISTS idempotency_keys (
+		key TEXT PRIMARY KEY,
+		analysis_id TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	);
+	
+	CREATE TABLE IF NOT EXISTS analysis_versions (
+		analysis_id TEXT NOT NULL,
+		version INTEGER NOT NULL,
+		summary TEXT NOT NULL,
+		metadata TEXT NOT NULL,
+		confidence REAL NOT NULL,
+		created_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (analysis_id, version)
+	);
+	
+	CREATE TABLE IF NOT EXISTS analysis_chunks (
+		analysis_id TEXT NOT NULL,
+		position INTEGER NOT NULL,
+		chunk_id TEXT NOT NULL,
+		PRIMARY KEY (analysis_id, position)
+	);
+	
+	CREATE TABLE IF NOT EXISTS notes (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		analysis_id TEXT NOT NULL,
+		note TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	);
+	
+	CREATE INDEX IF NOT EXISTS idx_notes_analysis_id ON notes(analysis_id);
+	
+	CREATE TABLE IF NOT EXISTS corrections (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		analysis_id TEXT NOT NULL,
+		field TEXT NOT NULL,
+		original TEXT NOT NULL,
+		corrected TEXT NOT NULL,
+		corrected_by TEXT NOT NULL,
+		created_at TIMESTAMP NOT NULL
+	);
+	
+	CREATE INDEX IF NOT EXISTS idx_corrections_analysis_id ON corrections(analysis_id);
 	`
 	
 	_, err := db.conn.Exec(query)
@@ -54,24 +316,101 @@ func (db *DB) createTables() error {
 }
 
 func (db *DB) SaveAnalysis(analysis *models.TextAnalysis) error {
-	metadataJSON, err := json.Marshal(analysis.Metadata)
+	return db.SaveAnalysisContext(context.Background(), analysis)
+}
+
+func (db *DB) SaveAnalysisContext(ctx context.Context, analysis *models.TextAnalysis) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("failed to marshal metadata: %w", err)
+		return fmt.Errorf("failed to begin transaction: %w", err)
 	}
+	defer tx.Rollback()
 	
-	query := `
-		INSERT INTO analyses (id, text, summary, metadata, confidence, created_at, processing_ms)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`
+	if err := db.insertAnalysis(ctx, tx, analysis); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit analysis: %w", err)
+	}
+	db.stats.invalidate()
+	
+	return nil
+}
+
+func (db *DB) SaveChunkedAnalysis(analysis *models.TextAnalysis, chunks []*models.TextAnalysis) error {
+	return db.SaveChunkedAnalysisContext(context.Background(), analysis, chunks)
+}
+
+// SaveChunkedAnalysisContext saves the analysis of every chunk and the
+// aggregated analysis built from them in one transaction, linking each chunk
+// to the aggregate in order.
+func (db *DB) SaveChunkedAnalysisContext(ctx context.Context, analysis *models.TextAnalysis, chunks []*models.TextAnalysis) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	if err := db.insertAnalysis(ctx, tx, analysis); err != nil {
+		return err
+	}
+	for position, chunk := range chunks {
+		if err := db.insertAnalysis(ctx, tx, chunk); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO analysis_chunks (analysis_id, position, chunk_id) VALUES (?, ?, ?)", analysis.ID, position, chunk.ID); err != nil {
+			return fmt.Errorf("failed to link chunk: %w", err)
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit chunked analysis: %w", err)
+	}
+	db.stats.invalidate()
+	
+	return nil
+}
+
+func (db *DB) GetChunkIDs(id string) ([]string, error) {
+	return db.GetChunkIDsContext(context.Background(), id)
+}
+
+// GetChunkIDsContext returns the IDs of the chunk analyses an aggregated
+// analysis was built from, in chunk order. It is empty for other analyses.
+func (db *DB) GetChunkIDsContext(ctx context.Context, id string) ([]string, error) {
+	rows, err := db.conn.QueryContext(ctx, "SELECT chunk_id FROM analysis_chunks WHERE analysis_id = ? ORDER BY position", id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query chunks: %w", err)
+	}
+	defer rows.Close()
 	
-	_, err = db.conn.Exec(
-		query,
+	ids := make([]string, 0)
+	for rows.Next() {
+		var chunkID string
+		if err := rows.Scan(&chunkID); err != nil {
+			return nil, fmt.Errorf("failed to scan chunk: %w", err)
+		}
+		ids = append(ids, chunkID)
+	}
+	
+	return ids, rows.Err()
+}
+
+func (db *DB) insertAnalysis(ctx context.Context, tx *sql.Tx, analysis *models.TextAnalysis) error {
+	metadataJSON, err := encodeMetadata(analysis)
+	if err != nil {
+		return err
+	}
+	
+	_, err = tx.StmtContext(ctx, db.insertAnalysisStmt).ExecContext(
+		ctx,
 		analysis.ID,
 		analysis.Text,
 		analysis.Summary,
-		string(metadataJSON),
+		metadataJSON,
 		analysis.Confidence,
-		analysis.CreatedAt,
+		formatTimestamp(analysis.CreatedAt),
 		analysis.ProcessingMS,
 	)
 	
@@ -79,26 +418,168 @@ func (db *DB) SaveAnalysis(analysis *models.TextAnalysis) error {
 		return fmt.Errorf("failed to insert analysis: %w", err)
 	}
 	
+	insertTag := tx.StmtContext(ctx, db.insertTagStmt)
+	for _, tag := range analysis.MetadataStrings("tags") {
+		if _, err := insertTag.ExecContext(ctx, analysis.ID, tag); err != nil {
+			return fmt.Errorf("failed to insert tag: %w", err)
+		}
+	}
+	
 	return nil
 }
 
-func (db *DB) GetAnalysis(id string) (*models.TextAnalysis, error) {
-	query := `
-		SELECT id, text, summary, metadata, confidence, created_at, processing_ms
-		FROM analyses
-		WHERE id = ?
-	`
+func (db *DB) UpdateAnalysis(analysis *models.TextAnalysis) error {
+	return db.UpdateAnalysisContext(context.Background(), analysis)
+}
+
+// UpdateAnalysisContext replaces the summary, metadata, confidence and
+// processing time of an existing analysis, first copying the current values
+// into analysis_versions. It returns ErrNotFound if the analysis is missing.
+func (db *DB) UpdateAnalysisContext(ctx context.Context, analysis *models.TextAnalysis) error {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	if err := db.updateAnalysis(ctx, tx, analysis); err != nil {
+		return err
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit analysis update: %w", err)
+	}
+	db.stats.invalidate()
+	
+	return nil
+}
+
+func (db *DB) updateAnalysis(ctx context.Context, tx *sql.Tx, analysis *models.TextAnalysis) error {
+	metadataJSON, err := encodeMetadata(analysis)
+	if err != nil {
+		return err
+	}
+	
+	result, err := tx.ExecContext(
+		ctx,
+		`INSERT INTO analysis_versions (analysis_id, version, summary, metadata, confidence, created_at)
+		SELECT id, (SELECT COALESCE(MAX(version), 0) + 1 FROM analysis_versions WHERE analysis_id = ?),
+			summary, metadata, confidence, ?
+		FROM analyses WHERE id = ?`,
+		analysis.ID,
+		formatTimestamp(time.Now()),
+		analysis.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to snapshot analysis: %w", err)
+	}
+	if n, err := result.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to snapshot analysis: %w", err)
+	} else if n == 0 {
+		return ErrNotFound
+	}
+	
+	_, err = tx.ExecContext(
+		ctx,
+		"UPDATE analyses SET summary = ?, metadata = ?, confidence = ?, processing_ms = ? WHERE id = ?",
+		analysis.Summary,
+		metadataJSON,
+		analysis.Confidence,
+		analysis.ProcessingMS,
+		analysis.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update analysis: %w", err)
+	}
 	
+	if _, err := tx.ExecContext(ctx, "DELETE FROM tags WHERE analysis_id = ?", analysis.ID); err != nil {
+		return fmt.Errorf("failed to clear tags: %w", err)
+	}
+	insertTag := tx.StmtContext(ctx, db.insertTagStmt)
+	for _, tag := range analysis.MetadataStrings("tags") {
+		if _, err := insertTag.ExecContext(ctx, analysis.ID, tag); err != nil {
+			return fmt.Errorf("failed to insert tag: %w", err)
+		}
+	}
+	
+	return nil
+}
+
+func (db *DB) GetAnalysisHistory(id string) ([]*models.AnalysisVersion, error) {
+	return db.GetAnalysisHistoryContext(context.Background(), id)
+}
+
+// GetAnalysisHistoryContext returns the snapshots taken before each update,
+// newest first. The current state lives in the analyses row, not here.
+func (db *DB) GetAnalysisHistoryContext(ctx context.Context, id string) ([]*models.AnalysisVersion, error) {
+	rows, err := db.conn.QueryContext(
+		ctx,
+		`SELECT analysis_id, version, summary, metadata, confidence, created_at
+		FROM analysis_versions
+		WHERE analysis_id = ?
+		ORDER BY version DESC`,
+		id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query analysis history: %w", err)
+	}
+	defer rows.Close()
+	
+	versions := make([]*models.AnalysisVersion, 0)
+	for rows.Next() {
+		var version models.AnalysisVersion
+		var metadataJSON string
+		
+		if err := rows.Scan(
+			&version.AnalysisID,
+			&version.Version,
+			&version.Summary,
+			&metadataJSON,
+			&version.Confidence,
+			timestamp{&version.CreatedAt},
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan analysis version: %w", err)
+		}
+		
+		if version.Metadata, err = decodeMetadata(metadataJSON); err != nil {
+			return nil, err
+		}
+		
+		versions = append(versions, &version)
+	}
+	
+	return versions, rows.Err()
+}
+
+func (db *DB) GetAnalysis(id string) (*models.TextAnalysis, error) {
+	return db.GetAnalysisContext(context.Background(), id)
+}
+
+func (db *DB) GetAnalysisContext(ctx context.Context, id string) (*models.TextAnalysis, error) {
+	return scanAnalysisRow(db.getAnalysisStmt.QueryRowContext(ctx, id))
+}
+
+func (db *DB) GetAnalysisPrimary(id string) (*models.TextAnalysis, error) {
+	return db.GetAnalysisPrimaryContext(context.Background(), id)
+}
+
+// GetAnalysisPrimaryContext is GetAnalysisContext against the primary, for
+// callers that must see their own writes even when a read replica lags.
+func (db *DB) GetAnalysisPrimaryContext(ctx context.Context, id string) (*models.TextAnalysis, error) {
+	return scanAnalysisRow(db.conn.QueryRowContext(ctx, getAnalysisQuery, id))
+}
+
+func scanAnalysisRow(row *sql.Row) (*models.TextAnalysis, error) {
 	var analysis models.TextAnalysis
 	var metadataJSON string
 	
-	err := db.conn.QueryRow(query, id).Scan(
+	err := row.Scan(
 		&analysis.ID,
 		&analysis.Text,
 		&analysis.Summary,
 		&metadataJSON,
 		&analysis.Confidence,
-		&analysis.CreatedAt,
+		timestamp{&analysis.CreatedAt},
 		&analysis.ProcessingMS,
 	)
 	
@@ -109,16 +590,77 @@ func (db *DB) GetAnalysis(id string) (*models.TextAnalysis, error) {
 		return nil, fmt.Errorf("failed to query analysis: %w", err)
 	}
 	
-	if err := json.Unmarshal([]byte(metadataJSON), &analysis.Metadata); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	if analysis.Metadata, err = decodeMetadata(metadataJSON); err != nil {
+		return nil, err
 	}
 	
 	return &analysis, nil
 }
 
 func (db *DB) SearchAnalyses(query models.SearchQuery) ([]*models.TextAnalysis, error) {
-	var conditions []string
-	var args []interface{}
+	return db.SearchAnalysesContext(context.Background(), query)
+}
+
+func (db *DB) SearchAnalysesContext(ctx context.Context, query models.SearchQuery) ([]*models.TextAnalysis, error) {
+	return collectAnalyses(db.searchStream(ctx, db.read, query))
+}
+
+// SearchAnalysesPrimaryContext is SearchAnalysesContext against the primary,
+// for callers that rewrite the analyses they find.
+func (db *DB) SearchAnalysesPrimaryContext(ctx context.Context, query models.SearchQuery) ([]*models.TextAnalysis, error) {
+	return collectAnalyses(db.searchStream(ctx, db.conn, query))
+}
+
+func collectAnalyses(stream AnalysisStream, err error) ([]*models.TextAnalysis, error) {
+	if err != nil {
+		return nil, err
+	}
+	
+	// An empty slice, not nil, so no matches encode as [] rather than null.
+	results := []*models.TextAnalysis{}
+	var streamErr error
+	
+	stream(func(analysis *models.TextAnalysis, err error) bool {
+		if err != nil {
+			streamErr = err
+			return false
+		}
+		results = append(results, analysis)
+		return true
+	})
+	
+	if streamErr != nil {
+		return nil, streamErr
+	}
+	
+	return results, nil
+}
+
+// AnalysisStream yields search results one row at a time. A non-nil error is
+// yielded at most once and ends the stream.
+type AnalysisStream func(yield func(*models.TextAnalysis, error) bool)
+
+// SearchAnalysesStream runs the query up front so errors surface before any
+// output is written. The returned stream must be called once to release rows.
+func (db *DB) SearchAnalysesStream(query models.SearchQuery) (AnalysisStream, error) {
+	return db.SearchAnalysesStreamContext(context.Background(), query)
+}
+
+func (db *DB) SearchAnalysesStreamContext(ctx context.Context, query models.SearchQuery) (AnalysisStream, error) {
+	return db.searchStream(ctx, db.read, query)
+}
+
+func (db *DB) searchStream(ctx context.Context, conn *sql.DB, query models.SearchQuery) (AnalysisStream, error) {
+	conditions, args := filterConditions(query.SearchFilter)
+	
+	if query.Cursor != "" {
+		createdAt, id, err := DecodeCursor(query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "(created_at < ? OR (created_at = ? AND id < ?))")
+		args = append(args, formatTimestamp(createdAt), formatTimestamp(createdAt), id)
+	}
 	
 	baseQuery := `
 		SELECT id, text, summary, metadata, confidence, created_at, processing_ms
@@ -126,22 +668,11 @@ func (db *DB) SearchAnalyses(query models.SearchQuery) ([]*models.TextAnalysis,
 		WHERE 1=1
 	`
 	
-	if query.Topic != "" {
-		conditions = append(conditions, "metadata LIKE ?")
-		args = append(args, "%\""+query.Topic+"\"%")
-	}
-	
-	if query.Keyword != "" {
-		conditions = append(conditions, "(text LIKE ? OR summary LIKE ? OR metadata LIKE ?)")
-		keyword := "%" + query.Keyword + "%"
-		args = append(args, keyword, keyword, keyword)
-	}
-	
 	if len(conditions) > 0 {
 		baseQuery += " AND " + strings.Join(conditions, " AND ")
 	}
 	
-	baseQuery += " ORDER BY created_at DESC"
+	baseQuery += " ORDER BY created_at DESC, id DESC"
 	
 	if query.Limit > 0 {
 		baseQuery += " LIMIT ?"
@@ -149,64 +680,293 @@ func (db *DB) SearchAnalyses(query models.SearchQuery) ([]*models.TextAnalysis,
 	}
 	
 	if query.Offset > 0 {
+		if query.Limit <= 0 {
+			baseQuery += " LIMIT -1"
+		}
 		baseQuery += " OFFSET ?"
 		args = append(args, query.Offset)
 	}
 	
-	rows, err := db.conn.Query(baseQuery, args...)
+	rows, err := conn.QueryContext(ctx, baseQuery, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search analyses: %w", err)
 	}
-	defer rows.Close()
 	
-	var results []*models.TextAnalysis
-	
-	for rows.Next() {
-		var analysis models.TextAnalysis
-		var metadataJSON string
+	return func(yield func(*models.TextAnalysis, error) bool) {
+		defer rows.Close()
 		
-		err := rows.Scan(
-			&analysis.ID,
-			&analysis.Text,
-			&analysis.Summary,
-			&metadataJSON,
-			&analysis.Confidence,
-			&analysis.CreatedAt,
-			&analysis.ProcessingMS,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan row: %w", err)
+		for rows.Next() {
+			analysis, err := scanAnalysis(rows)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			
+			if !yield(analysis, nil) {
+				return
+			}
 		}
 		
-		if err := json.Unmarshal([]byte(metadataJSON), &analysis.Metadata); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+		if err := rows.Err(); err != nil {
+			yield(nil, fmt.Errorf("failed to iterate rows: %w", err))
 		}
-		
-		results = append(results, &analysis)
+	}, nil
+}
+
+func EncodeCursor(analysis *models.TextAnalysis) string {
+	raw := analysis.CreatedAt.Format(time.RFC3339Nano) + "|" + analysis.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(cursor string) (time.Time, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("%w: %v", ErrInvalidCursor, err)
 	}
 	
-	return results, nil
+	createdAtStr, id, ok := strings.Cut(string(raw), "|")
+	if !ok || id == "" {
+		return time.Time{}, "", ErrInvalidCursor
+	}
+	
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtStr)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("%w: %v", ErrInvalidCursor, err)
+	}
+	
+	return createdAt, id, nil
+}
+
+func scanAnalysis(rows *sql.Rows) (*models.TextAnalysis, error) {
+	var analysis models.TextAnalysis
+	var metadataJSON string
+	
+	err := rows.Scan(
+		&analysis.ID,
+		&analysis.Text,
+		&analysis.Summary,
+		&metadataJSON,
+		&analysis.Confidence,
+		timestamp{&analysis.CreatedAt},
+		&analysis.ProcessingMS,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan row: %w", err)
+	}
+	
+	if analysis.Metadata, err = decodeMetadata(metadataJSON); err != nil {
+		return nil, err
+	}
+	
+	return &analysis, nil
+}
+
+func (db *DB) DeleteAnalyses(filter models.SearchFilter) (int64, error) {
+	return db.DeleteAnalysesContext(context.Background(), filter)
+}
+
+func (db *DB) DeleteAnalysesContext(ctx context.Context, filter models.SearchFilter) (int64, error) {
+	conditions, args := filterConditions(filter)
+	
+	query := "DELETE FROM analyses WHERE 1=1"
+	if len(conditions) > 0 {
+		query += " AND " + strings.Join(conditions, " AND ")
+	}
+	
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	result, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete analyses: %w", err)
+	}
+	
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to count deleted analyses: %w", err)
+	}
+	
+	if _, err := tx.ExecContext(ctx, "DELETE FROM tags WHERE analysis_id NOT IN (SELECT id FROM analyses)"); err != nil {
+		return 0, fmt.Errorf("failed to delete tags: %w", err)
+	}
+	
+	if _, err := tx.ExecContext(ctx, "DELETE FROM analysis_versions WHERE analysis_id NOT IN (SELECT id FROM analyses)"); err != nil {
+		return 0, fmt.Errorf("failed to delete analysis versions: %w", err)
+	}
+	
+	if _, err := tx.ExecContext(ctx, "DELETE FROM analysis_chunks WHERE analysis_id NOT IN (SELECT id FROM analyses) OR chunk_id NOT IN (SELECT id FROM analyses)"); err != nil {
+		return 0, fmt.Errorf("failed to delete chunk links: %w", err)
+	}
+	
+	if _, err := tx.ExecContext(ctx, "DELETE FROM notes WHERE analysis_id NOT IN (SELECT id FROM analyses)"); err != nil {
+		return 0, fmt.Errorf("failed to delete notes: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, "DELETE FROM corrections WHERE analysis_id NOT IN (SELECT id FROM analyses)"); err != nil {
+		return 0, fmt.Errorf("failed to delete corrections: %w", err)
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit delete: %w", err)
+	}
+	db.stats.invalidate()
+	
+	return deleted, nil
+}
+
+func filterConditions(filter models.SearchFilter) ([]string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+	
+	if filter.Topic != "" {
+		conditions = append(conditions, "metadata LIKE ?")
+		args = append(args, "%\""+filter.Topic+"\"%")
+	}
+	
+	if filter.Keyword != "" {
+		conditions = append(conditions, "(text LIKE ? OR summary LIKE ? OR metadata LIKE ?)")
+		keyword := "%" + filter.Keyword + "%"
+		args = append(args, keyword, keyword, keyword)
+	}
+	
+	for _, tag := range filter.Tags {
+		conditions = append(conditions, "id IN (SELECT analysis_id FROM tags WHERE tag = ?)")
+		args = append(args, strings.ToLower(strings.TrimSpace(tag)))
+	}
+	
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, formatTimestamp(filter.From))
+	}
+	
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, formatTimestamp(filter.To))
+	}
+	
+	if filter.MinSentimentScore != nil {
+		conditions = append(conditions, "json_extract(metadata, '$.sentiment_score') >= ?")
+		args = append(args, *filter.MinSentimentScore)
+	}
+	
+	if filter.MaxSentimentScore != nil {
+		conditions = append(conditions, "json_extract(metadata, '$.sentiment_score') <= ?")
+		args = append(args, *filter.MaxSentimentScore)
+	}
+	
+	if filter.Sentiment != "" {
+		conditions = append(conditions, "json_extract(metadata, '$.sentiment') = ?")
+		args = append(args, filter.Sentiment)
+	}
+	
+	if filter.Provider != "" {
+		conditions = append(conditions, "json_extract(metadata, '$.provider') = ?")
+		args = append(args, filter.Provider)
+	}
+	
+	if filter.MinConfidence != nil {
+		conditions = append(conditions, "confidence >= ?")
+		args = append(args, *filter.MinConfidence)
+	}
+	
+	if filter.MaxConfidence != nil {
+		conditions = append(conditions, "confidence <= ?")
+		args = append(args, *filter.MaxConfidence)
+	}
+	
+	return conditions, args
+}
+
+func (db *DB) SaveIdempotencyKey(key, analysisID string, createdAt time.Time) error {
+	return db.SaveIdempotencyKeyContext(context.Background(), key, analysisID, createdAt)
+}
+
+func (db *DB) SaveIdempotencyKeyContext(ctx context.Context, key, analysisID string, createdAt time.Time) error {
+	_, err := db.conn.ExecContext(
+		ctx,
+		"INSERT OR REPLACE INTO idempotency_keys (key, analysis_id, created_at) VALUES (?, ?, ?)",
+		key,
+		analysisID,
+		formatTimestamp(createdAt),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save idempotency key: %w", err)
+	}
+	
+	return nil
+}
+
+func (db *DB) GetIdempotencyKey(key string, notBefore time.Time) (string, error) {
+	return db.GetIdempotencyKeyContext(context.Background(), key, notBefore)
+}
+
+func (db *DB) GetIdempotencyKeyContext(ctx context.Context, key string, notBefore time.Time) (string, error) {
+	var analysisID string
+	
+	err := db.conn.QueryRowContext(
+		ctx,
+		"SELECT analysis_id FROM idempotency_keys WHERE key = ? AND created_at >= ?",
+		key,
+		formatTimestamp(notBefore),
+	).Scan(&analysisID)
+	
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to query idempotency key: %w", err)
+	}
+	
+	return analysisID, nil
 }
 
 func (db *DB) GetRecentAnalyses(limit int) ([]*models.TextAnalysis, error) {
+	return db.GetRecentAnalysesContext(context.Background(), limit)
+}
+
+func (db *DB) GetRecentAnalysesContext(ctx context.Context, limit int) ([]*models.TextAnalysis, error) {
 	query := models.SearchQuery{
 		Limit: limit,
 	}
-	return db.SearchAnalyses(query)
+	return db.SearchAnalysesContext(ctx, query)
 }
 
 func (db *DB) GetStats() (map[string]interface{}, error) {
+	return db.GetStatsContext(context.Background())
+}
+
+// GetStatsContext returns aggregate stats over all analyses. Results are
+// cached for Options.StatsCacheTTL so dashboards polling it do not rerun the
+// aggregates on every call.
+func (db *DB) GetStatsContext(ctx context.Context) (map[string]interface{}, error) {
+	cached, generation, ok := db.stats.get(time.Now())
+	if ok {
+		return cached, nil
+	}
+	
+	stats, err := db.queryStats(ctx)
+	if err != nil {
+		return nil, err
+	}
+	db.stats.put(stats, generation, time.Now())
+	
+	return stats, nil
+}
+
+func (db *DB) queryStats(ctx context.Context) (map[string]interface{}, error) {
 	stats := make(map[string]interface{})
 	
 	var count int
-	err := db.conn.QueryRow("SELECT COUNT(*) FROM analyses").Scan(&count)
+	err := db.read.QueryRowContext(ctx, "SELECT COUNT(*) FROM analyses").Scan(&count)
 	if err != nil {
 		return nil, err
 	}
 	stats["total_analyses"] = count
 	
 	var avgConfidence sql.NullFloat64
-	err = db.conn.QueryRow("SELECT AVG(confidence) FROM analyses").Scan(&avgConfidence)
+	err = db.read.QueryRowContext(ctx, "SELECT AVG(confidence) FROM analyses").Scan(&avgConfidence)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
@@ -217,7 +977,7 @@ func (db *DB) GetStats() (map[string]interface{}, error) {
 	}
 	
 	var avgProcessingTime sql.NullFloat64
-	err = db.conn.QueryRow("SELECT AVG(processing_ms) FROM analyses").Scan(&avgProcessingTime)
+	err = db.read.QueryRowContext(ctx, "SELECT AVG(processing_ms) FROM analyses").Scan(&avgProcessingTime)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
@@ -227,18 +987,61 @@ func (db *DB) GetStats() (map[string]interface{}, error) {
 		stats["average_processing_ms"] = 0.0
 	}
 	
-	var lastAnalysisStr sql.NullString
-	err = db.conn.QueryRow("SELECT MAX(created_at) FROM analyses").Scan(&lastAnalysisStr)
+	var lastAnalysis time.Time
+	err = db.read.QueryRowContext(ctx, "SELECT MAX(created_at) FROM analyses").Scan(timestamp{&lastAnalysis})
 	if err != nil && err != sql.ErrNoRows {
 		return nil, err
 	}
-	if lastAnalysisStr.Valid && lastAnalysisStr.String != "" {
-		stats["last_analysis"] = lastAnalysisStr.String
+	if !lastAnalysis.IsZero() {
+		stats["last_analysis"] = lastAnalysis
 	}
 	
 	return stats, nil
 }
 
+func (db *DB) GetTopicTimeline(topic, interval string) ([]models.TimelineBucket, error) {
+	return db.GetTopicTimelineContext(context.Background(), topic, interval)
+}
+
+// GetTopicTimelineContext counts the analyses listing topic, grouped by day,
+// week or month of created_at. Buckets without analyses are omitted.
+func (db *DB) GetTopicTimelineContext(ctx context.Context, topic, interval string) ([]models.TimelineBucket, error) {
+	bucket, ok := timelineBuckets[interval]
+	if !ok {
+		return nil, fmt.Errorf("%w %q, must be one of: day, week, month", ErrInvalidInterval, interval)
+	}
+	
+	rows, err := db.conn.QueryContext(ctx, `
+		SELECT `+bucket+` AS bucket, COUNT(*)
+		FROM analyses
+		WHERE EXISTS (SELECT 1 FROM json_each(metadata, '$.topics') WHERE value = ?)
+		GROUP BY bucket
+		ORDER BY bucket`, topic)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query topic timeline: %w", err)
+	}
+	defer rows.Close()
+	
+	buckets := make([]models.TimelineBucket, 0)
+	for rows.Next() {
+		var b models.TimelineBucket
+		if err := rows.Scan(&b.Start, &b.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan timeline bucket: %w", err)
+		}
+		buckets = append(buckets, b)
+	}
+	
+	return buckets, rows.Err()
+}
+
 func (db *DB) Close() error {
+	for _, stmt := range []*sql.Stmt{db.insertAnalysisStmt, db.insertTagStmt, db.getAnalysisStmt} {
+		if stmt != nil {
+			stmt.Close()
+		}
+	}
+	if db.read != nil && db.read != db.conn {
+		db.read.Close()
+	}
 	return db.conn.Close()
 }
\ No newline at end of file
@@ -0,0 +1,933 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func newTestDB(t *testing.T) *DB {
+	t.Helper()
+	
+	db, err := New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	
+	return db
+}
+
+func seedAnalysis(t *testing.T, db *DB, id, text string, topics []string, createdAt time.Time) {
+	t.Helper()
+	
+	err := db.SaveAnalysis(&models.TextAnalysis{
+		ID:      id,
+		Text:    text,
+		Summary: "Summary of " + id,
+		Metadata: map[string]interface{}{
+			"topics": topics,
+		},
+		Confidence: 0.8,
+		CreatedAt:  createdAt,
+	})
+	require.NoError(t, err)
+}
+
+func TestDB_DeleteAnalyses(t *testing.T) {
+	now := time.Now()
+	
+	tests := []struct {
+		name      string
+		filter    models.SearchFilter
+		deleted   int64
+		remaining int
+	}{
+		{
+			name:      "Delete by topic",
+			filter:    models.SearchFilter{Topic: "golang"},
+			deleted:   2,
+			remaining: 2,
+		},
+		{
+			name:      "Delete by keyword",
+			filter:    models.SearchFilter{Keyword: "sqlite"},
+			deleted:   1,
+			remaining: 3,
+		},
+		{
+			name:      "Delete by date range",
+			filter:    models.SearchFilter{To: now.Add(-24 * time.Hour)},
+			deleted:   2,
+			remaining: 2,
+		},
+		{
+			name:      "Combined filters",
+			filter:    models.SearchFilter{Topic: "golang", From: now.Add(-time.Hour)},
+			deleted:   1,
+			remaining: 3,
+		},
+		{
+			name:      "No matches",
+			filter:    models.SearchFilter{Topic: "cooking"},
+			deleted:   0,
+			remaining: 4,
+		},
+		{
+			name:      "Empty filter deletes everything",
+			filter:    models.SearchFilter{},
+			deleted:   4,
+			remaining: 0,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestDB(t)
+			seedAnalysis(t, db, "a1", "Go concurrency patterns", []string{"golang"}, now)
+			seedAnalysis(t, db, "a2", "Go modules explained", []string{"golang"}, now.Add(-48*time.Hour))
+			seedAnalysis(t, db, "a3", "Tuning sqlite for writes", []string{"databases"}, now.Add(-72*time.Hour))
+			seedAnalysis(t, db, "a4", "Release notes", []string{"product"}, now)
+			
+			deleted, err := db.DeleteAnalyses(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.deleted, deleted)
+			
+			remaining, err := db.SearchAnalyses(models.SearchQuery{})
+			require.NoError(t, err)
+			assert.Len(t, remaining, tt.remaining)
+		})
+	}
+}
+
+func TestDB_SearchAnalyses_Tags(t *testing.T) {
+	db := newTestDB(t)
+	
+	for id, tags := range map[string][]string{
+		"a1": {"research", "draft"},
+		"a2": {"research"},
+		"a3": {"draft"},
+		"a4": nil,
+	} {
+		err := db.SaveAnalysis(&models.TextAnalysis{
+			ID:      id,
+			Text:    "Text for " + id,
+			Summary: "Summary for " + id,
+			Metadata: map[string]interface{}{
+				"tags": tags,
+			},
+			Confidence: 0.8,
+			CreatedAt:  time.Now(),
+		})
+		require.NoError(t, err)
+	}
+	
+	tests := []struct {
+		name     string
+		tags     []string
+		expected []string
+	}{
+		{
+			name:     "Single tag",
+			tags:     []string{"research"},
+			expected: []string{"a1", "a2"},
+		},
+		{
+			name:     "Multiple tags use AND semantics",
+			tags:     []string{"research", "draft"},
+			expected: []string{"a1"},
+		},
+		{
+			name:     "Tag matching is case-insensitive",
+			tags:     []string{" Draft "},
+			expected: []string{"a1", "a3"},
+		},
+		{
+			name:     "Unknown tag",
+			tags:     []string{"missing"},
+			expected: nil,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := db.SearchAnalyses(models.SearchQuery{
+				SearchFilter: models.SearchFilter{Tags: tt.tags},
+			})
+			require.NoError(t, err)
+			
+			var ids []string
+			for _, result := range results {
+				ids = append(ids, result.ID)
+			}
+			assert.ElementsMatch(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestDB_SearchAnalyses_SentimentScoreRange(t *testing.T) {
+	db := newTestDB(t)
+	
+	for id, score := range map[string]float64{
+		"neg":     -0.8,
+		"neutral": 0,
+		"pos":     0.6,
+	} {
+		err := db.SaveAnalysis(&models.TextAnalysis{
+			ID:      id,
+			Text:    "Text for " + id,
+			Summary: "Summary for " + id,
+			Metadata: map[string]interface{}{
+				"sentiment_score": score,
+			},
+			Confidence: 0.8,
+			CreatedAt:  time.Now(),
+		})
+		require.NoError(t, err)
+	}
+	
+	score := func(v float64) *float64 { return &v }
+	
+	tests := []struct {
+		name     string
+		min      *float64
+		max      *float64
+		expected []string
+	}{
+		{
+			name:     "Minimum only",
+			min:      score(0.1),
+			expected: []string{"pos"},
+		},
+		{
+			name:     "Maximum only",
+			max:      score(0),
+			expected: []string{"neg", "neutral"},
+		},
+		{
+			name:     "Closed range",
+			min:      score(-0.5),
+			max:      score(0.5),
+			expected: []string{"neutral"},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := db.SearchAnalyses(models.SearchQuery{
+				SearchFilter: models.SearchFilter{MinSentimentScore: tt.min, MaxSentimentScore: tt.max},
+			})
+			require.NoError(t, err)
+			
+			var ids []string
+			for _, result := range results {
+				ids = append(ids, result.ID)
+			}
+			assert.ElementsMatch(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestDB_SearchAnalyses_Sentiment(t *testing.T) {
+	db := newTestDB(t)
+	
+	for id, meta := range map[string][2]string{
+		"neg-go":   {"negative", "golang"},
+		"neg-rust": {"negative", "rust"},
+		"pos-go":   {"positive", "golang"},
+	} {
+		err := db.SaveAnalysis(&models.TextAnalysis{
+			ID:      id,
+			Text:    "Text for " + id,
+			Summary: "Summary for " + id,
+			Metadata: map[string]interface{}{
+				"sentiment": meta[0],
+				"topics":    []string{meta[1]},
+			},
+			Confidence: 0.8,
+			CreatedAt:  time.Now(),
+		})
+		require.NoError(t, err)
+	}
+	
+	tests := []struct {
+		name     string
+		filter   models.SearchFilter
+		expected []string
+	}{
+		{"Sentiment only", models.SearchFilter{Sentiment: "negative"}, []string{"neg-go", "neg-rust"}},
+		{"Combined with topic", models.SearchFilter{Sentiment: "negative", Topic: "golang"}, []string{"neg-go"}},
+		{"No matches", models.SearchFilter{Sentiment: "neutral"}, nil},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := db.SearchAnalyses(models.SearchQuery{SearchFilter: tt.filter})
+			require.NoError(t, err)
+			
+			var ids []string
+			for _, result := range results {
+				ids = append(ids, result.ID)
+			}
+			assert.ElementsMatch(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestDB_SearchAnalyses_Provider(t *testing.T) {
+	db := newTestDB(t)
+	
+	for id, provider := range map[string]string{"openai-1": "openai", "openai-2": "openai", "ollama-1": "ollama"} {
+		err := db.SaveAnalysis(&models.TextAnalysis{
+			ID:         id,
+			Text:       "Text for " + id,
+			Summary:    "Summary for " + id,
+			Metadata:   map[string]interface{}{"provider": provider},
+			Confidence: 0.8,
+			CreatedAt:  time.Now(),
+		})
+		require.NoError(t, err)
+	}
+	
+	results, err := db.SearchAnalyses(models.SearchQuery{SearchFilter: models.SearchFilter{Provider: "openai"}})
+	require.NoError(t, err)
+	var ids []string
+	for _, result := range results {
+		ids = append(ids, result.ID)
+	}
+	assert.ElementsMatch(t, []string{"openai-1", "openai-2"}, ids)
+}
+
+func TestDB_GetSearchFacets(t *testing.T) {
+	db := newTestDB(t)
+	
+	for id, topics := range map[string][]string{
+		"t1": {"golang", "testing"},
+		"t2": {"golang", "databases"},
+		"t3": {"databases", "golang"},
+		"t4": {"testing"},
+	} {
+		err := db.SaveAnalysis(&models.TextAnalysis{
+			ID:         id,
+			Text:       "Text for " + id,
+			Summary:    "Summary for " + id,
+			Metadata:   map[string]interface{}{"topics": topics, "sentiment": "positive"},
+			Confidence: 0.8,
+			CreatedAt:  time.Now(),
+		})
+		require.NoError(t, err)
+	}
+	
+	facets, err := db.GetSearchFacets(models.SearchFilter{}, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 4, facets.Total)
+	assert.Equal(t, map[string]int{"positive": 4}, facets.Sentiments)
+	assert.Equal(t, []models.TopicCount{{Topic: "golang", Count: 3}, {Topic: "databases", Count: 2}}, facets.Topics)
+	
+	facets, err = db.GetSearchFacets(models.SearchFilter{Topic: "testing"}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, facets.Total)
+	assert.Equal(t, []models.TopicCount{{Topic: "testing", Count: 2}, {Topic: "golang", Count: 1}}, facets.Topics)
+}
+
+func TestDB_SearchAnalyses_ConfidenceRange(t *testing.T) {
+	db := newTestDB(t)
+	
+	for id, confidence := range map[string]float64{
+		"low":    0.2,
+		"medium": 0.5,
+		"high":   0.9,
+	} {
+		err := db.SaveAnalysis(&models.TextAnalysis{
+			ID:         id,
+			Text:       "Text for " + id,
+			Summary:    "Summary for " + id,
+			Metadata:   map[string]interface{}{},
+			Confidence: confidence,
+			CreatedAt:  time.Now(),
+		})
+		require.NoError(t, err)
+	}
+	
+	confidence := func(v float64) *float64 { return &v }
+	
+	tests := []struct {
+		name     string
+		min      *float64
+		max      *float64
+		expected []string
+	}{
+		{"Minimum only", confidence(0.5), nil, []string{"medium", "high"}},
+		{"Maximum only", nil, confidence(0.4), []string{"low"}},
+		{"Window", confidence(0.3), confidence(0.6), []string{"medium"}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := db.SearchAnalyses(models.SearchQuery{
+				SearchFilter: models.SearchFilter{MinConfidence: tt.min, MaxConfidence: tt.max},
+			})
+			require.NoError(t, err)
+			
+			var ids []string
+			for _, result := range results {
+				ids = append(ids, result.ID)
+			}
+			assert.ElementsMatch(t, tt.expected, ids)
+		})
+	}
+}
+
+func TestDB_UpdateAnalysis_RecordsHistory(t *testing.T) {
+	db := newTestDB(t)
+	seedAnalysis(t, db, "a1", "Original text", []string{"first"}, time.Now())
+	
+	for i, summary := range []string{"Second summary", "Third summary"} {
+		current, err := db.GetAnalysis("a1")
+		require.NoError(t, err)
+		
+		current.Summary = summary
+		current.Metadata["topics"] = []string{fmt.Sprintf("revision-%d", i+2)}
+		current.Confidence = 0.5
+		require.NoError(t, db.UpdateAnalysis(current))
+	}
+	
+	current, err := db.GetAnalysis("a1")
+	require.NoError(t, err)
+	assert.Equal(t, "Third summary", current.Summary)
+	assert.Equal(t, "Original text", current.Text)
+	
+	history, err := db.GetAnalysisHistory("a1")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	
+	assert.Equal(t, 2, history[0].Version)
+	assert.Equal(t, "Second summary", history[0].Summary)
+	assert.Equal(t, []interface{}{"revision-2"}, history[0].Metadata["topics"])
+	
+	assert.Equal(t, 1, history[1].Version)
+	assert.Equal(t, "Summary of a1", history[1].Summary)
+	assert.Equal(t, 0.8, history[1].Confidence)
+	
+	deleted, err := db.DeleteAnalyses(models.SearchFilter{Keyword: "Original"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	
+	history, err = db.GetAnalysisHistory("a1")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestDB_UpdateAnalysis_NotFound(t *testing.T) {
+	db := newTestDB(t)
+	
+	err := db.UpdateAnalysis(&models.TextAnalysis{ID: "missing", Summary: "x"})
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestDB_ConcurrentInserts(t *testing.T) {
+	db := newTestDB(t)
+	
+	const writers = 50
+	errs := make(chan error, writers)
+	
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			errs <- db.SaveAnalysis(&models.TextAnalysis{
+				ID:      fmt.Sprintf("concurrent-%d", index),
+				Text:    "Concurrent insert",
+				Summary: "Summary",
+				Metadata: map[string]interface{}{
+					"tags": []string{"load", fmt.Sprintf("writer-%d", index)},
+				},
+				Confidence: 0.5,
+				CreatedAt:  time.Now(),
+			})
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+	
+	results, err := db.SearchAnalyses(models.SearchQuery{})
+	require.NoError(t, err)
+	assert.Len(t, results, writers)
+}
+
+func TestNew_PathValidation(t *testing.T) {
+	dir := t.TempDir()
+	
+	tests := []struct {
+		name string
+		path string
+	}{
+		{
+			name: "Path is a directory",
+			path: dir,
+		},
+		{
+			name: "Parent directory missing",
+			path: filepath.Join(dir, "missing", "test.db"),
+		},
+		{
+			name: "Empty path",
+			path: "",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := New(tt.path)
+			assert.ErrorIs(t, err, ErrInvalidPath)
+			assert.Nil(t, db)
+		})
+	}
+}
+
+func TestNew_InMemory(t *testing.T) {
+	db, err := New(MemoryPath)
+	require.NoError(t, err)
+	defer db.Close()
+	
+	seedAnalysis(t, db, "mem", "In-memory text", []string{"memory"}, time.Now())
+	
+	results, err := db.SearchAnalyses(models.SearchQuery{
+		SearchFilter: models.SearchFilter{Topic: "memory"},
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "mem", results[0].ID)
+	
+	stored, err := db.GetAnalysis("mem")
+	require.NoError(t, err)
+	assert.Equal(t, "In-memory text", stored.Text)
+}
+
+func TestBuildDSN(t *testing.T) {
+	assert.Equal(t, "test.db", buildDSN("test.db", Options{}))
+	assert.Equal(t, "test.db?_busy_timeout=5000&_journal_mode=WAL", buildDSN("test.db", DefaultOptions()))
+	assert.Equal(t, "file:test.db?cache=shared&_journal_mode=DELETE", buildDSN("file:test.db?cache=shared", Options{JournalMode: "DELETE"}))
+}
+
+func benchmarkAnalysis(i int) *models.TextAnalysis {
+	return &models.TextAnalysis{
+		ID:         fmt.Sprintf("bench-%d", i),
+		Text:       "Benchmark text for insert throughput",
+		Summary:    "Benchmark summary",
+		Metadata:   map[string]interface{}{"topics": []string{"benchmark"}},
+		Confidence: 0.7,
+		CreatedAt:  time.Now(),
+	}
+}
+
+func BenchmarkSaveAnalysis_Prepared(b *testing.B) {
+	db, err := New(filepath.Join(b.TempDir(), "bench.db"))
+	require.NoError(b, err)
+	defer db.Close()
+	
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := db.SaveAnalysis(benchmarkAnalysis(i)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSaveAnalysis_AdHoc(b *testing.B) {
+	db, err := New(filepath.Join(b.TempDir(), "bench.db"))
+	require.NoError(b, err)
+	defer db.Close()
+	
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		analysis := benchmarkAnalysis(i)
+		metadataJSON, _ := json.Marshal(analysis.Metadata)
+		
+		tx, err := db.conn.Begin()
+		if err != nil {
+			b.Fatal(err)
+		}
+		
+		_, err = tx.Exec(
+			insertAnalysisQuery,
+			analysis.ID,
+			analysis.Text,
+			analysis.Summary,
+			string(metadataJSON),
+			analysis.Confidence,
+			analysis.CreatedAt,
+			analysis.ProcessingMS,
+		)
+		if err != nil {
+			b.Fatal(err)
+		}
+		
+		if err := tx.Commit(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDB_SearchAnalysesStream(t *testing.T) {
+	db := newTestDB(t)
+	
+	base := time.Now()
+	for i := 0; i < 10; i++ {
+		seedAnalysis(t, db, fmt.Sprintf("s%d", i), "Streamed text", []string{"stream"}, base.Add(time.Duration(i)*time.Minute))
+	}
+	
+	query := models.SearchQuery{SearchFilter: models.SearchFilter{Topic: "stream"}}
+	
+	expected, err := db.SearchAnalyses(query)
+	require.NoError(t, err)
+	require.Len(t, expected, 10)
+	
+	stream, err := db.SearchAnalysesStream(query)
+	require.NoError(t, err)
+	
+	var streamed []string
+	stream(func(analysis *models.TextAnalysis, err error) bool {
+		require.NoError(t, err)
+		streamed = append(streamed, analysis.ID)
+		return true
+	})
+	
+	var ids []string
+	for _, analysis := range expected {
+		ids = append(ids, analysis.ID)
+	}
+	assert.Equal(t, ids, streamed)
+	
+	stream, err = db.SearchAnalysesStream(query)
+	require.NoError(t, err)
+	
+	count := 0
+	stream(func(analysis *models.TextAnalysis, err error) bool {
+		count++
+		return count < 3
+	})
+	assert.Equal(t, 3, count)
+}
+
+func TestDB_CancelledContext(t *testing.T) {
+	db := newTestDB(t)
+	seedAnalysis(t, db, "c1", "Context text", []string{"context"}, time.Now())
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	
+	_, err := db.SearchAnalysesContext(ctx, models.SearchQuery{})
+	assert.ErrorIs(t, err, context.Canceled)
+	
+	_, err = db.GetAnalysisContext(ctx, "c1")
+	assert.ErrorIs(t, err, context.Canceled)
+	
+	err = db.SaveAnalysisContext(ctx, &models.TextAnalysis{
+		ID:        "c2",
+		Text:      "Never saved",
+		Summary:   "Summary",
+		Metadata:  map[string]interface{}{},
+		CreatedAt: time.Now(),
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	
+	stored, err := db.GetAnalysis("c2")
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestDB_SearchAnalyses_CursorStableUnderInserts(t *testing.T) {
+	db := newTestDB(t)
+	
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 6; i++ {
+		seedAnalysis(t, db, fmt.Sprintf("p%d", i), "Paged text", []string{"paging"}, base.Add(time.Duration(i)*time.Minute))
+	}
+	seedAnalysis(t, db, "p5b", "Paged text with same timestamp", []string{"paging"}, base.Add(5*time.Minute))
+	
+	seen := make(map[string]bool)
+	var order []string
+	cursor := ""
+	
+	for page := 0; page < 10; page++ {
+		results, err := db.SearchAnalyses(models.SearchQuery{Limit: 3, Cursor: cursor})
+		require.NoError(t, err)
+		
+		for _, result := range results {
+			assert.False(t, seen[result.ID], "duplicate %s", result.ID)
+			seen[result.ID] = true
+			order = append(order, result.ID)
+		}
+		
+		seedAnalysis(t, db, fmt.Sprintf("new%d", page), "Inserted between pages", []string{"paging"}, time.Now())
+		
+		if len(results) < 3 {
+			break
+		}
+		cursor = EncodeCursor(results[len(results)-1])
+	}
+	
+	assert.Equal(t, []string{"p5b", "p5", "p4", "p3", "p2", "p1", "p0"}, order)
+}
+
+func TestDB_SearchAnalyses_InvalidCursor(t *testing.T) {
+	db := newTestDB(t)
+	
+	for _, cursor := range []string{"not base64!", "bm8tc2VwYXJhdG9y"} {
+		_, err := db.SearchAnalyses(models.SearchQuery{Cursor: cursor})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	}
+}
+
+func insertRaw(t *testing.T, db *DB, id string) {
+	t.Helper()
+	
+	_, err := db.conn.Exec(insertAnalysisQuery, id, "Inserted behind the cache", "", "{}", 0.5, formatTimestamp(time.Now()), 10)
+	require.NoError(t, err)
+}
+
+func totalAnalyses(t *testing.T, db *DB) int {
+	t.Helper()
+	
+	stats, err := db.GetStats()
+	require.NoError(t, err)
+	return stats["total_analyses"].(int)
+}
+
+func TestDB_GetStats_Cache(t *testing.T) {
+	db := newTestDB(t)
+	seedAnalysis(t, db, "a1", "First", []string{"golang"}, time.Now())
+	assert.Equal(t, 1, totalAnalyses(t, db))
+	
+	insertRaw(t, db, "raw1")
+	assert.Equal(t, 1, totalAnalyses(t, db), "second call within TTL should not re-query")
+	
+	stats, err := db.GetStats()
+	require.NoError(t, err)
+	stats["total_analyses"] = 99
+	assert.Equal(t, 1, totalAnalyses(t, db), "callers must not be able to modify the cached stats")
+	
+	seedAnalysis(t, db, "a2", "Second", []string{"golang"}, time.Now())
+	assert.Equal(t, 3, totalAnalyses(t, db), "save should invalidate the cache")
+	
+	_, err = db.DeleteAnalyses(models.SearchFilter{Topic: "golang"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, totalAnalyses(t, db), "delete should invalidate the cache")
+}
+
+func TestDB_GetStats_CacheTTL(t *testing.T) {
+	for _, ttl := range []time.Duration{0, 20 * time.Millisecond} {
+		opts := DefaultOptions()
+		opts.StatsCacheTTL = ttl
+		db, err := NewWithOptions(filepath.Join(t.TempDir(), "test.db"), opts)
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		
+		assert.Equal(t, 0, totalAnalyses(t, db))
+		insertRaw(t, db, "raw1")
+		if ttl > 0 {
+			assert.Equal(t, 0, totalAnalyses(t, db))
+			time.Sleep(ttl)
+		}
+		assert.Equal(t, 1, totalAnalyses(t, db), "ttl %s", ttl)
+	}
+}
+
+func TestDB_GetStats_ConcurrentWrites(t *testing.T) {
+	db := newTestDB(t)
+	
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			seedAnalysis(t, db, fmt.Sprintf("c%d", i), "Concurrent", []string{"golang"}, time.Now())
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := db.GetStats()
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	
+	assert.Equal(t, 10, totalAnalyses(t, db))
+}
+
+func TestDB_GetTopicTimeline(t *testing.T) {
+	db := newTestDB(t)
+	day := func(month time.Month, d, hour int) time.Time {
+		return time.Date(2024, month, d, hour, 0, 0, 0, time.UTC)
+	}
+	
+	seedAnalysis(t, db, "t1", "Go on Wednesday", []string{"golang"}, day(time.May, 1, 9))
+	seedAnalysis(t, db, "t2", "Go again", []string{"golang", "sqlite"}, day(time.May, 1, 17))
+	seedAnalysis(t, db, "t3", "Go on Thursday", []string{"golang"}, day(time.May, 2, 9))
+	seedAnalysis(t, db, "t4", "Go next week", []string{"golang"}, day(time.May, 8, 9))
+	seedAnalysis(t, db, "t5", "Go in June", []string{"golang"}, day(time.June, 3, 9))
+	seedAnalysis(t, db, "t6", "Only sqlite", []string{"sqlite"}, day(time.May, 3, 9))
+	seedAnalysis(t, db, "t7", "Similar topic", []string{"golang tooling"}, day(time.May, 3, 9))
+	
+	tests := []struct {
+		interval string
+		expected []models.TimelineBucket
+	}{
+		{"day", []models.TimelineBucket{{Start: "2024-05-01", Count: 2}, {Start: "2024-05-02", Count: 1}, {Start: "2024-05-08", Count: 1}, {Start: "2024-06-03", Count: 1}}},
+		{"week", []models.TimelineBucket{{Start: "2024-04-29", Count: 3}, {Start: "2024-05-06", Count: 1}, {Start: "2024-06-03", Count: 1}}},
+		{"month", []models.TimelineBucket{{Start: "2024-05-01", Count: 4}, {Start: "2024-06-01", Count: 1}}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.interval, func(t *testing.T) {
+			buckets, err := db.GetTopicTimeline("golang", tt.interval)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buckets)
+		})
+	}
+	
+	buckets, err := db.GetTopicTimeline("rust", "day")
+	require.NoError(t, err)
+	assert.NotNil(t, buckets)
+	assert.Empty(t, buckets)
+	
+	_, err = db.GetTopicTimeline("golang", "hour")
+	assert.ErrorIs(t, err, ErrInvalidInterval)
+}
+
+func TestDB_SaveChunkedAnalysis(t *testing.T) {
+	db := newTestDB(t)
+	
+	newAnalysis := func(id string, topics []string) *models.TextAnalysis {
+		return &models.TextAnalysis{
+			ID:        id,
+			Text:      "Text of " + id,
+			Metadata:  map[string]interface{}{"topics": topics},
+			CreatedAt: time.Now(),
+		}
+	}
+	
+	chunks := []*models.TextAnalysis{newAnalysis("c2", []string{"chunk"}), newAnalysis("c1", []string{"chunk"})}
+	require.NoError(t, db.SaveChunkedAnalysis(newAnalysis("doc", []string{"document"}), chunks))
+	
+	ids, err := db.GetChunkIDs("doc")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c2", "c1"}, ids)
+	
+	ids, err = db.GetChunkIDs("c1")
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+	
+	_, err = db.DeleteAnalyses(models.SearchFilter{Topic: "chunk"})
+	require.NoError(t, err)
+	ids, err = db.GetChunkIDs("doc")
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+	
+	err = db.SaveChunkedAnalysis(newAnalysis("doc2", nil), []*models.TextAnalysis{newAnalysis("doc", nil)})
+	assert.Error(t, err, "duplicate chunk ID should roll back")
+	stored, err := db.GetAnalysis("doc2")
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+}
+
+func TestDB_TimestampRoundTrip(t *testing.T) {
+	db := newTestDB(t)
+	
+	zones := []*time.Location{
+		time.UTC,
+		time.FixedZone("UTC-5", -5*3600),
+		time.FixedZone("UTC+9:30", 9*3600+30*60),
+	}
+	base := time.Date(2024, time.March, 10, 23, 45, 12, 987654321, time.UTC)
+	
+	for i, zone := range zones {
+		createdAt := base.Add(time.Duration(i) * time.Hour).In(zone)
+		id := fmt.Sprintf("tz-%d", i)
+		seedAnalysis(t, db, id, "Timezone test", []string{"time"}, createdAt)
+		
+		loaded, err := db.GetAnalysis(id)
+		require.NoError(t, err)
+		assert.True(t, createdAt.Equal(loaded.CreatedAt), "%s: saved %s, loaded %s", zone, createdAt, loaded.CreatedAt)
+		assert.Equal(t, time.UTC, loaded.CreatedAt.Location())
+		assert.Equal(t, createdAt.Truncate(time.Second).UTC(), loaded.CreatedAt.Truncate(time.Second))
+	}
+	
+	results, err := db.SearchAnalyses(models.SearchQuery{SearchFilter: models.SearchFilter{
+		From: base.Add(30 * time.Minute).In(zones[2]),
+		To:   base.Add(90 * time.Minute).In(zones[1]),
+	}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "tz-1", results[0].ID)
+	assert.True(t, base.Add(time.Hour).Equal(results[0].CreatedAt))
+	
+	stats, err := db.GetStats()
+	require.NoError(t, err)
+	assert.Equal(t, base.Add(2*time.Hour), stats["last_analysis"])
+}
+
+func TestDB_MigratesLegacyTimestamps(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	db, err := New(path)
+	require.NoError(t, err)
+	
+	legacy := time.Date(2024, time.May, 1, 22, 30, 0, 123000000, time.FixedZone("UTC-5", -5*3600))
+	_, err = db.conn.Exec(insertAnalysisQuery, "legacy", "Old row", "", "{}", 0.5, legacy.Format("2006-01-02 15:04:05.999999999-07:00"), 10)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	
+	db, err = New(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	
+	var stored string
+	require.NoError(t, db.conn.QueryRow("SELECT CAST(created_at AS TEXT) FROM analyses WHERE id = 'legacy'").Scan(&stored))
+	assert.Equal(t, "2024-05-02T03:30:00.123000000Z", stored)
+	
+	loaded, err := db.GetAnalysis("legacy")
+	require.NoError(t, err)
+	assert.True(t, legacy.Equal(loaded.CreatedAt))
+}
+
+func TestDB_Notes(t *testing.T) {
+	db := newTestDB(t)
+	seedAnalysis(t, db, "a1", "First", []string{"golang"}, time.Now())
+	seedAnalysis(t, db, "a2", "Second", []string{"sqlite"}, time.Now())
+	
+	base := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	_, err := db.AddNote("a1", "later note", base.Add(time.Minute))
+	require.NoError(t, err)
+	_, err = db.AddNote("a1", "earlier note", base)
+	require.NoError(t, err)
+	_, err = db.AddNote("a2", "other analysis", base)
+	require.NoError(t, err)
+	
+	notes, err := db.GetNotes("a1")
+	require.NoError(t, err)
+	require.Len(t, notes, 2)
+	assert.Equal(t, "earlier note", notes[0].Note)
+	assert.True(t, base.Equal(notes[0].CreatedAt))
+	assert.Equal(t, "later note", notes[1].Note)
+	
+	_, err = db.DeleteAnalyses(models.SearchFilter{Topic: "golang"})
+	require.NoError(t, err)
+	
+	notes, err = db.GetNotes("a1")
+	require.NoError(t, err)
+	assert.Empty(t, notes)
+	notes, err = db.GetNotes("a2")
+	require.NoError(t, err)
+	assert.Len(t, notes, 1)
+}
\ No newline at end of file
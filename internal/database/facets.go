@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func (db *DB) GetSearchFacets(filter models.SearchFilter, topicLimit int) (*models.SearchFacets, error) {
+	return db.GetSearchFacetsContext(context.Background(), filter, topicLimit)
+}
+
+// GetSearchFacetsContext counts the analyses matching filter by sentiment and
+// by topic, returning the topicLimit most common topics (ties in name order).
+// An analysis without a stored sentiment counts as neutral, as it loads,
+// unless it is a partial analysis that skipped sentiment: those count toward
+// Total only.
+func (db *DB) GetSearchFacetsContext(ctx context.Context, filter models.SearchFilter, topicLimit int) (*models.SearchFacets, error) {
+	conditions, args := filterConditions(filter)
+	matched := "SELECT id, metadata FROM analyses WHERE 1=1"
+	if len(conditions) > 0 {
+		matched += " AND " + strings.Join(conditions, " AND ")
+	}
+	
+	facets := &models.SearchFacets{
+		Sentiments: make(map[string]int),
+		Topics:     make([]models.TopicCount, 0),
+	}
+	
+	rows, err := db.read.QueryContext(
+		ctx,
+		`SELECT COALESCE(
+			json_extract(metadata, '$.sentiment'),
+			CASE WHEN json_extract(metadata, '$.components') IS NULL THEN 'neutral' END
+		), COUNT(*)
+		FROM (`+matched+`)
+		GROUP BY 1`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sentiments: %w", err)
+	}
+	defer rows.Close()
+	
+	for rows.Next() {
+		var sentiment sql.NullString
+		var count int
+		if err := rows.Scan(&sentiment, &count); err != nil {
+			return nil, fmt.Errorf("failed to scan sentiment count: %w", err)
+		}
+		if sentiment.Valid {
+			facets.Sentiments[sentiment.String] = count
+		}
+		facets.Total += count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+	
+	// json_each has its own id column, so the filter runs in a subquery
+	// where its column names can only mean the analysis's.
+	rows, err = db.read.QueryContext(
+		ctx,
+		`SELECT topic.value, COUNT(DISTINCT matched.id) AS n
+		FROM (`+matched+`) AS matched, json_each(matched.metadata, '$.topics') AS topic
+		WHERE topic.type = 'text'
+		GROUP BY topic.value
+		ORDER BY n DESC, topic.value
+		LIMIT ?`,
+		append(args, topicLimit)...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count topics: %w", err)
+	}
+	defer rows.Close()
+	
+	for rows.Next() {
+		var topic models.TopicCount
+		if err := rows.Scan(&topic.Topic, &topic.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan topic count: %w", err)
+		}
+		facets.Topics = append(facets.Topics, topic)
+	}
+	
+	return facets, rows.Err()
+}
\ No newline at end of file
@@ -0,0 +1,64 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+var ErrDuplicateID = errors.New("analysis ID already exists")
+
+func (db *DB) ImportAnalyses(analyses []*models.TextAnalysis) ([]error, error) {
+	return db.ImportAnalysesContext(context.Background(), analyses)
+}
+
+// ImportAnalysesContext inserts already-analyzed records as they are, IDs
+// and timestamps included, in one transaction. The first result holds one
+// error per analysis, nil for those inserted; an ID that is already stored
+// (or repeated earlier in analyses) gets ErrDuplicateID. A failed item
+// doesn't stop the others. The second result is set when the transaction
+// itself fails, in which case nothing was imported.
+func (db *DB) ImportAnalysesContext(ctx context.Context, analyses []*models.TextAnalysis) ([]error, error) {
+	tx, err := db.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	
+	results := make([]error, len(analyses))
+	for i, analysis := range analyses {
+		var exists int
+		err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM analyses WHERE id = ?", analysis.ID).Scan(&exists)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check analysis ID: %w", err)
+		}
+		if exists > 0 {
+			results[i] = fmt.Errorf("%w: %s", ErrDuplicateID, analysis.ID)
+			continue
+		}
+		
+		// A savepoint per item undoes a half-written analysis (row
+		// inserted, tags not) without losing the items before it.
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT import_item"); err != nil {
+			return nil, fmt.Errorf("failed to create savepoint: %w", err)
+		}
+		if err := db.insertAnalysis(ctx, tx, analysis); err != nil {
+			results[i] = err
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO import_item"); err != nil {
+				return nil, fmt.Errorf("failed to roll back to savepoint: %w", err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, "RELEASE import_item"); err != nil {
+			return nil, fmt.Errorf("failed to release savepoint: %w", err)
+		}
+	}
+	
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit import: %w", err)
+	}
+	db.stats.invalidate()
+	
+	return results, nil
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+	
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func (db *DB) AddNote(analysisID, note string, createdAt time.Time) (*models.Note, error) {
+	return db.AddNoteContext(context.Background(), analysisID, note, createdAt)
+}
+
+// AddNoteContext attaches note to an analysis. Callers check the analysis
+// exists first; the notes table has no foreign key.
+func (db *DB) AddNoteContext(ctx context.Context, analysisID, note string, createdAt time.Time) (*models.Note, error) {
+	result, err := db.conn.ExecContext(
+		ctx,
+		"INSERT INTO notes (analysis_id, note, created_at) VALUES (?, ?, ?)",
+		analysisID,
+		note,
+		formatTimestamp(createdAt),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save note: %w", err)
+	}
+	
+	id, err := result.LastInsertId()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read note id: %w", err)
+	}
+	
+	return &models.Note{
+		ID:         id,
+		AnalysisID: analysisID,
+		Note:       note,
+		CreatedAt:  createdAt.UTC(),
+	}, nil
+}
+
+func (db *DB) GetNotes(analysisID string) ([]*models.Note, error) {
+	return db.GetNotesContext(context.Background(), analysisID)
+}
+
+// GetNotesContext returns the notes on an analysis, oldest first.
+func (db *DB) GetNotesContext(ctx context.Context, analysisID string) ([]*models.Note, error) {
+	rows, err := db.conn.QueryContext(
+		ctx,
+		"SELECT id, analysis_id, note, created_at FROM notes WHERE analysis_id = ? ORDER BY created_at, id",
+		analysisID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notes: %w", err)
+	}
+	defer rows.Close()
+	
+	notes := make([]*models.Note, 0)
+	for rows.Next() {
+		var note models.Note
+		if err := rows.Scan(&note.ID, &note.AnalysisID, &note.Note, timestamp{&note.CreatedAt}); err != nil {
+			return nil, fmt.Errorf("failed to scan note: %w", err)
+		}
+		notes = append(notes, &note)
+	}
+	
+	return notes, rows.Err()
+}
\ No newline at end of file
@@ -0,0 +1,90 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestNewWithOptions_ReadReplica(t *testing.T) {
+	dir := t.TempDir()
+	replicaPath := filepath.Join(dir, "replica.db")
+	
+	// Stand in for a replica that has only caught up with older writes.
+	replica, err := New(replicaPath)
+	require.NoError(t, err)
+	seedAnalysis(t, replica, "replicated", "Replicated text", []string{"replica"}, time.Now())
+	require.NoError(t, replica.Close())
+	
+	opts := DefaultOptions()
+	opts.StatsCacheTTL = 0
+	opts.ReadPath = replicaPath
+	db, err := NewWithOptions(filepath.Join(dir, "primary.db"), opts)
+	require.NoError(t, err)
+	defer db.Close()
+	
+	seedAnalysis(t, db, "primary-only", "Primary text", []string{"primary"}, time.Now())
+	
+	stored, err := db.GetAnalysis("replicated")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "Replicated text", stored.Text)
+	
+	stored, err = db.GetAnalysis("primary-only")
+	require.NoError(t, err)
+	assert.Nil(t, stored, "reads go to the replica, which has not seen the write")
+	
+	stored, err = db.GetAnalysisPrimary("primary-only")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "Primary text", stored.Text)
+	
+	results, err := db.SearchAnalyses(models.SearchQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "replicated", results[0].ID)
+	
+	results, err = db.SearchAnalysesPrimaryContext(context.Background(), models.SearchQuery{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, "primary-only", results[0].ID)
+	
+	stats, err := db.GetStats()
+	require.NoError(t, err)
+	assert.Equal(t, 1, stats["total_analyses"])
+	
+	var primaryCount int
+	require.NoError(t, db.conn.QueryRow("SELECT COUNT(*) FROM analyses").Scan(&primaryCount))
+	assert.Equal(t, 1, primaryCount, "writes go to the primary only")
+	
+	_, err = db.read.Exec("DELETE FROM analyses")
+	assert.Error(t, err, "the replica connection is query-only")
+}
+
+func TestNewWithOptions_ReadReplicaFallback(t *testing.T) {
+	db := newTestDB(t)
+	assert.Same(t, db.conn, db.read)
+	
+	seedAnalysis(t, db, "a1", "Text", []string{"topic"}, time.Now())
+	stored, err := db.GetAnalysis("a1")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+}
+
+func TestNewWithOptions_ReadReplicaMissing(t *testing.T) {
+	dir := t.TempDir()
+	opts := DefaultOptions()
+	opts.ReadPath = filepath.Join(dir, "missing.db")
+	
+	_, err := NewWithOptions(filepath.Join(dir, "primary.db"), opts)
+	assert.ErrorIs(t, err, ErrInvalidPath)
+	
+	opts.ReadPath = MemoryPath
+	_, err = NewWithOptions(filepath.Join(dir, "primary.db"), opts)
+	assert.ErrorIs(t, err, ErrInvalidPath)
+}
\ No newline at end of file
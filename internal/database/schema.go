@@ -0,0 +1,100 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+// SchemaVersion is stamped into the metadata of every saved analysis. Bump it
+// when the metadata shape changes and teach metadataDefaults about the fields
+// older rows lack.
+//
+//	1: title, topics, sentiment, keywords (rows written before versioning)
+//	2: adds sentiment_score, language, llm_ms, keyword_ms and schema_version
+const SchemaVersion = 2
+
+const legacySchemaVersion = 1
+
+func metadataDefaults() map[string]interface{} {
+	return map[string]interface{}{
+		"title":           "",
+		"topics":          []interface{}{},
+		"sentiment":       "neutral",
+		"sentiment_score": 0.0,
+		"keywords":        []interface{}{},
+		"language":        "en",
+	}
+}
+
+// defaultComponents maps each defaulted field to the analysis component that
+// computes it.
+var defaultComponents = map[string]string{
+	"title":           "title",
+	"topics":          "topics",
+	"sentiment":       "sentiment",
+	"sentiment_score": "sentiment",
+	"keywords":        "keywords",
+	"language":        "language",
+}
+
+func encodeMetadata(analysis *models.TextAnalysis) (string, error) {
+	if analysis.Metadata == nil {
+		analysis.Metadata = make(map[string]interface{})
+	}
+	analysis.Metadata["schema_version"] = SchemaVersion
+	
+	data, err := json.Marshal(analysis.Metadata)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	return string(data), nil
+}
+
+// decodeMetadata unmarshals stored metadata and fills in defaults for fields
+// that rows from older schema versions don't have. The stored schema_version
+// is preserved so consumers can tell an upgraded row from a current one. A
+// row that records its components was a partial analysis: fields of the
+// components it skipped stay absent rather than looking computed.
+func decodeMetadata(data string) (map[string]interface{}, error) {
+	var metadata map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	if metadata == nil {
+		metadata = make(map[string]interface{})
+	}
+	
+	if _, ok := metadata["schema_version"]; !ok {
+		metadata["schema_version"] = float64(legacySchemaVersion)
+	}
+	
+	computed := computedComponents(metadata)
+	for key, value := range metadataDefaults() {
+		if computed != nil && !computed[defaultComponents[key]] {
+			continue
+		}
+		if existing, ok := metadata[key]; !ok || existing == nil {
+			metadata[key] = value
+		}
+	}
+	
+	return metadata, nil
+}
+
+// computedComponents returns the components recorded in metadata, or nil
+// when the row doesn't record them because every component was computed.
+func computedComponents(metadata map[string]interface{}) map[string]bool {
+	list, ok := metadata["components"].([]interface{})
+	if !ok {
+		return nil
+	}
+	computed := make(map[string]bool, len(list))
+	for _, component := range list {
+		if name, ok := component.(string); ok {
+			computed[name] = true
+		}
+	}
+	return computed
+}
\ No newline at end of file
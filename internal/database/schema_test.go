@@ -0,0 +1,77 @@
+package database
+
+import (
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestDB_LegacyMetadataGetsDefaults(t *testing.T) {
+	db := newTestDB(t)
+	
+	_, err := db.conn.Exec(
+		`INSERT INTO analyses (id, text, summary, metadata, confidence, created_at, processing_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		"legacy", "Old text", "Old summary",
+		`{"title": "Old Title", "topics": ["history"], "keywords": null}`,
+		0.7, time.Now(), 12,
+	)
+	require.NoError(t, err)
+	
+	check := func(analysis *models.TextAnalysis) {
+		assert.Equal(t, float64(legacySchemaVersion), analysis.Metadata["schema_version"])
+		assert.Equal(t, "Old Title", analysis.Metadata["title"])
+		assert.Equal(t, []interface{}{"history"}, analysis.Metadata["topics"])
+		assert.Equal(t, "neutral", analysis.Metadata["sentiment"])
+		assert.Equal(t, 0.0, analysis.Metadata["sentiment_score"])
+		assert.Equal(t, []interface{}{}, analysis.Metadata["keywords"])
+		assert.Equal(t, "en", analysis.Metadata["language"])
+	}
+	
+	analysis, err := db.GetAnalysis("legacy")
+	require.NoError(t, err)
+	check(analysis)
+	
+	results, err := db.SearchAnalyses(models.SearchQuery{SearchFilter: models.SearchFilter{Topic: "history"}})
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	check(results[0])
+}
+
+func TestDB_SaveAnalysisStampsSchemaVersion(t *testing.T) {
+	db := newTestDB(t)
+	seedAnalysis(t, db, "current", "New text", []string{"present"}, time.Now())
+	
+	analysis, err := db.GetAnalysis("current")
+	require.NoError(t, err)
+	assert.Equal(t, float64(SchemaVersion), analysis.Metadata["schema_version"])
+	assert.Equal(t, []interface{}{"present"}, analysis.Metadata["topics"])
+}
+
+func TestDB_PartialAnalysisSkipsDefaults(t *testing.T) {
+	db := newTestDB(t)
+	err := db.SaveAnalysis(&models.TextAnalysis{
+		ID:        "partial",
+		Text:      "Text",
+		Summary:   "Summary",
+		Metadata:  map[string]interface{}{"components": []string{"summary", "keywords"}, "keywords": []string{"text"}},
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+	
+	analysis, err := db.GetAnalysis("partial")
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"text"}, analysis.Metadata["keywords"])
+	for _, key := range []string{"title", "topics", "sentiment", "sentiment_score", "language"} {
+		assert.NotContains(t, analysis.Metadata, key)
+	}
+	
+	seedAnalysis(t, db, "full", "Text", []string{"topic"}, time.Now())
+	facets, err := db.GetSearchFacets(models.SearchFilter{}, 10)
+	require.NoError(t, err)
+	assert.Equal(t, 2, facets.Total)
+	assert.Equal(t, map[string]int{"neutral": 1}, facets.Sentiments, "only the full analysis defaults to neutral")
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"sync"
+	"time"
+)
+
+// statsCache keeps the last GetStats result for ttl. Every write bumps
+// generation, so a result that was being computed while a write landed is
+// returned to its caller but never cached.
+type statsCache struct {
+	mu         sync.Mutex
+	ttl        time.Duration
+	stats      map[string]interface{}
+	expires    time.Time
+	generation uint64
+}
+
+func (c *statsCache) get(now time.Time) (map[string]interface{}, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	if c.stats == nil || !now.Before(c.expires) {
+		return nil, c.generation, false
+	}
+	return copyStats(c.stats), c.generation, true
+}
+
+func (c *statsCache) put(stats map[string]interface{}, generation uint64, now time.Time) {
+	if c.ttl <= 0 {
+		return
+	}
+	
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	if generation != c.generation {
+		return
+	}
+	c.stats = copyStats(stats)
+	c.expires = now.Add(c.ttl)
+}
+
+func (c *statsCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	c.generation++
+	c.stats = nil
+}
+
+func copyStats(stats map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(stats))
+	for k, v := range stats {
+		result[k] = v
+	}
+	return result
+}
\ No newline at end of file
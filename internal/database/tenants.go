@@ -0,0 +1,163 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrInvalidTenant = errors.New("invalid tenant ID")
+	ErrUnknownTenant = errors.New("unknown tenant")
+)
+
+var tenantIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// ValidTenantID reports whether id can name a tenant. IDs become file names,
+// so only letters, digits, '-' and '_' are allowed.
+func ValidTenantID(id string) bool {
+	return tenantIDPattern.MatchString(id)
+}
+
+// Tenants keeps one database file per tenant, <dir>/<tenant>.db, so tenants
+// never share tables. A tenant exists once Provision has created its file;
+// Get only opens existing files, so a request cannot create a tenant. Files
+// are opened on first use and stay open until Close. Options.ReadPath is
+// ignored: a replica belongs to one database, so tenant reads always use the
+// tenant's own file.
+type Tenants struct {
+	dir  string
+	opts Options
+	
+	mu  sync.Mutex
+	dbs map[string]*tenantDB
+}
+
+// tenantDB is an open, or still opening, tenant database. ready is closed
+// once db or err is set, so callers wait on the tenant they asked for
+// without holding up the others while its migrations run.
+type tenantDB struct {
+	ready chan struct{}
+	db    *DB
+	err   error
+}
+
+func NewTenants(dir string, opts Options) (*Tenants, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create tenant directory: %w", err)
+	}
+	opts.ReadPath = ""
+	return &Tenants{
+		dir:  dir,
+		opts: opts,
+		dbs:  make(map[string]*tenantDB),
+	}, nil
+}
+
+// Get returns the database of a provisioned tenant, or ErrUnknownTenant when
+// the tenant has no file.
+func (t *Tenants) Get(id string) (*DB, error) {
+	return t.open(id, false)
+}
+
+// Provision creates the database of a tenant if it doesn't exist yet and
+// returns it. It is safe to call for an existing tenant.
+func (t *Tenants) Provision(id string) (*DB, error) {
+	return t.open(id, true)
+}
+
+func (t *Tenants) open(id string, create bool) (*DB, error) {
+	if !ValidTenantID(id) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidTenant, id)
+	}
+	path := filepath.Join(t.dir, id+".db")
+	
+	t.mu.Lock()
+	entry, ok := t.dbs[id]
+	if !ok {
+		if _, err := os.Stat(path); !create && errors.Is(err, os.ErrNotExist) {
+			t.mu.Unlock()
+			return nil, fmt.Errorf("%w: %q", ErrUnknownTenant, id)
+		}
+		entry = &tenantDB{ready: make(chan struct{})}
+		t.dbs[id] = entry
+	}
+	t.mu.Unlock()
+	
+	if ok {
+		<-entry.ready
+		return entry.db, entry.err
+	}
+	
+	entry.db, entry.err = NewWithOptions(path, t.opts)
+	if entry.err != nil {
+		entry.err = fmt.Errorf("failed to open database for tenant %s: %w", id, entry.err)
+		t.mu.Lock()
+		delete(t.dbs, id)
+		t.mu.Unlock()
+	}
+	close(entry.ready)
+	return entry.db, entry.err
+}
+
+// All opens every tenant database found in the directory, in tenant order.
+func (t *Tenants) All() ([]*DB, error) {
+	paths, err := filepath.Glob(filepath.Join(t.dir, "*.db"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tenant databases: %w", err)
+	}
+	sort.Strings(paths)
+	
+	var dbs []*DB
+	for _, path := range paths {
+		id := strings.TrimSuffix(filepath.Base(path), ".db")
+		if !ValidTenantID(id) {
+			continue
+		}
+		db, err := t.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		dbs = append(dbs, db)
+	}
+	return dbs, nil
+}
+
+func (t *Tenants) Close() error {
+	t.mu.Lock()
+	dbs := t.dbs
+	t.dbs = make(map[string]*tenantDB)
+	t.mu.Unlock()
+	
+	var errs []error
+	for id, entry := range dbs {
+		<-entry.ready
+		if entry.db == nil {
+			continue
+		}
+		if err := entry.db.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type contextKey struct{}
+
+// WithContext returns a copy of ctx carrying db, for code that picks the
+// database per request.
+func WithContext(ctx context.Context, db *DB) context.Context {
+	return context.WithValue(ctx, contextKey{}, db)
+}
+
+// FromContext returns the database stored by WithContext, if any.
+func FromContext(ctx context.Context) (*DB, bool) {
+	db, ok := ctx.Value(contextKey{}).(*DB)
+	return db, ok
+}
\ No newline at end of file
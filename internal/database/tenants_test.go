@@ -0,0 +1,113 @@
+package database
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenants_Isolated(t *testing.T) {
+	tenants, err := NewTenants(t.TempDir(), Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { tenants.Close() })
+	
+	acme, err := tenants.Provision("acme")
+	require.NoError(t, err)
+	globex, err := tenants.Provision("globex")
+	require.NoError(t, err)
+	
+	seedAnalysis(t, acme, "acme-1", "Acme text", []string{"rockets"}, time.Now())
+	
+	leaked, err := globex.GetAnalysis("acme-1")
+	require.NoError(t, err)
+	assert.Nil(t, leaked)
+	
+	again, err := tenants.Get("acme")
+	require.NoError(t, err)
+	assert.Same(t, acme, again)
+	
+	analysis, err := again.GetAnalysis("acme-1")
+	require.NoError(t, err)
+	assert.Equal(t, "Acme text", analysis.Text)
+	
+	again, err = tenants.Provision("acme")
+	require.NoError(t, err)
+	assert.Same(t, acme, again, "provisioning an existing tenant is a no-op")
+	
+	all, err := tenants.All()
+	require.NoError(t, err)
+	assert.Equal(t, []*DB{acme, globex}, all)
+}
+
+func TestTenants_Unknown(t *testing.T) {
+	dir := t.TempDir()
+	tenants, err := NewTenants(dir, Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { tenants.Close() })
+	
+	_, err = tenants.Get("typo")
+	assert.ErrorIs(t, err, ErrUnknownTenant)
+	_, err = os.Stat(filepath.Join(dir, "typo.db"))
+	assert.ErrorIs(t, err, os.ErrNotExist, "a lookup never creates a file")
+	
+	// Tenants provisioned by an earlier process are found by their file.
+	earlier, err := NewTenants(dir, Options{})
+	require.NoError(t, err)
+	_, err = earlier.Provision("acme")
+	require.NoError(t, err)
+	require.NoError(t, earlier.Close())
+	
+	acme, err := tenants.Get("acme")
+	require.NoError(t, err)
+	assert.NotNil(t, acme)
+}
+
+func TestTenants_ConcurrentOpen(t *testing.T) {
+	tenants, err := NewTenants(t.TempDir(), Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { tenants.Close() })
+	
+	var wg sync.WaitGroup
+	dbs := make([]*DB, 8)
+	for i := range dbs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			db, err := tenants.Provision("acme")
+			assert.NoError(t, err)
+			dbs[i] = db
+		}(i)
+	}
+	wg.Wait()
+	
+	for _, db := range dbs {
+		assert.Same(t, dbs[0], db, "concurrent callers share one open database")
+	}
+}
+
+func TestTenants_InvalidID(t *testing.T) {
+	tenants, err := NewTenants(t.TempDir(), Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { tenants.Close() })
+	
+	for _, id := range []string{"", "../escape", "a/b", "with space"} {
+		_, err := tenants.Get(id)
+		assert.ErrorIs(t, err, ErrInvalidTenant, id)
+	}
+}
+
+func TestFromContext(t *testing.T) {
+	_, ok := FromContext(context.Background())
+	assert.False(t, ok)
+	
+	db := newTestDB(t)
+	got, ok := FromContext(WithContext(context.Background(), db))
+	assert.True(t, ok)
+	assert.Same(t, db, got)
+}
\ No newline at end of file
@@ -0,0 +1,84 @@
+package database
+
+import (
+	"fmt"
+	"time"
+)
+
+// timestampLayout is how every timestamp column is stored: UTC RFC 3339 with
+// a fixed nine-digit fraction, so comparing and sorting the stored strings in
+// SQL matches comparing the times.
+const timestampLayout = "2006-01-02T15:04:05.000000000Z"
+
+// legacyTimestampLayouts are the formats the sqlite3 driver wrote for
+// time.Time values before timestamps were standardized.
+var legacyTimestampLayouts = []string{
+	"2006-01-02 15:04:05.999999999-07:00",
+	"2006-01-02T15:04:05.999999999-07:00",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999",
+}
+
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format(timestampLayout)
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t.UTC(), nil
+	}
+	for _, layout := range legacyTimestampLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid timestamp %q", value)
+}
+
+// timestamp scans a timestamp column into a UTC time.Time. The driver hands
+// back TIMESTAMP columns as time.Time but computed ones such as MAX(created_at)
+// as text, so both are accepted.
+type timestamp struct {
+	t *time.Time
+}
+
+func (ts timestamp) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case time.Time:
+		*ts.t = value.UTC()
+	case string:
+		return ts.parse(value)
+	case []byte:
+		return ts.parse(string(value))
+	case nil:
+		*ts.t = time.Time{}
+	default:
+		return fmt.Errorf("cannot scan %T into timestamp", src)
+	}
+	return nil
+}
+
+func (ts timestamp) parse(value string) error {
+	t, err := parseTimestamp(value)
+	if err != nil {
+		return err
+	}
+	*ts.t = t
+	return nil
+}
+
+// migrateTimestamps rewrites timestamps stored in the driver's old local-time
+// format into timestampLayout. Rows already in the layout are left alone, so
+// it is safe to run on every start.
+func (db *DB) migrateTimestamps() error {
+	for _, table := range []string{"analyses", "analysis_versions", "idempotency_keys"} {
+		_, err := db.conn.Exec(`UPDATE ` + table + `
+			SET created_at = strftime('%Y-%m-%dT%H:%M:%f', created_at) || '000000Z'
+			WHERE created_at NOT LIKE '____-__-__T__:__:__._________Z'
+				AND strftime('%Y-%m-%dT%H:%M:%f', created_at) IS NOT NULL`)
+		if err != nil {
+			return fmt.Errorf("failed to migrate %s timestamps: %w", table, err)
+		}
+	}
+	return nil
+}
\ No newline at end of file
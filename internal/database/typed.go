@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func (db *DB) GetAnalysisTyped(id string) (*models.TypedAnalysis, error) {
+	return db.GetAnalysisTypedContext(context.Background(), id)
+}
+
+// GetAnalysisTypedContext loads an analysis like GetAnalysisContext, then
+// decodes its metadata into models.AnalysisMetadata. Defaults for fields
+// missing from older rows are applied first, so both forms agree.
+func (db *DB) GetAnalysisTypedContext(ctx context.Context, id string) (*models.TypedAnalysis, error) {
+	analysis, err := db.GetAnalysisContext(ctx, id)
+	if err != nil || analysis == nil {
+		return nil, err
+	}
+	
+	metadata, err := typedMetadata(analysis.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	
+	return &models.TypedAnalysis{
+		ID:           analysis.ID,
+		Text:         analysis.Text,
+		Summary:      analysis.Summary,
+		Metadata:     metadata,
+		Confidence:   analysis.Confidence,
+		CreatedAt:    analysis.CreatedAt,
+		ProcessingMS: analysis.ProcessingMS,
+	}, nil
+}
+
+func typedMetadata(metadata map[string]interface{}) (models.AnalysisMetadata, error) {
+	var typed models.AnalysisMetadata
+	
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return typed, fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	if err := json.Unmarshal(data, &typed); err != nil {
+		return typed, fmt.Errorf("failed to decode typed metadata: %w", err)
+	}
+	return typed, nil
+}
\ No newline at end of file
@@ -0,0 +1,77 @@
+package database
+
+import (
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestDB_GetAnalysisTyped(t *testing.T) {
+	db := newTestDB(t)
+	
+	require.NoError(t, db.SaveAnalysis(&models.TextAnalysis{
+		ID:      "typed",
+		Text:    "Typed text",
+		Summary: "Typed summary",
+		Metadata: map[string]interface{}{
+			"title":           "Typed Title",
+			"topics":          []string{"golang", "databases"},
+			"sentiment":       "positive",
+			"sentiment_score": 0.6,
+			"keywords":        []string{"sqlite", "schema"},
+			"language":        "en",
+			"tags":            []string{"research"},
+			"provider":        "openai",
+			"model":           "gpt-4o-mini",
+			"llm_ms":          120,
+			"keyword_ms":      2,
+			"compliance_flag": true,
+		},
+		Confidence:   0.8,
+		CreatedAt:    time.Now(),
+		ProcessingMS: 130,
+	}))
+	
+	analysis, err := db.GetAnalysisTyped("typed")
+	require.NoError(t, err)
+	require.NotNil(t, analysis)
+	assert.Equal(t, "Typed summary", analysis.Summary)
+	assert.Equal(t, 0.8, analysis.Confidence)
+	assert.Equal(t, models.AnalysisMetadata{
+		Title:          "Typed Title",
+		Topics:         []string{"golang", "databases"},
+		Sentiment:      "positive",
+		SentimentScore: 0.6,
+		Keywords:       []string{"sqlite", "schema"},
+		Language:       "en",
+		Tags:           []string{"research"},
+		Provider:       "openai",
+		Model:          "gpt-4o-mini",
+		LLMMS:          120,
+		KeywordMS:      2,
+		SchemaVersion:  SchemaVersion,
+	}, analysis.Metadata)
+	
+	_, err = db.conn.Exec(
+		`INSERT INTO analyses (id, text, summary, metadata, confidence, created_at, processing_ms)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		"legacy", "Old text", "Old summary", `{"title": "Old Title", "topics": ["history"]}`, 0.7, time.Now(), 12,
+	)
+	require.NoError(t, err)
+	
+	legacy, err := db.GetAnalysisTyped("legacy")
+	require.NoError(t, err)
+	assert.Equal(t, "Old Title", legacy.Metadata.Title)
+	assert.Equal(t, []string{"history"}, legacy.Metadata.Topics)
+	assert.Equal(t, "neutral", legacy.Metadata.Sentiment)
+	assert.Equal(t, []string{}, legacy.Metadata.Keywords)
+	assert.Equal(t, "en", legacy.Metadata.Language)
+	assert.Equal(t, legacySchemaVersion, legacy.Metadata.SchemaVersion)
+	
+	missing, err := db.GetAnalysisTyped("missing")
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
\ No newline at end of file
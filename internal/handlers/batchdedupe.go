@@ -0,0 +1,47 @@
+package handlers
+
+import (
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+// batchDuplicates finds texts that repeat an earlier text in the same batch.
+// Texts are compared after normalization, which bindBatch has already
+// applied. It returns the indices of first occurrences, the ones to analyze,
+// and maps every repeat's index to its first occurrence.
+func batchDuplicates(texts []string) ([]int, map[int]int) {
+	first := make(map[string]int, len(texts))
+	duplicateOf := make(map[int]int)
+	indices := make([]int, 0, len(texts))
+	for i, text := range texts {
+		if original, ok := first[text]; ok {
+			duplicateOf[i] = original
+			continue
+		}
+		first[text] = i
+		indices = append(indices, i)
+	}
+	return indices, duplicateOf
+}
+
+// shareDuplicates gives each repeat the outcome of its first occurrence: the
+// same result, analysis ID included, or the same failure under its own
+// index. results holds one slot per text, as from processBatchIndexed.
+func shareDuplicates(results []models.AnalyzeResponse, failed []models.BatchError, duplicateOf map[int]int) []models.BatchError {
+	if len(duplicateOf) == 0 {
+		return failed
+	}
+	
+	failures := make(map[int]models.BatchError, len(failed))
+	for _, failure := range failed {
+		failures[failure.Index] = failure
+	}
+	for index, original := range duplicateOf {
+		if failure, ok := failures[original]; ok {
+			failure.Index = index
+			failed = append(failed, failure)
+			continue
+		}
+		results[index] = results[original]
+	}
+	return failed
+}
\ No newline at end of file
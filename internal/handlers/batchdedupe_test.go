@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestBatchDuplicates(t *testing.T) {
+	indices, duplicateOf := batchDuplicates([]string{"a", "b", "a", "c", "b", "a"})
+	assert.Equal(t, []int{0, 1, 3}, indices)
+	assert.Equal(t, map[int]int{2: 0, 4: 1, 5: 0}, duplicateOf)
+}
+
+func TestHandler_BatchAnalyzeText_Dedupe(t *testing.T) {
+	provider := newFlakyProvider(map[string]int{"Broken text.": 1})
+	h, db := newTestHandler(t, provider)
+	r := gin.New()
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	texts := []string{
+		"The same report.",
+		"Another report.",
+		"The same  report.",
+		"Broken text.",
+		"The same report.",
+		"Broken text.",
+	}
+	w := postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: texts, Dedupe: true})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp models.BatchAnalyzeResponse
+	decodeData(t, w, &resp)
+	
+	assert.Equal(t, map[string]int{"The same report.": 1, "Another report.": 1, "Broken text.": 1}, provider.calls)
+	
+	// Results keep index order with the failures left out, so 0, 1, 2 and 4.
+	require.Len(t, resp.Results, 4)
+	assert.Equal(t, 4, resp.SuccessCount)
+	first := resp.Results[0].ID
+	assert.NotEqual(t, first, resp.Results[1].ID)
+	assert.Equal(t, first, resp.Results[2].ID, "normalized repeat shares the result")
+	assert.Equal(t, first, resp.Results[3].ID)
+	assert.Equal(t, resp.Results[0].Summary, resp.Results[3].Summary)
+	
+	require.Len(t, resp.Failed, 2)
+	assert.Equal(t, 2, resp.FailedCount)
+	assert.Equal(t, 3, resp.Failed[0].Index)
+	assert.Equal(t, 5, resp.Failed[1].Index)
+	assert.Equal(t, resp.Failed[0].Error, resp.Failed[1].Error)
+	
+	stored, err := db.SearchAnalyses(models.SearchQuery{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+}
+
+func TestHandler_BatchAnalyzeText_NoDedupeByDefault(t *testing.T) {
+	provider := newFlakyProvider(nil)
+	h, _ := newTestHandler(t, provider)
+	r := gin.New()
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	w := postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: []string{"Twice.", "Twice."}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp models.BatchAnalyzeResponse
+	decodeData(t, w, &resp)
+	
+	assert.Equal(t, 2, provider.calls["Twice."])
+	require.Len(t, resp.Results, 2)
+	assert.NotEqual(t, resp.Results[0].ID, resp.Results[1].ID)
+}
\ No newline at end of file
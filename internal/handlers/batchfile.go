@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/analyzer"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+type fileDocument struct {
+	line int
+	text string
+}
+
+// splitDocuments splits an upload into documents. In "line" mode every
+// non-blank line is a document; in "block" mode consecutive non-blank lines
+// form one document and blank lines separate them.
+func splitDocuments(r io.Reader, mode string) ([]fileDocument, error) {
+	var docs []fileDocument
+	var block []string
+	blockStart := 0
+	
+	flush := func() {
+		if len(block) > 0 {
+			docs = append(docs, fileDocument{line: blockStart, text: strings.Join(block, "\n")})
+			block = nil
+		}
+	}
+	
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := analyzer.NormalizeText(scanner.Text())
+		if text == "" {
+			flush()
+			continue
+		}
+		
+		if mode == "line" {
+			docs = append(docs, fileDocument{line: line, text: text})
+			continue
+		}
+		
+		if len(block) == 0 {
+			blockStart = line
+		}
+		block = append(block, text)
+	}
+	flush()
+	
+	return docs, scanner.Err()
+}
+
+func (h *Handler) BatchAnalyzeFile(c *gin.Context) {
+	mode := c.DefaultPostForm("split", "line")
+	if mode != "line" && mode != "block" {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, fmt.Errorf("unsupported split mode %q, expected line or block", mode))
+		return
+	}
+	
+	header, err := c.FormFile("file")
+	if err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, fmt.Errorf("a file upload in the \"file\" field is required: %w", err))
+		return
+	}
+	
+	file, err := header.Open()
+	if err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, fmt.Errorf("failed to read uploaded file: %w", err))
+		return
+	}
+	defer file.Close()
+	
+	data, err := io.ReadAll(file)
+	if err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, fmt.Errorf("failed to read uploaded file: %w", err))
+		return
+	}
+	
+	data, offset := h.validateEncoding(data)
+	if offset >= 0 {
+		line := bytes.Count(data[:offset], []byte("\n")) + 1
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidEncoding, fmt.Errorf("invalid UTF-8 on line %d", line))
+		return
+	}
+	
+	docs, err := splitDocuments(bytes.NewReader(data), mode)
+	if err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, fmt.Errorf("failed to read uploaded file: %w", err))
+		return
+	}
+	
+	if len(docs) == 0 {
+		response.RespondError(c, http.StatusBadRequest, models.CodeEmptyInput, errors.New("no documents found in file"))
+		return
+	}
+	
+	if len(docs) > maxBatchSize {
+		response.RespondError(c, http.StatusBadRequest, models.CodeBatchSizeExceeded, fmt.Errorf("maximum %d texts allowed per batch", maxBatchSize))
+		return
+	}
+	
+	texts := make([]string, len(docs))
+	indices := make([]int, len(docs))
+	for i, doc := range docs {
+		if h.rejectText(c, fmt.Sprintf("document at line %d", doc.line), doc.text) {
+			return
+		}
+		texts[i] = doc.text
+		indices[i] = i
+	}
+	
+	if !h.checkBatchBudget(c, texts) {
+		return
+	}
+	
+	results, failed := h.processBatchIndexed(c.Request.Context(), texts, indices)
+	omitText(c, results)
+	
+	resp := models.BatchFileResponse{
+		Results: make(map[int]models.AnalyzeResponse, len(docs)),
+	}
+	for i, result := range results {
+		if result.ID != "" {
+			resp.Results[docs[i].line] = result
+		}
+	}
+	for i := range failed {
+		failed[i].Index = docs[failed[i].Index].line
+	}
+	sort.Slice(failed, func(i, j int) bool {
+		return failed[i].Index < failed[j].Index
+	})
+	resp.Failed = failed
+	resp.SuccessCount = len(resp.Results)
+	resp.FailedCount = len(failed)
+	
+	response.JSON(c, http.StatusOK, resp)
+}
\ No newline at end of file
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func postFile(r http.Handler, path, content string, fields map[string]string) *httptest.ResponseRecorder {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	
+	for key, value := range fields {
+		writer.WriteField(key, value)
+	}
+	part, _ := writer.CreateFormFile("file", "documents.txt")
+	part.Write([]byte(content))
+	writer.Close()
+	
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestHandler_BatchAnalyzeFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		fields  map[string]string
+		lines   []int
+	}{
+		{
+			name:    "One document per line",
+			content: "first document\n\nsecond document\nthird document\n",
+			lines:   []int{1, 3, 4},
+		},
+		{
+			name:    "Blank-line separated blocks",
+			content: "first paragraph\ncontinues here\n\n\nsecond paragraph\n",
+			fields:  map[string]string{"split": "block"},
+			lines:   []int{1, 5},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newFlakyProvider(nil)
+			h, _ := newTestHandler(t, provider)
+			
+			r := gin.New()
+			r.POST("/batch-analyze/file", h.BatchAnalyzeFile)
+			
+			w := postFile(r, "/batch-analyze/file", tt.content, tt.fields)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			
+			var resp models.BatchFileResponse
+			decodeData(t, w, &resp)
+			assert.Equal(t, len(tt.lines), resp.SuccessCount)
+			for _, line := range tt.lines {
+				assert.Contains(t, resp.Results, line)
+			}
+		})
+	}
+	
+	provider := newFlakyProvider(nil)
+	h, _ := newTestHandler(t, provider)
+	r := gin.New()
+	r.POST("/batch-analyze/file", h.BatchAnalyzeFile)
+	
+	w := postFile(r, "/batch-analyze/file", "first paragraph\ncontinues here\n", map[string]string{"split": "block"})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, provider.calls["first paragraph\ncontinues here"])
+}
+
+func TestHandler_BatchAnalyzeFile_FailuresKeyedByLine(t *testing.T) {
+	provider := newFlakyProvider(map[string]int{"bad document": 1})
+	h, _ := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/batch-analyze/file", h.BatchAnalyzeFile)
+	
+	w := postFile(r, "/batch-analyze/file", "good document\n\nbad document\n", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var resp models.BatchFileResponse
+	decodeData(t, w, &resp)
+	assert.Contains(t, resp.Results, 1)
+	require.Len(t, resp.Failed, 1)
+	assert.Equal(t, 3, resp.Failed[0].Index)
+}
+
+func TestHandler_BatchAnalyzeFile_Limits(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		code    models.ErrorCode
+	}{
+		{
+			name:    "Oversize document",
+			content: "short document\n" + strings.Repeat("x", 101) + "\n",
+			code:    models.CodeTextTooLong,
+		},
+		{
+			name:    "Too many documents",
+			content: strings.Repeat("document\n", 11),
+			code:    models.CodeBatchSizeExceeded,
+		},
+		{
+			name:    "Only blank lines",
+			content: "\n\n  \n",
+			code:    models.CodeEmptyInput,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newFlakyProvider(nil)
+			h, _ := newTestHandlerWithConfig(t, provider, Config{MaxTextLength: 100})
+			
+			r := gin.New()
+			r.POST("/batch-analyze/file", h.BatchAnalyzeFile)
+			
+			w := postFile(r, "/batch-analyze/file", tt.content, nil)
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			
+			resp := decodeError(t, w)
+			assert.Equal(t, tt.code, resp.Code)
+			assert.Empty(t, provider.calls)
+		})
+	}
+}
\ No newline at end of file
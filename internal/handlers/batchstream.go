@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+// batchProgress is the payload of a "progress" event: one per batch item,
+// sent as the item finishes.
+type batchProgress struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// BatchAnalyzeStream runs a batch like BatchAnalyzeText but reports progress
+// as server-sent events: a "progress" event per item in completion order,
+// then a "summary" event carrying the usual batch response. With dedupe, a
+// repeated text gets its progress event when its first occurrence finishes,
+// with the same outcome. Validation
+// failures are still plain JSON errors. When the client disconnects, items
+// that have not finished are cancelled.
+func (h *Handler) BatchAnalyzeStream(c *gin.Context) {
+	req, ok := h.bindBatch(c)
+	if !ok {
+		return
+	}
+	texts := req.Texts
+	
+	indices := make([]int, len(texts))
+	for i := range texts {
+		indices[i] = i
+	}
+	
+	var duplicateOf map[int]int
+	if req.Dedupe {
+		indices, duplicateOf = batchDuplicates(texts)
+	}
+	repeats := make(map[int][]int)
+	for index, original := range duplicateOf {
+		repeats[original] = append(repeats[original], index)
+	}
+	for _, repeated := range repeats {
+		sort.Ints(repeated)
+	}
+	
+	events := make(chan batchProgress, len(texts))
+	done := make(chan struct{})
+	var results []models.AnalyzeResponse
+	var failed []models.BatchError
+	go func() {
+		defer close(done)
+		results, failed = h.processBatchContext(c.Request.Context(), texts, indices, func(index int, result *models.AnalyzeResponse, failure *models.BatchError) {
+			for _, item := range append([]int{index}, repeats[index]...) {
+				event := batchProgress{Index: item, Status: "completed", ID: result.ID}
+				if failure != nil {
+					event = batchProgress{Index: item, Status: "failed", Error: failure.Error}
+				}
+				events <- event
+			}
+		})
+		close(events)
+	}()
+	
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	
+	for event := range events {
+		c.SSEvent("progress", event)
+		c.Writer.Flush()
+	}
+	<-done
+	
+	if c.Request.Context().Err() != nil {
+		return
+	}
+	
+	failed = shareDuplicates(results, failed, duplicateOf)
+	successes := successfulResults(results)
+	omitText(c, successes)
+	
+	c.SSEvent("summary", h.batchResponse(successes, failed))
+	c.Writer.Flush()
+}
\ No newline at end of file
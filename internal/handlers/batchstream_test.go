@@ -0,0 +1,193 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/llm"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+type sseEvent struct {
+	name string
+	data string
+}
+
+func readEvents(t *testing.T, body string) []sseEvent {
+	t.Helper()
+	
+	var events []sseEvent
+	var current sseEvent
+	scanner := bufio.NewScanner(strings.NewReader(body))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			current.name = strings.TrimPrefix(line, "event:")
+		case strings.HasPrefix(line, "data:"):
+			current.data = strings.TrimPrefix(line, "data:")
+		case line == "" && current.name != "":
+			events = append(events, current)
+			current = sseEvent{}
+		}
+	}
+	require.NoError(t, scanner.Err())
+	return events
+}
+
+func TestHandler_BatchAnalyzeStream(t *testing.T) {
+	provider := newFlakyProvider(map[string]int{"second text": 10})
+	h, _ := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/batch-analyze/stream", h.BatchAnalyzeStream)
+	
+	w := postJSON(r, "/batch-analyze/stream", models.BatchAnalyzeRequest{
+		Texts: []string{"first text", "second text", "third text"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	
+	events := readEvents(t, w.Body.String())
+	require.Len(t, events, 4)
+	
+	statuses := make(map[int]batchProgress)
+	for _, event := range events[:3] {
+		require.Equal(t, "progress", event.name)
+		var progress batchProgress
+		require.NoError(t, json.Unmarshal([]byte(event.data), &progress))
+		statuses[progress.Index] = progress
+	}
+	require.Len(t, statuses, 3)
+	assert.Equal(t, "completed", statuses[0].Status)
+	assert.NotEmpty(t, statuses[0].ID)
+	assert.Equal(t, "failed", statuses[1].Status)
+	assert.Contains(t, statuses[1].Error, "flaky failure")
+	assert.Equal(t, "completed", statuses[2].Status)
+	
+	require.Equal(t, "summary", events[3].name)
+	var summary models.BatchAnalyzeResponse
+	require.NoError(t, json.Unmarshal([]byte(events[3].data), &summary))
+	assert.Equal(t, 2, summary.SuccessCount)
+	assert.Equal(t, 1, summary.FailedCount)
+	require.Len(t, summary.Failed, 1)
+	assert.Equal(t, 1, summary.Failed[0].Index)
+}
+
+func TestHandler_BatchAnalyzeStream_Dedupe(t *testing.T) {
+	provider := newFlakyProvider(map[string]int{"bad text": 10})
+	h, _ := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/batch-analyze/stream", h.BatchAnalyzeStream)
+	
+	w := postJSON(r, "/batch-analyze/stream", models.BatchAnalyzeRequest{
+		Texts:  []string{"same text", "bad text", "same text", "bad text", "same text"},
+		Dedupe: true,
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, 1, provider.calls["same text"])
+	assert.Equal(t, 1, provider.calls["bad text"])
+	
+	events := readEvents(t, w.Body.String())
+	require.Len(t, events, 6)
+	
+	statuses := make(map[int]batchProgress)
+	for _, event := range events[:5] {
+		require.Equal(t, "progress", event.name)
+		var progress batchProgress
+		require.NoError(t, json.Unmarshal([]byte(event.data), &progress))
+		statuses[progress.Index] = progress
+	}
+	require.Len(t, statuses, 5)
+	for _, index := range []int{0, 2, 4} {
+		assert.Equal(t, "completed", statuses[index].Status)
+		assert.Equal(t, statuses[0].ID, statuses[index].ID)
+	}
+	assert.NotEmpty(t, statuses[0].ID)
+	for _, index := range []int{1, 3} {
+		assert.Equal(t, "failed", statuses[index].Status)
+		assert.Contains(t, statuses[index].Error, "flaky failure")
+	}
+	
+	require.Equal(t, "summary", events[5].name)
+	var summary models.BatchAnalyzeResponse
+	require.NoError(t, json.Unmarshal([]byte(events[5].data), &summary))
+	assert.Equal(t, 3, summary.SuccessCount)
+	assert.Equal(t, 2, summary.FailedCount)
+	assert.Equal(t, []int{1, 3}, summary.FailedIndices)
+}
+
+func TestHandler_BatchAnalyzeStream_ValidationError(t *testing.T) {
+	h, _ := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/batch-analyze/stream", h.BatchAnalyzeStream)
+	
+	w := postJSON(r, "/batch-analyze/stream", models.BatchAnalyzeRequest{Texts: []string{"fine", "  "}})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, models.CodeEmptyInput, decodeError(t, w).Code)
+}
+
+// blockingProvider waits for ctx to be cancelled, signalling once a call has
+// started.
+type blockingProvider struct {
+	started chan struct{}
+}
+
+func (p *blockingProvider) Analyze(ctx context.Context, text string) (*llm.AnalysisResult, error) {
+	select {
+	case p.started <- struct{}{}:
+	default:
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func (p *blockingProvider) IsAvailable() bool {
+	return true
+}
+
+func TestHandler_BatchAnalyzeStream_ClientDisconnect(t *testing.T) {
+	provider := &blockingProvider{started: make(chan struct{}, 1)}
+	h, db := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/batch-analyze/stream", h.BatchAnalyzeStream)
+	
+	body, _ := json.Marshal(models.BatchAnalyzeRequest{Texts: []string{"first text", "second text"}})
+	ctx, cancel := context.WithCancel(context.Background())
+	req := httptest.NewRequest(http.MethodPost, "/batch-analyze/stream", strings.NewReader(string(body))).WithContext(ctx)
+	w := httptest.NewRecorder()
+	
+	finished := make(chan struct{})
+	go func() {
+		r.ServeHTTP(w, req)
+		close(finished)
+	}()
+	
+	<-provider.started
+	cancel()
+	
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream did not stop after the client disconnected")
+	}
+	
+	for _, event := range readEvents(t, w.Body.String()) {
+		assert.NotEqual(t, "summary", event.name)
+	}
+	stats, err := db.GetStats()
+	require.NoError(t, err)
+	assert.Equal(t, 0, stats["total_analyses"])
+}
\ No newline at end of file
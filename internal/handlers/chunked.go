@@ -0,0 +1,267 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/user/llm-knowledge-extractor/internal/analyzer"
+	"github.com/user/llm-knowledge-extractor/internal/llm"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+const maxChunks = 50
+
+// AnalyzeChunked analyzes a document that was split into chunks up front.
+// Every chunk is analyzed and stored on its own, then combined into one
+// aggregated analysis: topics are merged across chunks, the summary is made
+// from the chunk summaries, and confidence is the chunks' average. Any chunk
+// failing fails the whole request and nothing is stored.
+func (h *Handler) AnalyzeChunked(c *gin.Context) {
+	var req models.ChunkedAnalyzeRequest
+	
+	if !h.checkBodyEncoding(c) {
+		return
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	if len(req.Chunks) > maxChunks {
+		response.RespondError(c, http.StatusBadRequest, models.CodeBatchSizeExceeded, fmt.Errorf("maximum %d chunks allowed per document", maxChunks))
+		return
+	}
+	
+	if !h.validateBatchTexts(c, req.Chunks) {
+		return
+	}
+	
+	startTime := time.Now()
+	
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Minute)
+	defer cancel()
+	
+	chunks, results, err := h.analyzeChunks(ctx, req.Chunks)
+	if err != nil {
+		h.respondChunkError(c, err)
+		return
+	}
+	
+	summaries := make([]string, len(results))
+	var confidence float64
+	for i, result := range results {
+		summaries[i] = result.Summary
+		confidence += chunks[i].Confidence
+	}
+	confidence = analyzer.RoundConfidence(confidence / float64(len(chunks)))
+	
+	llmStart := time.Now()
+	combined, err := h.analyzeWithPool(ctx, strings.Join(summaries, "\n\n"))
+	llmMS := time.Since(llmStart).Milliseconds()
+	if err != nil {
+		h.respondChunkError(c, err)
+		return
+	}
+	
+	text := strings.Join(req.Chunks, "\n\n")
+	topics := mergeChunkTopics(results)
+	
+	keywordStart := time.Now()
+	language, keywords := h.extractKeywords(text, topics, 0)
+	keywordMS := time.Since(keywordStart).Milliseconds()
+	
+	metadata := map[string]interface{}{
+		"title":           combined.Title,
+		"topics":          topics,
+		"sentiment":       combined.Sentiment,
+		"sentiment_score": combined.SentimentScore,
+		"keywords":        keywords,
+		"language":        language,
+		"llm_ms":          llmMS,
+		"keyword_ms":      keywordMS,
+		"chunk_count":     len(chunks),
+	}
+	h.recordProvider(metadata)
+	h.recordNode(metadata)
+	summary, summaryTruncated := h.capSummary(combined.Summary)
+	if summaryTruncated {
+		metadata["summary_truncated"] = true
+	}
+	
+	if tags := normalizeTags(req.Tags); len(tags) > 0 {
+		metadata["tags"] = tags
+	}
+	
+	analysis := &models.TextAnalysis{
+		ID:           uuid.New().String(),
+		Text:         text,
+		Summary:      summary,
+		Metadata:     metadata,
+		Confidence:   confidence,
+		CreatedAt:    time.Now(),
+		ProcessingMS: time.Since(startTime).Milliseconds(),
+	}
+	
+	chunkIDs := make([]string, len(chunks))
+	for i, chunk := range chunks {
+		chunk.Metadata["chunk_of"] = analysis.ID
+		chunkIDs[i] = chunk.ID
+	}
+	
+	// Chunks are stored analyses too, so the hooks see each of them as well
+	// as the aggregate; any hook error fails the whole document.
+	for _, chunk := range chunks {
+		if err := h.runHooks(ctx, chunk); err != nil {
+			response.RespondError(c, http.StatusInternalServerError, models.CodeHookFailed, err)
+			return
+		}
+		h.capMetadata(chunk.Metadata)
+	}
+	if err := h.runHooks(ctx, analysis); err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeHookFailed, err)
+		return
+	}
+	metadataTruncated := h.capMetadata(analysis.Metadata)
+	
+	dbStart := time.Now()
+	if err := h.store(ctx).SaveChunkedAnalysisContext(ctx, analysis, chunks); err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	recordDBTiming(analysis, dbStart, startTime)
+	
+	resp := models.ChunkedAnalyzeResponse{
+		AnalyzeResponse: models.AnalyzeResponse{
+			ID:                analysis.ID,
+			Summary:           analysis.Summary,
+			Metadata:          analysis.Metadata,
+			Confidence:        analysis.Confidence,
+			ProcessingMS:      analysis.ProcessingMS,
+			MetadataTruncated: metadataTruncated,
+			Persisted:         true,
+		},
+		ChunkIDs: chunkIDs,
+	}
+	if includeText(c, false) {
+		resp.Text = analysis.Text
+	}
+	
+	response.JSON(c, http.StatusOK, resp)
+}
+
+func (h *Handler) respondChunkError(c *gin.Context, err error) {
+	if errors.Is(err, llm.ErrEmptyInput) {
+		response.RespondError(c, http.StatusBadRequest, models.CodeEmptyInput, err)
+		return
+	}
+	h.respondLLMError(c, err)
+}
+
+// analyzeChunks builds an unsaved analysis for every chunk, concurrently but
+// within the shared worker pool. The first chunk to fail cancels the others,
+// since the request fails anyway, and its error is returned.
+func (h *Handler) analyzeChunks(ctx context.Context, texts []string) ([]*models.TextAnalysis, []*llm.AnalysisResult, error) {
+	chunks := make([]*models.TextAnalysis, len(texts))
+	results := make([]*llm.AnalysisResult, len(texts))
+	
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	
+	var firstErr error
+	var failOnce sync.Once
+	fail := func(err error) {
+		failOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	
+	var wg sync.WaitGroup
+	for i, text := range texts {
+		wg.Add(1)
+		go func(index int, text string) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					log.Printf("Chunk %d panicked: %v", index, r)
+					fail(fmt.Errorf("chunk %d: internal error: %v", index, r))
+				}
+			}()
+			
+			startTime := time.Now()
+			result, err := h.analyzeWithPool(ctx, text)
+			if err != nil {
+				fail(fmt.Errorf("chunk %d: %w", index, err))
+				return
+			}
+			llmMS := time.Since(startTime).Milliseconds()
+			
+			language, keywords := h.extractKeywords(text, result.Topics, 0)
+			metadata := map[string]interface{}{
+				"title":           result.Title,
+				"topics":          result.Topics,
+				"sentiment":       result.Sentiment,
+				"sentiment_score": result.SentimentScore,
+				"keywords":        keywords,
+				"language":        language,
+				"llm_ms":          llmMS,
+				"chunk_index":     index,
+			}
+			h.recordProvider(metadata)
+			h.recordNode(metadata)
+			summary, summaryTruncated := h.capSummary(result.Summary)
+			if summaryTruncated {
+				metadata["summary_truncated"] = true
+			}
+			
+			results[index] = result
+			chunks[index] = &models.TextAnalysis{
+				ID:           uuid.New().String(),
+				Text:         text,
+				Summary:      summary,
+				Metadata:     metadata,
+				Confidence:   analyzer.CalculateConfidenceBreakdown(text, summary, result.Topics).Score,
+				CreatedAt:    time.Now(),
+				ProcessingMS: time.Since(startTime).Milliseconds(),
+			}
+		}(i, text)
+	}
+	wg.Wait()
+	
+	if firstErr != nil {
+		return nil, nil, firstErr
+	}
+	return chunks, results, nil
+}
+
+// mergeChunkTopics dedupes the chunks' topics, most frequent first and ties
+// in order of first appearance, so the topic cap keeps the document's main
+// themes.
+func mergeChunkTopics(results []*llm.AnalysisResult) []string {
+	counts := make(map[string]int)
+	var topics []string
+	for _, result := range results {
+		for _, topic := range result.Topics {
+			if counts[topic] == 0 {
+				topics = append(topics, topic)
+			}
+			counts[topic]++
+		}
+	}
+	
+	sort.SliceStable(topics, func(i, j int) bool {
+		return counts[topics[i]] > counts[topics[j]]
+	})
+	return topics
+}
\ No newline at end of file
@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/llm"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+// chunkProvider answers with the result registered for each text, failing
+// texts it does not know.
+type chunkProvider struct {
+	mu      sync.Mutex
+	results map[string]*llm.AnalysisResult
+	calls   []string
+}
+
+func (p *chunkProvider) Analyze(ctx context.Context, text string) (*llm.AnalysisResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	p.calls = append(p.calls, text)
+	if result, ok := p.results[text]; ok {
+		return result, nil
+	}
+	return nil, fmt.Errorf("%w: unexpected text %q", llm.ErrLLMUnavailable, text)
+}
+
+func (p *chunkProvider) IsAvailable() bool {
+	return true
+}
+
+func newChunkProvider() *chunkProvider {
+	return &chunkProvider{results: map[string]*llm.AnalysisResult{
+		"Go services talk to SQLite through database/sql.": {
+			Summary:   "Go services use SQLite.",
+			Topics:    []string{"Golang", "SQLite"},
+			Sentiment: "neutral",
+		},
+		"The Go HTTP server handles each request in a goroutine.": {
+			Summary:   "Go serves HTTP concurrently.",
+			Topics:    []string{"golang", "HTTP"},
+			Sentiment: "positive",
+		},
+		"Go services use SQLite.\n\nGo serves HTTP concurrently.": {
+			Summary:   "A Go service with SQLite storage and an HTTP server.",
+			Title:     "Go service overview",
+			Topics:    []string{"ignored"},
+			Sentiment: "positive",
+		},
+	}}
+}
+
+func TestHandler_AnalyzeChunked(t *testing.T) {
+	provider := newChunkProvider()
+	h, db := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/analyze/chunked", h.AnalyzeChunked)
+	
+	w := postJSON(r, "/analyze/chunked", models.ChunkedAnalyzeRequest{
+		Chunks: []string{
+			"Go services talk to SQLite through database/sql.",
+			"The Go HTTP server handles each request in a goroutine.",
+		},
+		Tags: []string{"Docs"},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var resp models.ChunkedAnalyzeResponse
+	decodeData(t, w, &resp)
+	require.Len(t, resp.ChunkIDs, 2)
+	assert.Equal(t, "A Go service with SQLite storage and an HTTP server.", resp.Summary)
+	assert.Equal(t, "Go service overview", resp.Metadata["title"])
+	assert.Equal(t, []interface{}{"golang", "sqlite", "http"}, resp.Metadata["topics"])
+	
+	stored, err := db.GetAnalysis(resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docs"}, stored.MetadataStrings("tags"))
+	assert.Equal(t, float64(2), stored.Metadata["chunk_count"])
+	
+	var confidenceSum float64
+	for i, id := range resp.ChunkIDs {
+		chunk, err := db.GetAnalysis(id)
+		require.NoError(t, err)
+		require.NotNil(t, chunk)
+		assert.Equal(t, resp.ID, chunk.Metadata["chunk_of"])
+		assert.Equal(t, float64(i), chunk.Metadata["chunk_index"])
+		confidenceSum += chunk.Confidence
+	}
+	assert.InDelta(t, confidenceSum/2, resp.Confidence, 1e-9)
+	assert.InDelta(t, confidenceSum/2, stored.Confidence, 1e-9)
+	
+	chunkIDs, err := db.GetChunkIDs(resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, resp.ChunkIDs, chunkIDs)
+}
+
+func TestHandler_AnalyzeChunked_Errors(t *testing.T) {
+	tooMany := make([]string, maxChunks+1)
+	for i := range tooMany {
+		tooMany[i] = "chunk"
+	}
+	
+	tests := []struct {
+		name   string
+		chunks []string
+		status int
+		code   models.ErrorCode
+	}{
+		{"no chunks", []string{}, http.StatusBadRequest, models.CodeInvalidRequest},
+		{"empty chunk", []string{"Go services talk to SQLite through database/sql.", " "}, http.StatusBadRequest, models.CodeEmptyInput},
+		{"too many chunks", tooMany, http.StatusBadRequest, models.CodeBatchSizeExceeded},
+		{"chunk fails", []string{"Go services talk to SQLite through database/sql.", "Unknown chunk."}, http.StatusServiceUnavailable, models.CodeLLMUnavailable},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, db := newTestHandler(t, newChunkProvider())
+			
+			r := gin.New()
+			r.POST("/analyze/chunked", h.AnalyzeChunked)
+			
+			w := postJSON(r, "/analyze/chunked", models.ChunkedAnalyzeRequest{Chunks: tt.chunks})
+			require.Equal(t, tt.status, w.Code, w.Body.String())
+			assert.Equal(t, tt.code, decodeError(t, w).Code)
+			
+			stored, err := db.GetRecentAnalyses(10)
+			require.NoError(t, err)
+			assert.Empty(t, stored)
+		})
+	}
+}
+
+// failFastProvider fails one text at once and holds every other call until
+// its context is cancelled.
+type failFastProvider struct {
+	failing   string
+	cancelled chan string
+}
+
+func (p *failFastProvider) Analyze(ctx context.Context, text string) (*llm.AnalysisResult, error) {
+	if text == p.failing {
+		return nil, fmt.Errorf("%w: chunk rejected", llm.ErrLLMUnavailable)
+	}
+	<-ctx.Done()
+	p.cancelled <- text
+	return nil, ctx.Err()
+}
+
+func (p *failFastProvider) IsAvailable() bool {
+	return true
+}
+
+func TestHandler_AnalyzeChunked_FirstFailureCancelsOthers(t *testing.T) {
+	provider := &failFastProvider{failing: "Bad chunk.", cancelled: make(chan string, 3)}
+	h, _ := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/analyze/chunked", h.AnalyzeChunked)
+	
+	finished := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		finished <- postJSON(r, "/analyze/chunked", models.ChunkedAnalyzeRequest{
+			Chunks: []string{"Slow chunk one.", "Bad chunk.", "Slow chunk two."},
+		})
+	}()
+	
+	select {
+	case w := <-finished:
+		require.Equal(t, http.StatusServiceUnavailable, w.Code, w.Body.String())
+		apiErr := decodeError(t, w)
+		assert.Equal(t, models.CodeLLMUnavailable, apiErr.Code)
+		assert.Contains(t, apiErr.Details, "chunk 1")
+	case <-time.After(5 * time.Second):
+		t.Fatal("the failed chunk did not cancel the others")
+	}
+	assert.Len(t, provider.cancelled, 2)
+}
+
+func TestMergeChunkTopics(t *testing.T) {
+	merged := mergeChunkTopics([]*llm.AnalysisResult{
+		{Topics: []string{"sqlite", "golang"}},
+		{Topics: []string{"http", "golang"}},
+		{Topics: []string{"http", "testing"}},
+	})
+	assert.Equal(t, []string{"golang", "http", "sqlite", "testing"}, merged)
+}
\ No newline at end of file
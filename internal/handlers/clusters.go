@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/analyzer"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+const (
+	// maxClusterCorpus bounds the cubic clustering step; only the most
+	// recent analyses matching the filter are clustered.
+	maxClusterCorpus      = 500
+	clusterTopicsPerGroup = 3
+)
+
+// ClusterAnalyses groups the stored analyses into at most ?k= clusters
+// (default 5) by the overlap of their topics and keywords. The usual search
+// filters narrow the corpus.
+func (h *Handler) ClusterAnalyses(c *gin.Context) {
+	var query models.ClusterQuery
+	
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	if !h.prepareFilter(c, &query.SearchFilter) {
+		return
+	}
+	
+	analyses, err := h.store(c.Request.Context()).SearchAnalysesContext(c.Request.Context(), models.SearchQuery{
+		SearchFilter: query.SearchFilter,
+		Limit:        maxClusterCorpus,
+	})
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	sets := make([][]string, len(analyses))
+	for i, analysis := range analyses {
+		for _, topic := range analysis.MetadataStrings("topics") {
+			sets[i] = append(sets[i], "topic:"+topic)
+		}
+		for _, keyword := range analysis.MetadataStrings("keywords") {
+			sets[i] = append(sets[i], "keyword:"+keyword)
+		}
+	}
+	
+	groups := analyzer.ClusterSets(sets, query.K)
+	clusters := make([]models.AnalysisCluster, 0, len(groups))
+	for _, group := range groups {
+		cluster := models.AnalysisCluster{Size: len(group)}
+		members := make([]*models.TextAnalysis, 0, len(group))
+		for _, index := range group {
+			cluster.AnalysisIDs = append(cluster.AnalysisIDs, analyses[index].ID)
+			members = append(members, analyses[index])
+		}
+		cluster.Topics = representativeTopics(members, clusterTopicsPerGroup)
+		clusters = append(clusters, cluster)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Size > clusters[j].Size
+	})
+	
+	response.List(c, http.StatusOK, "clusters", clusters, gin.H{
+		"k":           query.K,
+		"count":       len(clusters),
+		"corpus_size": len(analyses),
+		"truncated":   len(analyses) == maxClusterCorpus,
+	})
+}
+
+// representativeTopics returns up to limit topics shared by the most
+// analyses, breaking ties by first appearance.
+func representativeTopics(analyses []*models.TextAnalysis, limit int) []string {
+	counts := make(map[string]int)
+	var order []string
+	for _, analysis := range analyses {
+		for _, topic := range analysis.MetadataStrings("topics") {
+			if counts[topic] == 0 {
+				order = append(order, topic)
+			}
+			counts[topic]++
+		}
+	}
+	
+	sort.SliceStable(order, func(i, j int) bool {
+		return counts[order[i]] > counts[order[j]]
+	})
+	if len(order) > limit {
+		order = order[:limit]
+	}
+	if order == nil {
+		order = []string{}
+	}
+	return order
+}
\ No newline at end of file
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_ClusterAnalyses(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	seedAnalysis(t, db, "go-1", []string{"golang", "concurrency"})
+	seedAnalysis(t, db, "cook-1", []string{"cooking", "pasta"})
+	seedAnalysis(t, db, "go-2", []string{"golang", "concurrency", "channels"})
+	seedAnalysis(t, db, "cook-2", []string{"cooking", "pasta", "sauce"})
+	seedAnalysis(t, db, "go-3", []string{"golang", "channels"})
+	
+	r := gin.New()
+	r.GET("/analyses/clusters", h.ClusterAnalyses)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/analyses/clusters?k=2", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var clusters []models.AnalysisCluster
+	decodeData(t, w, &clusters)
+	require.Len(t, clusters, 2)
+	
+	golang, cooking := clusters[0], clusters[1]
+	assert.Equal(t, 3, golang.Size)
+	assert.ElementsMatch(t, []string{"go-1", "go-2", "go-3"}, golang.AnalysisIDs)
+	assert.Equal(t, "golang", golang.Topics[0])
+	assert.Equal(t, 2, cooking.Size)
+	assert.ElementsMatch(t, []string{"cook-1", "cook-2"}, cooking.AnalysisIDs)
+	assert.ElementsMatch(t, []string{"cooking", "pasta", "sauce"}, cooking.Topics)
+	
+	var meta map[string]interface{}
+	decodeMeta(t, w, &meta)
+	assert.Equal(t, float64(5), meta["corpus_size"])
+	assert.Equal(t, false, meta["truncated"])
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/analyses/clusters?k=2&topic=cooking", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	decodeData(t, w, &clusters)
+	assert.Len(t, clusters, 2)
+	decodeMeta(t, w, &meta)
+	assert.Equal(t, float64(2), meta["corpus_size"])
+	
+	for _, query := range []string{"k=0", "k=51", "k=abc"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/analyses/clusters?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Equal(t, models.CodeInvalidRequest, decodeError(t, w).Code)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,59 @@
+package handlers
+
+// Analysis components a client can select with AnalyzeRequest.Include.
+const (
+	componentSummary   = "summary"
+	componentTitle     = "title"
+	componentTopics    = "topics"
+	componentSentiment = "sentiment"
+	componentKeywords  = "keywords"
+	componentLanguage  = "language"
+)
+
+// allComponents lists every component in the order they are recorded.
+var allComponents = []string{componentSummary, componentTitle, componentTopics, componentSentiment, componentKeywords, componentLanguage}
+
+// llmComponents all come from the one provider call, so asking for any of
+// them runs it and leaving them all out skips it.
+var llmComponents = []string{componentSummary, componentTitle, componentTopics, componentSentiment}
+
+// componentSet is the set of components to compute for a request; nil means
+// every component.
+type componentSet map[string]bool
+
+func requestedComponents(include []string) componentSet {
+	if len(include) == 0 {
+		return nil
+	}
+	
+	set := make(componentSet, len(include))
+	for _, component := range include {
+		set[component] = true
+	}
+	return set
+}
+
+func (s componentSet) has(component string) bool {
+	return s == nil || s[component]
+}
+
+// names lists the selected components, for recording in the metadata which
+// ones a partial analysis computed.
+func (s componentSet) names() []string {
+	names := make([]string, 0, len(allComponents))
+	for _, component := range allComponents {
+		if s.has(component) {
+			names = append(names, component)
+		}
+	}
+	return names
+}
+
+func (s componentSet) needsLLM() bool {
+	for _, component := range llmComponents {
+		if s.has(component) {
+			return true
+		}
+	}
+	return false
+}
\ No newline at end of file
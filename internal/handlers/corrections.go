@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/database"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+// CorrectSentiment replaces the sentiment the LLM assigned to an analysis
+// and logs the original value and who corrected it. The analysis is marked
+// corrected; its sentiment_score is left as the LLM gave it.
+func (h *Handler) CorrectSentiment(c *gin.Context) {
+	analysis, ok := h.loadAnalysisPrimary(c)
+	if !ok {
+		return
+	}
+	
+	var req models.SentimentCorrectionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	sentiment := strings.ToLower(strings.TrimSpace(req.Sentiment))
+	if !h.sentiments.Contains(sentiment) {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, fmt.Errorf("unknown sentiment %q, must be one of: %s", sentiment, strings.Join(h.sentiments.Labels(), ", ")))
+		return
+	}
+	correctedBy := strings.TrimSpace(req.CorrectedBy)
+	if correctedBy == "" {
+		response.RespondError(c, http.StatusBadRequest, models.CodeEmptyInput, errors.New("corrected_by cannot be empty"))
+		return
+	}
+	
+	original, _ := analysis.Metadata["sentiment"].(string)
+	if sentiment == original {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, fmt.Errorf("sentiment is already %q", sentiment))
+		return
+	}
+	
+	analysis.Metadata["sentiment"] = sentiment
+	analysis.Metadata["corrected"] = true
+	correction := &models.Correction{
+		AnalysisID:  analysis.ID,
+		Field:       "sentiment",
+		Original:    original,
+		Corrected:   sentiment,
+		CorrectedBy: correctedBy,
+		CreatedAt:   time.Now().UTC(),
+	}
+	
+	ctx := c.Request.Context()
+	if err := h.store(ctx).CorrectAnalysisContext(ctx, analysis, correction); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			response.RespondError(c, http.StatusNotFound, models.CodeNotFound, nil)
+			return
+		}
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	response.JSON(c, http.StatusOK, gin.H{
+		"id":         analysis.ID,
+		"sentiment":  sentiment,
+		"corrected":  true,
+		"correction": correction,
+	})
+}
+
+// ListCorrections returns the corrections made to an analysis, oldest first,
+// for comparing the LLM's output with what analysts decided.
+func (h *Handler) ListCorrections(c *gin.Context) {
+	analysis, ok := h.loadAnalysisPrimary(c)
+	if !ok {
+		return
+	}
+	
+	corrections, err := h.store(c.Request.Context()).GetCorrectionsContext(c.Request.Context(), analysis.ID)
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	response.List(c, http.StatusOK, "corrections", corrections, gin.H{
+		"id":    analysis.ID,
+		"count": len(corrections),
+	})
+}
\ No newline at end of file
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_CorrectSentiment(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	seedAnalysis(t, db, "c1", []string{"billing"})
+	
+	r := gin.New()
+	r.POST("/analyses/:id/correct-sentiment", h.CorrectSentiment)
+	r.GET("/analyses/:id/corrections", h.ListCorrections)
+	r.GET("/analyses/:id/typed", h.GetAnalysisTyped)
+	
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	
+	w := postJSON(r, "/analyses/c1/correct-sentiment", models.SentimentCorrectionRequest{Sentiment: " Negative ", CorrectedBy: "alice"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp struct {
+		ID         string            `json:"id"`
+		Sentiment  string            `json:"sentiment"`
+		Corrected  bool              `json:"corrected"`
+		Correction models.Correction `json:"correction"`
+	}
+	decodeData(t, w, &resp)
+	assert.Equal(t, "negative", resp.Sentiment)
+	assert.True(t, resp.Corrected)
+	assert.Equal(t, "neutral", resp.Correction.Original)
+	assert.NotZero(t, resp.Correction.ID)
+	
+	stored, err := db.GetAnalysis("c1")
+	require.NoError(t, err)
+	assert.Equal(t, "negative", stored.Metadata["sentiment"])
+	assert.Equal(t, true, stored.Metadata["corrected"])
+	
+	history, err := db.GetAnalysisHistory("c1")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "neutral", history[0].Metadata["sentiment"])
+	
+	w = get("/analyses/c1/typed")
+	require.Equal(t, http.StatusOK, w.Code)
+	var typed models.TypedAnalysis
+	decodeData(t, w, &typed)
+	assert.True(t, typed.Metadata.Corrected)
+	
+	w = postJSON(r, "/analyses/c1/correct-sentiment", models.SentimentCorrectionRequest{Sentiment: "positive", CorrectedBy: "bob"})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	w = get("/analyses/c1/corrections")
+	require.Equal(t, http.StatusOK, w.Code)
+	var corrections []models.Correction
+	decodeData(t, w, &corrections)
+	require.Len(t, corrections, 2)
+	assert.Equal(t, models.Correction{
+		ID:          corrections[0].ID,
+		AnalysisID:  "c1",
+		Field:       "sentiment",
+		Original:    "neutral",
+		Corrected:   "negative",
+		CorrectedBy: "alice",
+		CreatedAt:   corrections[0].CreatedAt,
+	}, corrections[0])
+	assert.Equal(t, "negative", corrections[1].Original)
+	assert.Equal(t, "bob", corrections[1].CorrectedBy)
+}
+
+func TestHandler_CorrectSentiment_Invalid(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	seedAnalysis(t, db, "c1", []string{"billing"})
+	
+	r := gin.New()
+	r.POST("/analyses/:id/correct-sentiment", h.CorrectSentiment)
+	
+	tests := []struct {
+		name   string
+		path   string
+		req    models.SentimentCorrectionRequest
+		status int
+		code   models.ErrorCode
+	}{
+		{"Unknown sentiment", "/analyses/c1/correct-sentiment", models.SentimentCorrectionRequest{Sentiment: "furious", CorrectedBy: "alice"}, http.StatusBadRequest, models.CodeInvalidRequest},
+		{"Missing corrector", "/analyses/c1/correct-sentiment", models.SentimentCorrectionRequest{Sentiment: "negative"}, http.StatusBadRequest, models.CodeInvalidRequest},
+		{"Blank corrector", "/analyses/c1/correct-sentiment", models.SentimentCorrectionRequest{Sentiment: "negative", CorrectedBy: "  "}, http.StatusBadRequest, models.CodeEmptyInput},
+		{"Unchanged sentiment", "/analyses/c1/correct-sentiment", models.SentimentCorrectionRequest{Sentiment: "neutral", CorrectedBy: "alice"}, http.StatusBadRequest, models.CodeInvalidRequest},
+		{"Missing analysis", "/analyses/missing/correct-sentiment", models.SentimentCorrectionRequest{Sentiment: "negative", CorrectedBy: "alice"}, http.StatusNotFound, models.CodeNotFound},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postJSON(r, tt.path, tt.req)
+			require.Equal(t, tt.status, w.Code, w.Body.String())
+			assert.Equal(t, tt.code, decodeError(t, w).Code)
+		})
+	}
+	
+	corrections, err := db.GetCorrections("c1")
+	require.NoError(t, err)
+	assert.Empty(t, corrections)
+}
\ No newline at end of file
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"unicode/utf8"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+// Values for Config.InvalidUTF8. Reject is the default.
+const (
+	InvalidUTF8Reject   = "reject"
+	InvalidUTF8Sanitize = "sanitize"
+)
+
+// validateEncoding checks that data is valid UTF-8. Invalid sequences are
+// dropped in sanitize mode; in reject mode data is returned unchanged along
+// with the offset of the first invalid byte. The offset is -1 when data can
+// be used.
+func (h *Handler) validateEncoding(data []byte) ([]byte, int) {
+	if utf8.Valid(data) {
+		return data, -1
+	}
+	if h.config.InvalidUTF8 == InvalidUTF8Sanitize {
+		return bytes.ToValidUTF8(data, nil), -1
+	}
+	
+	offset := 0
+	for offset < len(data) {
+		r, size := utf8.DecodeRune(data[offset:])
+		if r == utf8.RuneError && size == 1 {
+			break
+		}
+		offset += size
+	}
+	return data, offset
+}
+
+// checkBodyEncoding validates the raw JSON body before it is bound, because
+// encoding/json silently replaces invalid UTF-8 with U+FFFD. A sanitized body
+// replaces the original so binding sees the cleaned text.
+func (h *Handler) checkBodyEncoding(c *gin.Context) bool {
+	body, err := c.GetRawData()
+	if err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, fmt.Errorf("failed to read request body: %w", err))
+		return false
+	}
+	
+	body, offset := h.validateEncoding(body)
+	if offset >= 0 {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidEncoding, fmt.Errorf("invalid UTF-8 at byte %d of the request body", offset))
+		return false
+	}
+	
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	return true
+}
\ No newline at end of file
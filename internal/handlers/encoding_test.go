@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+// postRaw sends body as is; postJSON would marshal invalid bytes to U+FFFD.
+func postRaw(r http.Handler, path, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+	return w
+}
+
+func TestHandler_InvalidUTF8_Reject(t *testing.T) {
+	h, _ := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	r.POST("/analyze/chunked", h.AnalyzeChunked)
+	r.POST("/batch-analyze/file", h.BatchAnalyzeFile)
+	
+	tests := []struct {
+		name string
+		path string
+		body string
+	}{
+		{"lone continuation byte", "/analyze", "{\"text\": \"caf\x80 au lait\"}"},
+		{"truncated sequence", "/analyze", "{\"text\": \"na\xc3\"}"},
+		{"overlong encoding", "/analyze", "{\"text\": \"slash \xc0\xaf\"}"},
+		{"surrogate half", "/analyze", "{\"text\": \"half \xed\xa0\x80\"}"},
+		{"batch item", "/batch-analyze", "{\"texts\": [\"fine\", \"bad \xff byte\"]}"},
+		{"chunk", "/analyze/chunked", "{\"chunks\": [\"bad \xfe byte\"]}"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postRaw(r, tt.path, tt.body)
+			require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+			apiErr := decodeError(t, w)
+			assert.Equal(t, models.CodeInvalidEncoding, apiErr.Code)
+			assert.False(t, apiErr.Retryable)
+		})
+	}
+	
+	t.Run("batch file", func(t *testing.T) {
+		w := postFile(r, "/batch-analyze/file", "good line\nbad \xff line\n", nil)
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		apiErr := decodeError(t, w)
+		assert.Equal(t, models.CodeInvalidEncoding, apiErr.Code)
+		assert.Contains(t, apiErr.Details, "line 2")
+	})
+}
+
+func TestHandler_InvalidUTF8_Sanitize(t *testing.T) {
+	h, db := newTestHandlerWithConfig(t, newStubProvider(), Config{InvalidUTF8: InvalidUTF8Sanitize})
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	w := postRaw(r, "/analyze", "{\"text\": \"caf\xc3\xa9 \x80au lait\"}")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var resp models.AnalyzeResponse
+	decodeData(t, w, &resp)
+	stored, err := db.GetAnalysis(resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "caf\u00e9 au lait", stored.Text)
+	
+	w = postRaw(r, "/batch-analyze", "{\"texts\": [\"one\", \"t\xffwo\"]}")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var batch models.BatchAnalyzeResponse
+	decodeData(t, w, &batch)
+	assert.Equal(t, 2, batch.SuccessCount)
+	
+	w = postRaw(r, "/analyze", "{\"text\": \"\xff\xfe\"}")
+	assert.Equal(t, http.StatusBadRequest, w.Code, "text that is only invalid bytes is empty once sanitized")
+}
\ No newline at end of file
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+func newEnvelopeRouter(t *testing.T) *gin.Engine {
+	h, db := newTestHandler(t, newStubProvider())
+	seedAnalysis(t, db, "a1", []string{"golang"})
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	r.POST("/batch-analyze/retry", h.RetryBatchAnalyze)
+	r.POST("/batch-analyze/file", h.BatchAnalyzeFile)
+	r.GET("/search", h.SearchAnalyses)
+	r.DELETE("/analyses", h.DeleteAnalyses)
+	r.GET("/analyses/:id/markdown", h.ExportMarkdown)
+	r.GET("/analyses/:id/history", h.GetAnalysisHistory)
+	r.POST("/analyses/:id/reextract-keywords", h.ReextractKeywords)
+	r.POST("/analyses/reextract-keywords", h.BulkReextractKeywords)
+	
+	return r
+}
+
+func TestHandler_ResponsesUseEnvelope(t *testing.T) {
+	r := newEnvelopeRouter(t)
+	
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		body    string
+		isError bool
+	}{
+		{"analyze", http.MethodPost, "/analyze", `{"text":"Some text to analyze."}`, false},
+		{"analyze invalid", http.MethodPost, "/analyze", `{}`, true},
+		{"batch", http.MethodPost, "/batch-analyze", `{"texts":["one text","two text"]}`, false},
+		{"batch empty item", http.MethodPost, "/batch-analyze", `{"texts":["one text"," "]}`, true},
+		{"batch retry", http.MethodPost, "/batch-analyze/retry", `{"texts":["one text"],"previous":{}}`, false},
+		{"batch file missing", http.MethodPost, "/batch-analyze/file", ``, true},
+		{"search", http.MethodGet, "/search?topic=golang", ``, false},
+		{"search invalid cursor", http.MethodGet, "/search?cursor=garbage!", ``, true},
+		{"delete", http.MethodDelete, "/analyses?topic=none&confirm=true", ``, false},
+		{"delete unconfirmed", http.MethodDelete, "/analyses?topic=none", ``, true},
+		{"markdown missing", http.MethodGet, "/analyses/missing/markdown", ``, true},
+		{"history", http.MethodGet, "/analyses/a1/history", ``, false},
+		{"history missing", http.MethodGet, "/analyses/missing/history", ``, true},
+		{"reextract", http.MethodPost, "/analyses/a1/reextract-keywords", ``, false},
+		{"bulk reextract", http.MethodPost, "/analyses/reextract-keywords?all=true", ``, false},
+		{"bulk reextract unfiltered", http.MethodPost, "/analyses/reextract-keywords", ``, true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			assert.Equal(t, tt.isError, w.Code >= http.StatusBadRequest, w.Body.String())
+			
+			var body map[string]json.RawMessage
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+			require.Contains(t, body, "data")
+			for key := range body {
+				assert.Contains(t, []string{"data", "meta", "error"}, key)
+			}
+			
+			if tt.isError {
+				assert.Equal(t, "null", string(body["data"]))
+				assert.NotEmpty(t, decodeError(t, w).Code)
+			} else {
+				assert.NotContains(t, body, "error")
+			}
+		})
+	}
+}
+
+func TestHandler_LegacyResponseFormat(t *testing.T) {
+	r := newEnvelopeRouter(t)
+	
+	req := httptest.NewRequest(http.MethodGet, "/search?topic=golang", nil)
+	req.Header.Set(response.FormatHeader, response.FormatLegacy)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var search struct {
+		Results []models.TextAnalysis `json:"results"`
+		Count   int                   `json:"count"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &search))
+	assert.Equal(t, 1, search.Count)
+	assert.Len(t, search.Results, 1)
+	
+	req = httptest.NewRequest(http.MethodGet, "/analyses/missing/history", nil)
+	req.Header.Set(response.FormatHeader, response.FormatLegacy)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	
+	var errResp models.ErrorResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &errResp))
+	assert.Equal(t, models.CodeNotFound, errResp.Code)
+}
+
+func TestHandler_SearchAnalyses_NoMatchesIsEmptyArray(t *testing.T) {
+	r := newEnvelopeRouter(t)
+	
+	for _, format := range []string{"", response.FormatLegacy} {
+		req := httptest.NewRequest(http.MethodGet, "/search?topic=missing", nil)
+		if format != "" {
+			req.Header.Set(response.FormatHeader, format)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		
+		var body map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		key := "data"
+		if format == response.FormatLegacy {
+			key = "results"
+		}
+		assert.Equal(t, "[]", string(body[key]), format)
+	}
+}
+
+func TestHandler_AnalyzeText_PrettyJSON(t *testing.T) {
+	r := newEnvelopeRouter(t)
+	
+	compact := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "Some text to analyze."})
+	pretty := postJSON(r, "/analyze?pretty=true", models.AnalyzeRequest{Text: "Some text to analyze."})
+	require.Equal(t, http.StatusOK, compact.Code)
+	require.Equal(t, http.StatusOK, pretty.Code)
+	
+	assert.NotContains(t, compact.Body.String(), "\n")
+	assert.True(t, strings.HasPrefix(pretty.Body.String(), "{\n    \"data\": {\n        \""), pretty.Body.String())
+	
+	var compactResp, prettyResp models.AnalyzeResponse
+	decodeData(t, compact, &compactResp)
+	decodeData(t, pretty, &prettyResp)
+	assert.Equal(t, compactResp.Summary, prettyResp.Summary)
+	assert.Equal(t, compactResp.Metadata["topics"], prettyResp.Metadata["topics"])
+	assert.Equal(t, compactResp.Confidence, prettyResp.Confidence)
+}
\ No newline at end of file
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`,
+	`{`, `\{`, `}`, `\}`, `[`, `\[`, `]`, `\]`,
+	`(`, `\(`, `)`, `\)`, `#`, `\#`, `+`, `\+`,
+	`-`, `\-`, `.`, `\.`, `!`, `\!`, `|`, `\|`,
+	`<`, `\<`, `>`, `\>`,
+)
+
+func (h *Handler) ExportMarkdown(c *gin.Context) {
+	analysis, ok := h.loadAnalysis(c)
+	if !ok {
+		return
+	}
+	
+	c.Data(http.StatusOK, "text/markdown; charset=utf-8", []byte(renderMarkdown(analysis)))
+}
+
+// ExportNDJSON streams every analysis matching the search filters as
+// newline-delimited JSON, one analysis with its full metadata per line. Rows
+// are written as they are read, so exports of any size use constant memory.
+func (h *Handler) ExportNDJSON(c *gin.Context) {
+	var filter models.SearchFilter
+	
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	if !h.prepareFilter(c, &filter) {
+		return
+	}
+	
+	stream, err := h.store(c.Request.Context()).SearchAnalysesStreamContext(c.Request.Context(), models.SearchQuery{SearchFilter: filter})
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	c.Header("Content-Type", "application/x-ndjson")
+	c.Status(http.StatusOK)
+	
+	fields := metadataFields(c)
+	encoder := json.NewEncoder(c.Writer)
+	exported := 0
+	stream(func(analysis *models.TextAnalysis, err error) bool {
+		if err == nil {
+			analysis.Metadata = projectMetadata(analysis.Metadata, fields)
+			err = encoder.Encode(analysis)
+		}
+		if err != nil {
+			// The status line is already sent; all we can do is stop early.
+			log.Printf("NDJSON export stopped after %d analyses: %v", exported, err)
+			return false
+		}
+		
+		exported++
+		if exported%100 == 0 {
+			c.Writer.Flush()
+		}
+		return true
+	})
+	c.Writer.Flush()
+}
+
+// zipManifestEntry indexes one analysis file in an export bundle.
+type zipManifestEntry struct {
+	ID        string    `json:"id"`
+	File      string    `json:"file"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type zipManifest struct {
+	ExportedAt time.Time          `json:"exported_at"`
+	Count      int                `json:"count"`
+	Analyses   []zipManifestEntry `json:"analyses"`
+}
+
+// ExportZip streams every analysis matching the search filters as a zip
+// bundle: one {id}.json file per analysis followed by manifest.json indexing
+// them. Analyses are compressed as they are read; only the small manifest
+// entries are held in memory.
+func (h *Handler) ExportZip(c *gin.Context) {
+	var filter models.SearchFilter
+	
+	if err := c.ShouldBindQuery(&filter); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	if !h.prepareFilter(c, &filter) {
+		return
+	}
+	
+	stream, err := h.store(c.Request.Context()).SearchAnalysesStreamContext(c.Request.Context(), models.SearchQuery{SearchFilter: filter})
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="analyses.zip"`)
+	c.Status(http.StatusOK)
+	
+	archive := zip.NewWriter(c.Writer)
+	manifest := zipManifest{ExportedAt: time.Now().UTC(), Analyses: []zipManifestEntry{}}
+	failed := false
+	stream(func(analysis *models.TextAnalysis, err error) bool {
+		var entry zipManifestEntry
+		if err == nil {
+			entry = zipManifestEntry{ID: analysis.ID, File: analysis.ID + ".json", CreatedAt: analysis.CreatedAt}
+			err = writeZipJSON(archive, entry.File, analysis)
+		}
+		if err != nil {
+			// The status line is already sent; all we can do is stop early.
+			log.Printf("Zip export stopped after %d analyses: %v", len(manifest.Analyses), err)
+			failed = true
+			return false
+		}
+		
+		entry.Title, _ = analysis.Metadata["title"].(string)
+		manifest.Analyses = append(manifest.Analyses, entry)
+		if len(manifest.Analyses)%100 == 0 {
+			c.Writer.Flush()
+		}
+		return true
+	})
+	if failed {
+		return
+	}
+	
+	manifest.Count = len(manifest.Analyses)
+	if err := writeZipJSON(archive, "manifest.json", manifest); err != nil {
+		log.Printf("Zip export failed writing manifest: %v", err)
+		return
+	}
+	if err := archive.Close(); err != nil {
+		log.Printf("Zip export failed to finish archive: %v", err)
+	}
+	c.Writer.Flush()
+}
+
+func writeZipJSON(archive *zip.Writer, name string, v interface{}) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+func (h *Handler) loadAnalysis(c *gin.Context) (*models.TextAnalysis, bool) {
+	return h.respondAnalysis(c, h.store(c.Request.Context()).GetAnalysisContext)
+}
+
+// loadAnalysisPrimary is loadAnalysis against the primary, for handlers that
+// write back what they read or go on to read tables kept on the primary; a
+// lagging replica would hand them stale metadata or a spurious 404.
+func (h *Handler) loadAnalysisPrimary(c *gin.Context) (*models.TextAnalysis, bool) {
+	return h.respondAnalysis(c, h.store(c.Request.Context()).GetAnalysisPrimaryContext)
+}
+
+func (h *Handler) respondAnalysis(c *gin.Context, get func(context.Context, string) (*models.TextAnalysis, error)) (*models.TextAnalysis, bool) {
+	analysis, err := get(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return nil, false
+	}
+	
+	if analysis == nil {
+		response.RespondError(c, http.StatusNotFound, models.CodeNotFound, nil)
+		return nil, false
+	}
+	
+	return analysis, true
+}
+
+func renderMarkdown(analysis *models.TextAnalysis) string {
+	var b strings.Builder
+	
+	title, _ := analysis.Metadata["title"].(string)
+	if title == "" {
+		title = "Untitled Analysis"
+	}
+	
+	fmt.Fprintf(&b, "# %s\n\n", escapeMarkdown(title))
+	fmt.Fprintf(&b, "%s\n\n", escapeMarkdown(analysis.Summary))
+	
+	writeMarkdownList(&b, "Topics", analysis.MetadataStrings("topics"))
+	writeMarkdownList(&b, "Keywords", analysis.MetadataStrings("keywords"))
+	
+	sentiment, _ := analysis.Metadata["sentiment"].(string)
+	if sentiment == "" {
+		sentiment = "unknown"
+	}
+	
+	fmt.Fprintf(&b, "**Sentiment:** %s  \n", escapeMarkdown(sentiment))
+	fmt.Fprintf(&b, "**Confidence:** %.2f  \n", analysis.Confidence)
+	fmt.Fprintf(&b, "**Created:** %s\n", analysis.CreatedAt.UTC().Format(time.RFC3339))
+	
+	return b.String()
+}
+
+func writeMarkdownList(b *strings.Builder, heading string, items []string) {
+	fmt.Fprintf(b, "## %s\n\n", heading)
+	
+	if len(items) == 0 {
+		b.WriteString("_None_\n\n")
+		return
+	}
+	
+	for _, item := range items {
+		fmt.Fprintf(b, "- %s\n", escapeMarkdown(item))
+	}
+	b.WriteString("\n")
+}
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
\ No newline at end of file
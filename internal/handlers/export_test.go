@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_ExportMarkdown(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	
+	err := db.SaveAnalysis(&models.TextAnalysis{
+		ID:      "md-1",
+		Text:    "Original text",
+		Summary: "Uses *bold* claims and [links](http://example.com).",
+		Metadata: map[string]interface{}{
+			"title":     "C# & F_Sharp #1",
+			"topics":    []string{"programming", "c++"},
+			"keywords":  []string{"language", "compiler"},
+			"sentiment": "positive",
+		},
+		Confidence: 0.85,
+		CreatedAt:  time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	
+	r := gin.New()
+	r.GET("/analyses/:id/markdown", h.ExportMarkdown)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/analyses/md-1/markdown", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "text/markdown")
+	
+	expected := "# C\\# & F\\_Sharp \\#1\n\n" +
+		"Uses \\*bold\\* claims and \\[links\\]\\(http://example\\.com\\)\\.\n\n" +
+		"## Topics\n\n- programming\n- c\\+\\+\n\n" +
+		"## Keywords\n\n- language\n- compiler\n\n" +
+		"**Sentiment:** positive  \n" +
+		"**Confidence:** 0.85  \n" +
+		"**Created:** 2024-03-01T12:30:00Z\n"
+	assert.Equal(t, expected, w.Body.String())
+}
+
+func TestHandler_ExportMarkdown_NotFound(t *testing.T) {
+	h, _ := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.GET("/analyses/:id/markdown", h.ExportMarkdown)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/analyses/missing/markdown", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandler_ExportNDJSON(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	for i := 0; i < 5; i++ {
+		seedAnalysis(t, db, fmt.Sprintf("nd-%d", i), []string{"golang"})
+	}
+	seedAnalysis(t, db, "other", []string{"rust"})
+	
+	r := gin.New()
+	r.GET("/export.ndjson", h.ExportNDJSON)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export.ndjson?topic=Golang", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	
+	lines := strings.Split(strings.TrimSuffix(w.Body.String(), "\n"), "\n")
+	require.Len(t, lines, 5)
+	for _, line := range lines {
+		var analysis models.TextAnalysis
+		require.NoError(t, json.Unmarshal([]byte(line), &analysis), line)
+		assert.Contains(t, analysis.ID, "nd-")
+		assert.Equal(t, []string{"golang"}, analysis.MetadataStrings("topics"))
+	}
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export.ndjson?topic=none", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestHandler_ExportZip(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	for i := 0; i < 3; i++ {
+		seedAnalysis(t, db, fmt.Sprintf("zip-%d", i), []string{"golang"})
+	}
+	seedAnalysis(t, db, "other", []string{"rust"})
+	
+	r := gin.New()
+	r.GET("/export.zip", h.ExportZip)
+	
+	export := func(query string) *zip.Reader {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export.zip"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+		
+		archive, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+		require.NoError(t, err)
+		return archive
+	}
+	
+	readJSON := func(f *zip.File, v interface{}) {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		defer rc.Close()
+		require.NoError(t, json.NewDecoder(rc).Decode(v))
+	}
+	
+	archive := export("?topic=Golang")
+	require.Len(t, archive.File, 4)
+	
+	files := make(map[string]*zip.File)
+	for _, f := range archive.File {
+		files[f.Name] = f
+	}
+	require.Contains(t, files, "manifest.json")
+	
+	var manifest zipManifest
+	readJSON(files["manifest.json"], &manifest)
+	assert.Equal(t, 3, manifest.Count)
+	require.Len(t, manifest.Analyses, 3)
+	for _, entry := range manifest.Analyses {
+		assert.Contains(t, entry.ID, "zip-")
+		assert.Equal(t, entry.ID+".json", entry.File)
+		require.Contains(t, files, entry.File)
+		
+		var analysis models.TextAnalysis
+		readJSON(files[entry.File], &analysis)
+		assert.Equal(t, entry.ID, analysis.ID)
+		assert.Equal(t, "Summary for "+entry.ID, analysis.Summary)
+		assert.Equal(t, []string{"golang"}, analysis.MetadataStrings("topics"))
+	}
+	
+	archive = export("?topic=none")
+	require.Len(t, archive.File, 1)
+	var empty zipManifest
+	readJSON(archive.File[0], &empty)
+	assert.Equal(t, 0, empty.Count)
+	assert.Empty(t, empty.Analyses)
+}
\ No newline at end of file
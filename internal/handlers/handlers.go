@@ -2,10 +2,17 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
+	"unicode/utf8"
 	
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -13,258 +20,964 @@ import (
 	"github.com/user/llm-knowledge-extractor/internal/database"
 	"github.com/user/llm-knowledge-extractor/internal/llm"
 	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
 )
 
+const maxBatchSize = 10
+
+// facetTopicLimit is how many of the most common topics search facets list.
+const facetTopicLimit = 10
+
+type Config struct {
+	IdempotencyTTL          time.Duration
+	ConfidenceWarnThreshold float64
+	MinConfidenceToStore    float64
+	MaxSummaryChars         int
+	BatchMaxFailures        int
+	BatchMaxTotalChars      int
+	BatchItemTimeout        time.Duration
+	WorkerPoolSize          int
+	SearchDefaultLimit      int
+	SearchMaxLimit          int
+	TopicAliases            map[string]string
+	ValidSentiments         []string
+	StopWordsFile           string
+	MaxMetadataItems        int
+	MaxTextLength           int
+	KeywordMinLength        int
+	DisableKeywordBackfill  bool
+	RetryAfter              time.Duration
+	InvalidUTF8             string
+	LLMProvider             string
+	LLMModel                string
+	ResultCacheTTL          time.Duration
+	NodeID                  string
+	// ProviderConcurrency caps, by provider name, how many calls a provider
+	// may have in flight at once on top of the shared worker pool. Providers
+	// without a positive cap are bounded by the pool alone.
+	ProviderConcurrency map[string]int
+}
+
+func (c Config) Validate() error {
+	if c.SearchDefaultLimit > c.SearchMaxLimit {
+		return fmt.Errorf("search default limit %d exceeds max limit %d", c.SearchDefaultLimit, c.SearchMaxLimit)
+	}
+	if c.MinConfidenceToStore < 0 || c.MinConfidenceToStore > 1 {
+		return fmt.Errorf("minimum confidence to store %v must be between 0 and 1", c.MinConfidenceToStore)
+	}
+	switch c.InvalidUTF8 {
+	case "", InvalidUTF8Reject, InvalidUTF8Sanitize:
+	default:
+		return fmt.Errorf("invalid UTF-8 mode %q, expected %s or %s", c.InvalidUTF8, InvalidUTF8Reject, InvalidUTF8Sanitize)
+	}
+	return nil
+}
+
+// keywordStrategy ranks keyword candidates for a text. Each supported
+// language has its own; *analyzer.KeywordExtractor is the implementation.
+type keywordStrategy interface {
+	ExtractKeywords(text string, topN int) []string
+	ExtractKeywordsWithMinLength(text string, topN, minLength int) []string
+}
+
 type Handler struct {
-	db               *database.DB
-	llmProvider      llm.Provider
-	keywordExtractor *analyzer.KeywordExtractor
+	db                *database.DB
+	llmProvider       llm.Provider
+	keywordExtractors map[string]keywordStrategy
+	topicNormalizer   *analyzer.TopicNormalizer
+	sentiments        llm.SentimentSet
+	config            Config
+	idempotencyLocks  *keyedMutex
+	workerPool        chan struct{}
+	defaultSlots      chan struct{}
+	hooks             []AnalysisHook
+	backends          map[string]backend
+	results           *resultCache
 }
 
-func New(db *database.DB, llmProvider llm.Provider) *Handler {
+func New(db *database.DB, llmProvider llm.Provider, config Config) *Handler {
+	if config.IdempotencyTTL <= 0 {
+		config.IdempotencyTTL = 24 * time.Hour
+	}
+	if config.WorkerPoolSize <= 0 {
+		config.WorkerPoolSize = 5
+	}
+	if config.SearchDefaultLimit <= 0 {
+		config.SearchDefaultLimit = 50
+	}
+	if config.SearchMaxLimit <= 0 {
+		config.SearchMaxLimit = 100
+	}
+	if config.MaxMetadataItems <= 0 {
+		config.MaxMetadataItems = 20
+	}
+	if config.MaxTextLength <= 0 {
+		config.MaxTextLength = 50000
+	}
+	if config.RetryAfter <= 0 {
+		config.RetryAfter = 5 * time.Second
+	}
+	if config.BatchItemTimeout <= 0 {
+		config.BatchItemTimeout = 30 * time.Second
+	}
+	providerConcurrency := make(map[string]int, len(config.ProviderConcurrency))
+	for name, limit := range config.ProviderConcurrency {
+		providerConcurrency[strings.ToLower(strings.TrimSpace(name))] = limit
+	}
+	config.ProviderConcurrency = providerConcurrency
+	
+	keywordExtractors := make(map[string]keywordStrategy)
+	for language, extractor := range analyzer.NewLanguageKeywordExtractors(config.StopWordsFile) {
+		extractor.SetMinWordLength(config.KeywordMinLength)
+		keywordExtractors[language] = extractor
+	}
+	
 	return &Handler{
-		db:               db,
-		llmProvider:      llmProvider,
-		keywordExtractor: analyzer.NewKeywordExtractor(),
+		db:                db,
+		llmProvider:       llmProvider,
+		keywordExtractors: keywordExtractors,
+		topicNormalizer:   analyzer.NewTopicNormalizer(config.TopicAliases),
+		sentiments:        llm.NewSentimentSet(config.ValidSentiments),
+		config:            config,
+		idempotencyLocks:  newKeyedMutex(),
+		workerPool:        make(chan struct{}, config.WorkerPoolSize),
+		defaultSlots:      providerSlots(config, config.LLMProvider),
+		backends:          make(map[string]backend),
+		results:           newResultCache(config.ResultCacheTTL),
+	}
+}
+
+// store is the database for a request: the tenant's when the tenant
+// middleware chose one, otherwise the handler's own.
+func (h *Handler) store(ctx context.Context) *database.DB {
+	if db, ok := database.FromContext(ctx); ok {
+		return db
 	}
+	return h.db
+}
+
+func (h *Handler) analyzeWithPool(ctx context.Context, text string) (*llm.AnalysisResult, error) {
+	return h.analyzeWith(ctx, h.defaultBackend(), text)
+}
+
+// analyzeWith runs text through the provider of b, sharing the worker pool
+// with every other provider. A call takes a slot of its provider's own cap
+// before a pool slot, so calls queued behind a slow provider never hold pool
+// slots the other providers could use.
+func (h *Handler) analyzeWith(ctx context.Context, b backend, text string) (*llm.AnalysisResult, error) {
+	return h.analyzeWithin(ctx, b, text, 0)
+}
+
+// analyzeWithin is analyzeWith with a timeout on the provider call that only
+// starts once the slots are held, so time spent queued doesn't count against
+// it. A zero timeout leaves ctx's deadline as the only limit.
+func (h *Handler) analyzeWithin(ctx context.Context, b backend, text string, timeout time.Duration) (*llm.AnalysisResult, error) {
+	if b.slots != nil {
+		select {
+		case b.slots <- struct{}{}:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		defer func() { <-b.slots }()
+	}
+	
+	select {
+	case h.workerPool <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	defer func() { <-h.workerPool }()
+	
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	
+	result, err := b.provider.Analyze(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	
+	resolved := *result
+	resolved.Topics = h.topicNormalizer.NormalizeAll(result.Topics)
+	
+	// The provider's sentiment wins when it gave a usable one; otherwise fall
+	// back to the offline lexicon so every analysis carries a value.
+	if !h.sentiments.Contains(resolved.Sentiment) {
+		var label string
+		label, resolved.SentimentScore = analyzer.AnalyzeSentiment(text)
+		resolved.Sentiment = h.sentiments.Resolve(label)
+	}
+	
+	return &resolved, nil
+}
+
+// keywordExtractor picks the extractor for the detected language of text,
+// falling back to English when the language has no extractor.
+func (h *Handler) keywordExtractor(text string) (string, keywordStrategy) {
+	language := analyzer.DetectLanguage(text)
+	extractor, ok := h.keywordExtractors[language]
+	if !ok {
+		language = analyzer.DefaultLanguage
+		extractor = h.keywordExtractors[language]
+	}
+	return language, extractor
+}
+
+// extractKeywords drops keywords that repeat one of the topics. A positive
+// minLength overrides the configured minimum keyword length. Keywords are a
+// local extra on top of the LLM result, so a panic here is logged and the
+// analysis goes ahead with no keywords instead of failing.
+func (h *Handler) extractKeywords(text string, topics []string, minLength int) (language string, keywords []string) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Keyword extraction panicked, continuing without keywords: %v", r)
+			if language == "" {
+				language = analyzer.DefaultLanguage
+			}
+			keywords = []string{}
+		}
+	}()
+	
+	language, extractor := h.keywordExtractor(text)
+	
+	const keywordCount = 3
+	candidates := extractor.ExtractKeywordsWithMinLength(text, keywordCount+len(topics), minLength)
+	return language, analyzer.SuppressTopicKeywords(candidates, topics, keywordCount, !h.config.DisableKeywordBackfill)
 }
 
 func (h *Handler) AnalyzeText(c *gin.Context) {
 	var req models.AnalyzeRequest
 	
+	if !h.checkBodyEncoding(c) {
+		return
+	}
+	
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request format",
-			Code:    "INVALID_REQUEST",
-			Details: err.Error(),
-		})
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
 		return
 	}
 	
-	if req.Text == "" {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error: "Text cannot be empty",
-			Code:  "EMPTY_INPUT",
-		})
+	req.Text = analyzer.NormalizeText(req.Text)
+	if h.rejectText(c, "text", req.Text) {
 		return
 	}
 	
+	selected, err := h.backend(req.Provider)
+	if err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	idempotencyKey := c.GetHeader("Idempotency-Key")
+	if idempotencyKey != "" {
+		unlock := h.idempotencyLocks.Lock(idempotencyKey)
+		defer unlock()
+		
+		if h.replayIdempotent(c, idempotencyKey) {
+			return
+		}
+	}
+	
 	startTime := time.Now()
 	
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 45*time.Second)
 	defer cancel()
 	
-	llmResult, err := h.llmProvider.Analyze(ctx, req.Text)
-	if err != nil {
-		if err == llm.ErrEmptyInput {
-			c.JSON(http.StatusBadRequest, models.ErrorResponse{
-				Error: "Text cannot be empty",
-				Code:  "EMPTY_INPUT",
-			})
+	components := requestedComponents(req.Include)
+	cacheKey := resultCacheKey(req.Text, selected.name, components, req.KeywordMinLength)
+	cached, hit := h.results.get(cacheKey)
+	
+	llmResult := &llm.AnalysisResult{}
+	var llmMS int64
+	switch {
+	case hit:
+		llmResult = &cached.llm
+	case components.needsLLM():
+		llmStart := time.Now()
+		result, err := h.analyzeWith(ctx, selected, req.Text)
+		llmMS = time.Since(llmStart).Milliseconds()
+		if err != nil {
+			if err == llm.ErrEmptyInput {
+				response.RespondError(c, http.StatusBadRequest, models.CodeEmptyInput, nil)
+				return
+			}
+			
+			h.respondLLMError(c, err)
 			return
 		}
-		
-		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{
-			Error:   "LLM service unavailable",
-			Code:    "LLM_UNAVAILABLE",
-			Details: err.Error(),
-		})
-		return
+		llmResult = result
 	}
 	
-	keywords := h.keywordExtractor.ExtractKeywords(req.Text, 3)
-	
 	metadata := map[string]interface{}{
-		"title":     llmResult.Title,
-		"topics":    llmResult.Topics,
-		"sentiment": llmResult.Sentiment,
-		"keywords":  keywords,
+		"llm_ms": llmMS,
+	}
+	if components != nil {
+		// Stored so reads don't fill in defaults for what was skipped.
+		metadata["components"] = components.names()
+	}
+	h.recordNode(metadata)
+	summary, summaryTruncated := h.capSummary(llmResult.Summary)
+	if summaryTruncated && components.has(componentSummary) {
+		metadata["summary_truncated"] = true
+	}
+	if components.needsLLM() {
+		recordBackend(metadata, selected)
+	}
+	if components.has(componentTitle) {
+		metadata["title"] = llmResult.Title
+	}
+	if components.has(componentTopics) {
+		metadata["topics"] = llmResult.Topics
+	}
+	if components.has(componentSentiment) {
+		metadata["sentiment"] = llmResult.Sentiment
+		metadata["sentiment_score"] = llmResult.SentimentScore
 	}
 	
-	confidence := analyzer.CalculateConfidence(req.Text, llmResult.Summary, llmResult.Topics)
+	switch {
+	case hit:
+		if components.has(componentKeywords) {
+			metadata["keywords"] = cached.keywords
+			metadata["keyword_ms"] = int64(0)
+		}
+		if components.has(componentLanguage) {
+			metadata["language"] = cached.language
+		}
+	case components.has(componentKeywords):
+		keywordStart := time.Now()
+		cached.language, cached.keywords = h.extractKeywords(req.Text, llmResult.Topics, req.KeywordMinLength)
+		metadata["keywords"] = cached.keywords
+		metadata["keyword_ms"] = time.Since(keywordStart).Milliseconds()
+		if components.has(componentLanguage) {
+			metadata["language"] = cached.language
+		}
+	case components.has(componentLanguage):
+		cached.language, _ = h.keywordExtractor(req.Text)
+		metadata["language"] = cached.language
+	}
+	if !hit {
+		cached.llm = *llmResult
+		h.results.put(cacheKey, cached)
+	}
+	
+	if tags := normalizeTags(req.Tags); len(tags) > 0 {
+		metadata["tags"] = tags
+	}
+	
+	// Confidence rates the provider's answer, so it stays zero when the
+	// provider was skipped.
+	var breakdown analyzer.ConfidenceBreakdown
+	if components.needsLLM() {
+		breakdown = analyzer.CalculateConfidenceBreakdown(req.Text, summary, llmResult.Topics)
+	}
+	
+	if !components.has(componentSummary) {
+		summary = ""
+	}
 	
 	analysis := &models.TextAnalysis{
 		ID:           uuid.New().String(),
 		Text:         req.Text,
-		Summary:      llmResult.Summary,
+		Summary:      summary,
 		Metadata:     metadata,
-		Confidence:   confidence,
+		Confidence:   breakdown.Score,
 		CreatedAt:    time.Now(),
 		ProcessingMS: time.Since(startTime).Milliseconds(),
 	}
 	
-	if err := h.db.SaveAnalysis(analysis); err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Failed to save analysis",
-			Code:    "DB_ERROR",
-			Details: err.Error(),
-		})
+	if err := h.runHooks(ctx, analysis); err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeHookFailed, err)
 		return
 	}
+	metadataTruncated := h.capMetadata(analysis.Metadata)
 	
-	c.JSON(http.StatusOK, models.AnalyzeResponse{
-		ID:         analysis.ID,
-		Summary:    analysis.Summary,
-		Metadata:   analysis.Metadata,
-		Confidence: analysis.Confidence,
-	})
+	// Without the LLM there is no confidence score to hold the analysis to.
+	persisted := !components.needsLLM() || h.worthStoring(analysis.Confidence)
+	if persisted {
+		dbStart := time.Now()
+		if err := h.store(ctx).SaveAnalysisContext(ctx, analysis); err != nil {
+			response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+			return
+		}
+		recordDBTiming(analysis, dbStart, startTime)
+		
+		if idempotencyKey != "" {
+			if err := h.store(ctx).SaveIdempotencyKeyContext(ctx, idempotencyKey, analysis.ID, time.Now()); err != nil {
+				log.Printf("Failed to store idempotency key: %v", err)
+			}
+		}
+	} else {
+		analysis.ProcessingMS = time.Since(startTime).Milliseconds()
+	}
+	
+	resp := models.AnalyzeResponse{
+		ID:                analysis.ID,
+		Summary:           analysis.Summary,
+		Metadata:          analysis.Metadata,
+		Confidence:        analysis.Confidence,
+		ProcessingMS:      analysis.ProcessingMS,
+		MetadataTruncated: metadataTruncated,
+		Persisted:         persisted,
+	}
+	if includeText(c, false) {
+		resp.Text = analysis.Text
+	}
+	if components.needsLLM() {
+		h.annotateConfidence(&resp, breakdown)
+	}
+	
+	response.JSON(c, http.StatusOK, resp)
+}
+
+// respondLLMError reports a failed provider call. Timeouts and outages are
+// retryable, so they carry a Retry-After hint; any other failure, such as an
+// unparseable answer or a request the provider rejected, would fail the same
+// way again and is reported as a bad response.
+func (h *Handler) respondLLMError(c *gin.Context, err error) {
+	retryAfter := strconv.Itoa(int(math.Ceil(h.config.RetryAfter.Seconds())))
+	
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		c.Header("Retry-After", retryAfter)
+		response.RespondError(c, http.StatusGatewayTimeout, models.CodeTimeout, err)
+	case errors.Is(err, llm.ErrLLMUnavailable):
+		c.Header("Retry-After", retryAfter)
+		response.RespondError(c, http.StatusServiceUnavailable, models.CodeLLMUnavailable, err)
+	default:
+		response.RespondError(c, http.StatusBadGateway, models.CodeLLMBadResponse, err)
+	}
+}
+
+// recordDBTiming adds the write time to the response copy of the analysis.
+// The stored row can't include its own write time, so it keeps llm_ms and
+// keyword_ms while the response also carries db_ms and the full total.
+func recordDBTiming(analysis *models.TextAnalysis, dbStart, startTime time.Time) {
+	analysis.Metadata["db_ms"] = time.Since(dbStart).Milliseconds()
+	analysis.ProcessingMS = time.Since(startTime).Milliseconds()
+}
+
+// recordProvider stamps metadata with the default provider and model, for
+// analyses that always use the default.
+func (h *Handler) recordProvider(metadata map[string]interface{}) {
+	recordBackend(metadata, h.defaultBackend())
+}
+
+// recordBackend stamps metadata with the provider and model that produced
+// it, so stored analyses can be compared across providers.
+func recordBackend(metadata map[string]interface{}, b backend) {
+	if b.name != "" {
+		metadata["provider"] = b.name
+	}
+	if b.model != "" {
+		metadata["model"] = b.model
+	}
+}
+
+// recordNode stamps metadata with NodeID, when configured, so an analysis can
+// be traced to the instance that created it.
+func (h *Handler) recordNode(metadata map[string]interface{}) {
+	if h.config.NodeID != "" {
+		metadata["node_id"] = h.config.NodeID
+	}
+}
+
+// worthStoring reports whether an analysis with this confidence clears
+// MinConfidenceToStore. Analyses below it are returned but not saved.
+func (h *Handler) worthStoring(confidence float64) bool {
+	return h.config.MinConfidenceToStore <= 0 || confidence >= h.config.MinConfidenceToStore
+}
+
+func (h *Handler) annotateConfidence(resp *models.AnalyzeResponse, breakdown analyzer.ConfidenceBreakdown) {
+	if h.config.ConfidenceWarnThreshold <= 0 || breakdown.Score >= h.config.ConfidenceWarnThreshold {
+		return
+	}
+	
+	resp.LowConfidence = true
+	resp.ConfidenceWarning = fmt.Sprintf(
+		"confidence %.2f is below threshold %.2f: %s",
+		breakdown.Score,
+		h.config.ConfidenceWarnThreshold,
+		strings.Join(breakdown.WeakReasons(), "; "),
+	)
+}
+
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+	
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		result = append(result, tag)
+	}
+	
+	return result
 }
 
 func (h *Handler) BatchAnalyzeText(c *gin.Context) {
+	req, ok := h.bindBatch(c)
+	if !ok {
+		return
+	}
+	texts := req.Texts
+	
+	indices := make([]int, len(texts))
+	for i := range texts {
+		indices[i] = i
+	}
+	
+	// With dedupe, repeated texts are analyzed once and share the result.
+	var duplicateOf map[int]int
+	if req.Dedupe {
+		indices, duplicateOf = batchDuplicates(texts)
+	}
+	
+	results, failed := h.processBatchIndexed(c.Request.Context(), texts, indices)
+	failed = shareDuplicates(results, failed, duplicateOf)
+	results = successfulResults(results)
+	omitText(c, results)
+	
+	response.JSON(c, http.StatusOK, h.batchResponse(results, failed))
+}
+
+// bindBatch binds and validates a batch request body, responding with the
+// error itself when the batch can't be processed.
+func (h *Handler) bindBatch(c *gin.Context) (models.BatchAnalyzeRequest, bool) {
 	var req models.BatchAnalyzeRequest
 	
+	if !h.checkBodyEncoding(c) {
+		return req, false
+	}
+	
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid request format",
-			Code:    "INVALID_REQUEST",
-			Details: err.Error(),
-		})
-		return
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return req, false
 	}
 	
 	if len(req.Texts) == 0 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error: "No texts provided",
-			Code:  "EMPTY_INPUT",
-		})
+		response.RespondError(c, http.StatusBadRequest, models.CodeEmptyInput, errors.New("no texts provided"))
+		return req, false
+	}
+	
+	if len(req.Texts) > maxBatchSize {
+		response.RespondError(c, http.StatusBadRequest, models.CodeBatchSizeExceeded, fmt.Errorf("maximum %d texts allowed per batch", maxBatchSize))
+		return req, false
+	}
+	
+	if !h.validateBatchTexts(c, req.Texts) {
+		return req, false
+	}
+	return req, true
+}
+
+func (h *Handler) RetryBatchAnalyze(c *gin.Context) {
+	var req models.BatchRetryRequest
+	
+	if !h.checkBodyEncoding(c) {
+		return
+	}
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	if len(req.Texts) > maxBatchSize {
+		response.RespondError(c, http.StatusBadRequest, models.CodeBatchSizeExceeded, fmt.Errorf("maximum %d texts allowed per batch", maxBatchSize))
 		return
 	}
 	
-	if len(req.Texts) > 10 {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error: "Maximum 10 texts allowed per batch",
-			Code:  "BATCH_SIZE_EXCEEDED",
-		})
+	if !h.validateBatchTexts(c, req.Texts) {
 		return
 	}
 	
+	// Failed may be cut short by BATCH_MAX_FAILURES, so failed_indices
+	// counts too. An index listed more than once is still retried once.
+	candidates := append([]int(nil), req.Previous.FailedIndices...)
+	for _, failure := range req.Previous.Failed {
+		candidates = append(candidates, failure.Index)
+	}
+	indices := make([]int, 0, len(candidates))
+	seen := make(map[int]bool, len(candidates))
+	for _, index := range candidates {
+		if index < 0 || index >= len(req.Texts) {
+			response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, fmt.Errorf("failed index %d is out of range for %d texts", index, len(req.Texts)))
+			return
+		}
+		if !seen[index] {
+			seen[index] = true
+			indices = append(indices, index)
+		}
+	}
+	sort.Ints(indices)
+	
+	results, failed := h.processBatch(c.Request.Context(), req.Texts, indices)
+	
+	merged := make([]models.AnalyzeResponse, 0, len(req.Previous.Results)+len(results))
+	merged = append(merged, req.Previous.Results...)
+	merged = append(merged, results...)
+	omitText(c, merged)
+	
+	response.JSON(c, http.StatusOK, h.batchResponse(merged, failed))
+}
+
+// validateBatchTexts normalizes each text in place and rejects the whole
+// batch when any item is empty or whitespace-only, so callers never see
+// per-item errors for bad input.
+func (h *Handler) validateBatchTexts(c *gin.Context, texts []string) bool {
+	for i, text := range texts {
+		text = analyzer.NormalizeText(text)
+		texts[i] = text
+		if h.rejectText(c, fmt.Sprintf("text at index %d", i), text) {
+			return false
+		}
+	}
+	return h.checkBatchBudget(c, texts)
+}
+
+// checkBatchBudget rejects a batch whose texts together exceed
+// BatchMaxTotalChars characters, on top of the per-text limit. A zero limit
+// disables the check.
+func (h *Handler) checkBatchBudget(c *gin.Context, texts []string) bool {
+	if h.config.BatchMaxTotalChars <= 0 {
+		return true
+	}
+	
+	total := 0
+	for _, text := range texts {
+		total += utf8.RuneCountInString(text)
+	}
+	if total > h.config.BatchMaxTotalChars {
+		response.RespondError(c, http.StatusBadRequest, models.CodeBatchBudgetExceeded, fmt.Errorf("batch totals %d characters, limit is %d", total, h.config.BatchMaxTotalChars))
+		return false
+	}
+	return true
+}
+
+func (h *Handler) batchResponse(results []models.AnalyzeResponse, failed []models.BatchError) models.BatchAnalyzeResponse {
+	sort.Slice(failed, func(i, j int) bool {
+		return failed[i].Index < failed[j].Index
+	})
+	
+	resp := models.BatchAnalyzeResponse{
+		Results:      results,
+		Failed:       failed,
+		SuccessCount: len(results),
+		FailedCount:  len(failed),
+	}
+	for _, failure := range failed {
+		resp.FailedIndices = append(resp.FailedIndices, failure.Index)
+	}
+	
+	if h.config.BatchMaxFailures > 0 && len(failed) > h.config.BatchMaxFailures {
+		resp.Failed = failed[:h.config.BatchMaxFailures]
+		resp.Truncated = true
+	}
+	
+	return resp
+}
+
+func (h *Handler) processBatch(ctx context.Context, texts []string, indices []int) ([]models.AnalyzeResponse, []models.BatchError) {
+	results, errors := h.processBatchIndexed(ctx, texts, indices)
+	return successfulResults(results), errors
+}
+
+// successfulResults drops the empty slots of failed or skipped texts.
+func successfulResults(results []models.AnalyzeResponse) []models.AnalyzeResponse {
+	successResults := make([]models.AnalyzeResponse, 0)
+	for _, result := range results {
+		if result.ID != "" {
+			successResults = append(successResults, result)
+		}
+	}
+	return successResults
+}
+
+// processBatchIndexed returns a result slot per text; slots for failed or
+// skipped texts are left zero. The batch runs to completion even if the
+// client goes away; only ctx's values, such as the tenant database, are kept.
+func (h *Handler) processBatchIndexed(ctx context.Context, texts []string, indices []int) ([]models.AnalyzeResponse, []models.BatchError) {
+	return h.processBatchContext(context.WithoutCancel(ctx), texts, indices, nil)
+}
+
+// processBatchContext analyzes the texts at indices concurrently, giving up
+// on items still waiting when ctx is done. onItem, if set, is called as each
+// item finishes with either its result or its failure; calls never overlap.
+func (h *Handler) processBatchContext(ctx context.Context, texts []string, indices []int, onItem func(index int, result *models.AnalyzeResponse, failure *models.BatchError)) ([]models.AnalyzeResponse, []models.BatchError) {
 	var wg sync.WaitGroup
-	results := make([]models.AnalyzeResponse, len(req.Texts))
+	results := make([]models.AnalyzeResponse, len(texts))
 	errors := make([]models.BatchError, 0)
-	var errorsMu sync.Mutex
+	var mu sync.Mutex
 	
-	semaphore := make(chan struct{}, 3)
-	
-	for i, text := range req.Texts {
+	for _, i := range indices {
 		wg.Add(1)
 		go func(index int, textContent string) {
 			defer wg.Done()
 			
-			semaphore <- struct{}{}
-			defer func() { <-semaphore }()
+			result, failure := h.analyzeBatchItem(ctx, index, textContent)
 			
-			if textContent == "" {
-				errorsMu.Lock()
-				errors = append(errors, models.BatchError{
-					Index: index,
-					Error: "Text cannot be empty",
-				})
-				errorsMu.Unlock()
-				return
+			mu.Lock()
+			defer mu.Unlock()
+			if failure != nil {
+				errors = append(errors, *failure)
+			} else {
+				results[index] = result
 			}
-			
-			startTime := time.Now()
-			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-			defer cancel()
-			
-			llmResult, err := h.llmProvider.Analyze(ctx, textContent)
-			if err != nil {
-				errorsMu.Lock()
-				errors = append(errors, models.BatchError{
-					Index: index,
-					Error: fmt.Sprintf("Analysis failed: %v", err),
-				})
-				errorsMu.Unlock()
-				return
+			if onItem != nil {
+				onItem(index, &result, failure)
 			}
-			
-			keywords := h.keywordExtractor.ExtractKeywords(textContent, 3)
-			
-			metadata := map[string]interface{}{
-				"title":     llmResult.Title,
-				"topics":    llmResult.Topics,
-				"sentiment": llmResult.Sentiment,
-				"keywords":  keywords,
-			}
-			
-			confidence := analyzer.CalculateConfidence(textContent, llmResult.Summary, llmResult.Topics)
-			
-			analysis := &models.TextAnalysis{
-				ID:           uuid.New().String(),
-				Text:         textContent,
-				Summary:      llmResult.Summary,
-				Metadata:     metadata,
-				Confidence:   confidence,
-				CreatedAt:    time.Now(),
-				ProcessingMS: time.Since(startTime).Milliseconds(),
+		}(i, texts[i])
+	}
+	
+	wg.Wait()
+	
+	return results, errors
+}
+
+// analyzeBatchItem analyzes and stores one batch text. A panic is recovered
+// and reported as the item's failure so it can't take down the batch.
+func (h *Handler) analyzeBatchItem(parent context.Context, index int, textContent string) (result models.AnalyzeResponse, failure *models.BatchError) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Batch item %d panicked: %v", index, r)
+			failure = &models.BatchError{
+				Index: index,
+				Error: fmt.Sprintf("Analysis failed: internal error: %v", r),
 			}
-			
-			if err := h.db.SaveAnalysis(analysis); err != nil {
-				errorsMu.Lock()
-				errors = append(errors, models.BatchError{
-					Index: index,
-					Error: fmt.Sprintf("Failed to save: %v", err),
-				})
-				errorsMu.Unlock()
-				return
+		}
+	}()
+	
+	startTime := time.Now()
+	
+	// Batch items use the default options, so they share cache entries with
+	// single analyses that don't pass any.
+	cacheKey := resultCacheKey(textContent, h.config.LLMProvider, nil, 0)
+	cached, hit := h.results.get(cacheKey)
+	llmResult := &cached.llm
+	var llmMS, keywordMS int64
+	if !hit {
+		llmStart := time.Now()
+		// Items queue for the worker pool behind the rest of the batch, so the
+		// item timeout starts only once this one's call holds a slot.
+		analyzed, err := h.analyzeWithin(parent, h.defaultBackend(), textContent, h.config.BatchItemTimeout)
+		llmMS = time.Since(llmStart).Milliseconds()
+		if err != nil {
+			return result, &models.BatchError{
+				Index: index,
+				Error: fmt.Sprintf("Analysis failed: %v", err),
 			}
-			
-			results[index] = models.AnalyzeResponse{
-				ID:         analysis.ID,
-				Summary:    analysis.Summary,
-				Metadata:   analysis.Metadata,
-				Confidence: analysis.Confidence,
+		}
+		
+		keywordStart := time.Now()
+		cached.language, cached.keywords = h.extractKeywords(textContent, analyzed.Topics, 0)
+		keywordMS = time.Since(keywordStart).Milliseconds()
+		
+		cached.llm = *analyzed
+		h.results.put(cacheKey, cached)
+	}
+	language, keywords := cached.language, cached.keywords
+	
+	metadata := map[string]interface{}{
+		"title":           llmResult.Title,
+		"topics":          llmResult.Topics,
+		"sentiment":       llmResult.Sentiment,
+		"sentiment_score": llmResult.SentimentScore,
+		"keywords":        keywords,
+		"language":        language,
+		"llm_ms":          llmMS,
+		"keyword_ms":      keywordMS,
+	}
+	h.recordProvider(metadata)
+	h.recordNode(metadata)
+	summary, summaryTruncated := h.capSummary(llmResult.Summary)
+	if summaryTruncated {
+		metadata["summary_truncated"] = true
+	}
+	
+	breakdown := analyzer.CalculateConfidenceBreakdown(textContent, summary, llmResult.Topics)
+	
+	ctx, cancel := context.WithTimeout(parent, h.config.BatchItemTimeout)
+	defer cancel()
+	
+	analysis := &models.TextAnalysis{
+		ID:           uuid.New().String(),
+		Text:         textContent,
+		Summary:      summary,
+		Metadata:     metadata,
+		Confidence:   breakdown.Score,
+		CreatedAt:    time.Now(),
+		ProcessingMS: time.Since(startTime).Milliseconds(),
+	}
+	
+	if err := h.runHooks(ctx, analysis); err != nil {
+		return result, &models.BatchError{
+			Index: index,
+			Error: fmt.Sprintf("Analysis failed: %v", err),
+		}
+	}
+	metadataTruncated := h.capMetadata(analysis.Metadata)
+	
+	persisted := h.worthStoring(analysis.Confidence)
+	if persisted {
+		dbStart := time.Now()
+		if err := h.store(ctx).SaveAnalysisContext(ctx, analysis); err != nil {
+			return result, &models.BatchError{
+				Index: index,
+				Error: fmt.Sprintf("Failed to save: %v", err),
 			}
-		}(i, text)
+		}
+		recordDBTiming(analysis, dbStart, startTime)
+	} else {
+		analysis.ProcessingMS = time.Since(startTime).Milliseconds()
 	}
 	
-	wg.Wait()
+	result = models.AnalyzeResponse{
+		ID:                analysis.ID,
+		Text:              analysis.Text,
+		Summary:           analysis.Summary,
+		Metadata:          analysis.Metadata,
+		Confidence:        analysis.Confidence,
+		ProcessingMS:      analysis.ProcessingMS,
+		MetadataTruncated: metadataTruncated,
+		Persisted:         persisted,
+	}
+	h.annotateConfidence(&result, breakdown)
+	return result, nil
+}
+
+// prepareFilter normalizes the topic the same way stored topics are and
+// rejects sentiments outside the configured set and inverted confidence
+// ranges. It writes the error response and returns false when the filter is
+// invalid.
+func (h *Handler) prepareFilter(c *gin.Context, filter *models.SearchFilter) bool {
+	filter.Topic = h.topicNormalizer.Normalize(filter.Topic)
+	filter.Provider = strings.ToLower(strings.TrimSpace(filter.Provider))
 	
-	successResults := make([]models.AnalyzeResponse, 0)
-	for _, result := range results {
-		if result.ID != "" {
-			successResults = append(successResults, result)
+	if filter.Sentiment != "" {
+		filter.Sentiment = strings.ToLower(strings.TrimSpace(filter.Sentiment))
+		if !h.sentiments.Contains(filter.Sentiment) {
+			response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, fmt.Errorf("unknown sentiment %q, must be one of: %s", filter.Sentiment, strings.Join(h.sentiments.Labels(), ", ")))
+			return false
 		}
 	}
 	
-	c.JSON(http.StatusOK, models.BatchAnalyzeResponse{
-		Results: successResults,
-		Failed:  errors,
-	})
+	if filter.MinConfidence != nil && filter.MaxConfidence != nil && *filter.MinConfidence > *filter.MaxConfidence {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, fmt.Errorf("min_confidence %v exceeds max_confidence %v", *filter.MinConfidence, *filter.MaxConfidence))
+		return false
+	}
+	
+	return true
 }
 
 func (h *Handler) SearchAnalyses(c *gin.Context) {
 	var query models.SearchQuery
 	
 	if err := c.ShouldBindQuery(&query); err != nil {
-		c.JSON(http.StatusBadRequest, models.ErrorResponse{
-			Error:   "Invalid query parameters",
-			Code:    "INVALID_REQUEST",
-			Details: err.Error(),
-		})
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	if !h.prepareFilter(c, &query.SearchFilter) {
+		return
+	}
+	
+	if query.Highlight && query.Keyword == "" {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, errors.New("highlight requires a keyword"))
+		return
+	}
+	
+	if query.Limit <= 0 {
+		query.Limit = h.config.SearchDefaultLimit
+	}
+	if query.Limit > h.config.SearchMaxLimit {
+		query.Limit = h.config.SearchMaxLimit
+	}
+	
+	analyses, err := h.store(c.Request.Context()).SearchAnalysesContext(c.Request.Context(), query)
+	if errors.Is(err, database.ErrInvalidCursor) {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	fields := metadataFields(c)
+	withText := includeText(c, true)
+	highlights := map[string]*models.SearchHighlight{}
+	for _, analysis := range analyses {
+		// Highlight before the text is dropped, so include_text=false still
+		// gets excerpts.
+		if query.Highlight {
+			if highlight := searchHighlight(analysis, query.Keyword); highlight != nil {
+				highlights[analysis.ID] = highlight
+			}
+		}
+		analysis.Metadata = projectMetadata(analysis.Metadata, fields)
+		if !withText {
+			analysis.Text = ""
+		}
+	}
+	
+	meta := gin.H{
+		"count": len(analyses),
+		"query": query,
+	}
+	if len(analyses) == query.Limit {
+		meta["next_cursor"] = database.EncodeCursor(analyses[len(analyses)-1])
+	}
+	if query.Highlight {
+		meta["highlights"] = highlights
+	}
+	
+	// Facets cost extra aggregate queries, so they are only run on request.
+	if query.Facets {
+		facets, err := h.store(c.Request.Context()).GetSearchFacetsContext(c.Request.Context(), query.SearchFilter, facetTopicLimit)
+		if err != nil {
+			response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+			return
+		}
+		meta["facets"] = facets
+	}
+	
+	response.List(c, http.StatusOK, "results", analyses, meta)
+}
+
+func (h *Handler) DeleteAnalyses(c *gin.Context) {
+	var query models.DeleteQuery
+	
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
 		return
 	}
 	
-	if query.Limit == 0 {
-		query.Limit = 50
+	if !h.prepareFilter(c, &query.SearchFilter) {
+		return
 	}
-	if query.Limit > 100 {
-		query.Limit = 100
+	
+	if !query.Confirm {
+		response.RespondError(c, http.StatusBadRequest, models.CodeConfirmationRequired, errors.New("bulk delete requires confirm=true"))
+		return
+	}
+	
+	if query.SearchFilter.IsEmpty() && !query.All {
+		response.RespondError(c, http.StatusBadRequest, models.CodeFilterRequired, errors.New("at least one filter is required, or pass all=true to delete everything"))
+		return
 	}
 	
-	analyses, err := h.db.SearchAnalyses(query)
+	deleted, err := h.store(c.Request.Context()).DeleteAnalysesContext(c.Request.Context(), query.SearchFilter)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{
-			Error:   "Search failed",
-			Code:    "DB_ERROR",
-			Details: err.Error(),
-		})
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
 		return
 	}
 	
-	c.JSON(http.StatusOK, gin.H{
-		"results": analyses,
-		"count":   len(analyses),
-		"query":   query,
+	response.JSON(c, http.StatusOK, models.DeleteResponse{
+		Deleted: deleted,
 	})
 }
\ No newline at end of file
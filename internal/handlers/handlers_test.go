@@ -0,0 +1,1876 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/analyzer"
+	"github.com/user/llm-knowledge-extractor/internal/database"
+	"github.com/user/llm-knowledge-extractor/internal/llm"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+type stubProvider struct {
+	result *llm.AnalysisResult
+	err    error
+}
+
+func (p *stubProvider) Analyze(ctx context.Context, text string) (*llm.AnalysisResult, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.result, nil
+}
+
+func (p *stubProvider) IsAvailable() bool {
+	return p.err == nil
+}
+
+type flakyProvider struct {
+	mu       sync.Mutex
+	failures map[string]int
+	calls    map[string]int
+	result   *llm.AnalysisResult
+}
+
+func newFlakyProvider(failures map[string]int) *flakyProvider {
+	return &flakyProvider{
+		failures: failures,
+		calls:    make(map[string]int),
+		result:   newStubProvider().result,
+	}
+}
+
+func (p *flakyProvider) Analyze(ctx context.Context, text string) (*llm.AnalysisResult, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	p.calls[text]++
+	if p.failures[text] > 0 {
+		p.failures[text]--
+		return nil, fmt.Errorf("%w: flaky failure", llm.ErrLLMUnavailable)
+	}
+	return p.result, nil
+}
+
+func (p *flakyProvider) IsAvailable() bool {
+	return true
+}
+
+type concurrencyProvider struct {
+	mu      sync.Mutex
+	current int
+	max     int
+	delay   time.Duration
+}
+
+func (p *concurrencyProvider) Analyze(ctx context.Context, text string) (*llm.AnalysisResult, error) {
+	p.mu.Lock()
+	p.current++
+	if p.current > p.max {
+		p.max = p.current
+	}
+	p.mu.Unlock()
+	
+	time.Sleep(p.delay)
+	
+	p.mu.Lock()
+	p.current--
+	p.mu.Unlock()
+	
+	return newStubProvider().result, nil
+}
+
+func (p *concurrencyProvider) IsAvailable() bool {
+	return true
+}
+
+type delayedProvider struct {
+	*stubProvider
+	delay time.Duration
+}
+
+func (p *delayedProvider) Analyze(ctx context.Context, text string) (*llm.AnalysisResult, error) {
+	time.Sleep(p.delay)
+	return p.stubProvider.Analyze(ctx, text)
+}
+
+func newStubProvider() *stubProvider {
+	return &stubProvider{
+		result: &llm.AnalysisResult{
+			Summary:   "A short summary of the text.",
+			Title:     "Test Title",
+			Topics:    []string{"technology", "testing", "software"},
+			Sentiment: "neutral",
+		},
+	}
+}
+
+func newTestHandler(t *testing.T, provider llm.Provider) (*Handler, *database.DB) {
+	return newTestHandlerWithConfig(t, provider, Config{})
+}
+
+func newTestHandlerWithConfig(t *testing.T, provider llm.Provider, config Config) (*Handler, *database.DB) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	
+	return New(db, provider, config), db
+}
+
+func postJSON(r http.Handler, path string, payload interface{}) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(payload)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+	return w
+}
+
+// decodeData unmarshals the data field of an enveloped response into v.
+func decodeData(t *testing.T, w *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	require.NoError(t, json.Unmarshal(envelope.Data, v))
+}
+
+// decodeMeta unmarshals the meta field of an enveloped response into v.
+func decodeMeta(t *testing.T, w *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	
+	var envelope struct {
+		Meta json.RawMessage `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	require.NoError(t, json.Unmarshal(envelope.Meta, v))
+}
+
+func decodeError(t *testing.T, w *httptest.ResponseRecorder) models.APIError {
+	t.Helper()
+	
+	var envelope models.Envelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+	require.NotNil(t, envelope.Error, w.Body.String())
+	return *envelope.Error
+}
+
+func seedAnalysis(t *testing.T, db *database.DB, id string, topics []string) {
+	t.Helper()
+	
+	err := db.SaveAnalysis(&models.TextAnalysis{
+		ID:      id,
+		Text:    "Text for " + id,
+		Summary: "Summary for " + id,
+		Metadata: map[string]interface{}{
+			"topics": topics,
+		},
+		Confidence: 0.8,
+		CreatedAt:  time.Now(),
+	})
+	require.NoError(t, err)
+}
+
+func TestHandler_DeleteAnalyses(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		status    int
+		code      models.ErrorCode
+		deleted   int64
+		remaining int
+	}{
+		{
+			name:      "Missing confirmation",
+			query:     "topic=golang",
+			status:    http.StatusBadRequest,
+			code:      models.CodeConfirmationRequired,
+			remaining: 3,
+		},
+		{
+			name:      "No filters without all flag",
+			query:     "confirm=true",
+			status:    http.StatusBadRequest,
+			code:      models.CodeFilterRequired,
+			remaining: 3,
+		},
+		{
+			name:      "Filtered delete",
+			query:     "confirm=true&topic=golang",
+			status:    http.StatusOK,
+			deleted:   2,
+			remaining: 1,
+		},
+		{
+			name:      "Explicit delete all",
+			query:     "confirm=true&all=true",
+			status:    http.StatusOK,
+			deleted:   3,
+			remaining: 0,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, db := newTestHandler(t, newStubProvider())
+			seedAnalysis(t, db, "a1", []string{"golang"})
+			seedAnalysis(t, db, "a2", []string{"golang"})
+			seedAnalysis(t, db, "a3", []string{"python"})
+			
+			r := gin.New()
+			r.DELETE("/analyses", h.DeleteAnalyses)
+			
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodDelete, "/analyses?"+tt.query, nil)
+			r.ServeHTTP(w, req)
+			
+			assert.Equal(t, tt.status, w.Code)
+			
+			if tt.code != "" {
+				errResp := decodeError(t, w)
+				assert.Equal(t, tt.code, errResp.Code)
+			} else {
+				var resp models.DeleteResponse
+				decodeData(t, w, &resp)
+				assert.Equal(t, tt.deleted, resp.Deleted)
+			}
+			
+			remaining, err := db.SearchAnalyses(models.SearchQuery{})
+			require.NoError(t, err)
+			assert.Len(t, remaining, tt.remaining)
+		})
+	}
+}
+
+func TestHandler_BatchAnalyzeText_EmptyItemRejectsBatch(t *testing.T) {
+	for _, empty := range []string{"", "   ", "\u200b\u00a0\ufeff"} {
+		provider := newFlakyProvider(nil)
+		h, db := newTestHandler(t, provider)
+		
+		r := gin.New()
+		r.POST("/batch-analyze", h.BatchAnalyzeText)
+		
+		w := postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{
+			Texts: []string{"first text", empty, "third text"},
+		})
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		
+		resp := decodeError(t, w)
+		assert.Equal(t, models.CodeEmptyInput, resp.Code)
+		assert.Contains(t, resp.Details, "index 1")
+		
+		assert.Empty(t, provider.calls)
+		stored, err := db.GetRecentAnalyses(10)
+		require.NoError(t, err)
+		assert.Empty(t, stored)
+	}
+}
+
+func TestHandler_BatchAnalyzeText_TotalCharBudget(t *testing.T) {
+	provider := newFlakyProvider(nil)
+	h, _ := newTestHandlerWithConfig(t, provider, Config{BatchMaxTotalChars: 1000})
+	
+	r := gin.New()
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	small := make([]string, maxBatchSize)
+	for i := range small {
+		small[i] = fmt.Sprintf("short text number %d", i)
+	}
+	w := postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: small})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp models.BatchAnalyzeResponse
+	decodeData(t, w, &resp)
+	assert.Equal(t, maxBatchSize, resp.SuccessCount)
+	
+	calls := len(provider.calls)
+	huge := strings.Repeat("a", 600)
+	w = postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: []string{huge, huge}})
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+	apiErr := decodeError(t, w)
+	assert.Equal(t, models.CodeBatchBudgetExceeded, apiErr.Code)
+	assert.Contains(t, apiErr.Details, "1200")
+	assert.Contains(t, apiErr.Details, "1000")
+	assert.Len(t, provider.calls, calls, "nothing is analyzed")
+}
+
+func TestHandler_BatchAnalyzeText_RetriesTransientFailures(t *testing.T) {
+	flaky := newFlakyProvider(map[string]int{"second text": 1})
+	h, _ := newTestHandler(t, llm.NewRetryingProvider(flaky, 2, time.Millisecond))
+	
+	r := gin.New()
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	w := postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{
+		Texts: []string{"first text", "second text", "third text"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var resp models.BatchAnalyzeResponse
+	decodeData(t, w, &resp)
+	assert.Len(t, resp.Results, 3)
+	assert.Empty(t, resp.Failed)
+	assert.Equal(t, 2, flaky.calls["second text"])
+}
+
+// patientProvider answers after delay unless ctx is done first.
+type patientProvider struct {
+	delay time.Duration
+}
+
+func (p *patientProvider) Analyze(ctx context.Context, text string) (*llm.AnalysisResult, error) {
+	select {
+	case <-time.After(p.delay):
+		return newStubProvider().result, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *patientProvider) IsAvailable() bool {
+	return true
+}
+
+func TestHandler_BatchAnalyzeText_TimeoutExcludesPoolWait(t *testing.T) {
+	h, _ := newTestHandlerWithConfig(t, &patientProvider{delay: 50 * time.Millisecond}, Config{
+		WorkerPoolSize:   1,
+		BatchItemTimeout: 150 * time.Millisecond,
+	})
+	
+	r := gin.New()
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	// With one slot the last items wait far longer than the item timeout
+	// before their call starts; only the call itself is timed.
+	texts := make([]string, 6)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("queued text %d", i)
+	}
+	w := postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: texts})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var resp models.BatchAnalyzeResponse
+	decodeData(t, w, &resp)
+	assert.Empty(t, resp.Failed)
+	assert.Equal(t, len(texts), resp.SuccessCount)
+}
+
+func TestHandler_RetryBatchAnalyze(t *testing.T) {
+	texts := []string{"first text", "second text", "third text"}
+	provider := newFlakyProvider(map[string]int{"second text": 1})
+	h, _ := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	r.POST("/batch-analyze/retry", h.RetryBatchAnalyze)
+	
+	body, err := json.Marshal(models.BatchAnalyzeRequest{Texts: texts})
+	require.NoError(t, err)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/batch-analyze", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var first models.BatchAnalyzeResponse
+	decodeData(t, w, &first)
+	assert.Len(t, first.Results, 2)
+	require.Len(t, first.Failed, 1)
+	assert.Equal(t, 1, first.Failed[0].Index)
+	
+	body, err = json.Marshal(models.BatchRetryRequest{Texts: texts, Previous: first})
+	require.NoError(t, err)
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/batch-analyze/retry", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var retried models.BatchAnalyzeResponse
+	decodeData(t, w, &retried)
+	assert.Len(t, retried.Results, 3)
+	assert.Empty(t, retried.Failed)
+	
+	assert.Equal(t, 1, provider.calls["first text"])
+	assert.Equal(t, 2, provider.calls["second text"])
+	assert.Equal(t, 1, provider.calls["third text"])
+}
+
+func TestHandler_RetryBatchAnalyze_DuplicateIndices(t *testing.T) {
+	texts := []string{"first text", "second text"}
+	provider := newFlakyProvider(nil)
+	h, db := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/batch-analyze/retry", h.RetryBatchAnalyze)
+	
+	w := postJSON(r, "/batch-analyze/retry", models.BatchRetryRequest{
+		Texts: texts,
+		Previous: models.BatchAnalyzeResponse{
+			Failed:        []models.BatchError{{Index: 1, Error: "Analysis failed"}, {Index: 1, Error: "Analysis failed"}},
+			FailedIndices: []int{1, 1},
+		},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var resp models.BatchAnalyzeResponse
+	decodeData(t, w, &resp)
+	assert.Equal(t, 1, resp.SuccessCount)
+	assert.Equal(t, 1, provider.calls["second text"])
+	assert.Zero(t, provider.calls["first text"])
+	
+	stored, err := db.GetRecentAnalyses(10)
+	require.NoError(t, err)
+	assert.Len(t, stored, 1)
+}
+
+func TestHandler_RetryBatchAnalyze_InvalidIndex(t *testing.T) {
+	h, _ := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/batch-analyze/retry", h.RetryBatchAnalyze)
+	
+	body, err := json.Marshal(models.BatchRetryRequest{
+		Texts: []string{"only text"},
+		Previous: models.BatchAnalyzeResponse{
+			Failed: []models.BatchError{{Index: 5, Error: "Analysis failed"}},
+		},
+	})
+	require.NoError(t, err)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/batch-analyze/retry", bytes.NewReader(body)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	
+	w = postJSON(r, "/batch-analyze/retry", models.BatchRetryRequest{
+		Texts:    []string{"only text"},
+		Previous: models.BatchAnalyzeResponse{FailedIndices: []int{-1}},
+	})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_AnalyzeText_TooLong(t *testing.T) {
+	provider := newFlakyProvider(nil)
+	h, db := newTestHandlerWithConfig(t, provider, Config{MaxTextLength: 10})
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "This text is longer than ten characters."})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, models.CodeTextTooLong, decodeError(t, w).Code)
+	assert.Empty(t, provider.calls)
+	stored, err := db.GetRecentAnalyses(10)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+	
+	// The limit counts characters after normalization.
+	w = postJSON(r, "/analyze", models.AnalyzeRequest{Text: "  ten   chars "})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestHandler_AnalyzeText_ConfidenceRounded(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	
+	for _, text := range []string{
+		"Short note about testing.",
+		"Release planning covers scope, risks and staffing for the next two quarters of product work.",
+	} {
+		w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: text})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp models.AnalyzeResponse
+		decodeData(t, w, &resp)
+		
+		stored, err := db.GetAnalysis(resp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, analyzer.RoundConfidence(stored.Confidence), stored.Confidence)
+		assert.Equal(t, stored.Confidence, resp.Confidence)
+	}
+}
+
+func TestHandler_AnalyzeText_Tags(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.GET("/search", h.SearchAnalyses)
+	
+	body, err := json.Marshal(models.AnalyzeRequest{
+		Text: "Quarterly planning notes for the platform team.",
+		Tags: []string{"Planning", "q3", "planning"},
+	})
+	require.NoError(t, err)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/analyze", bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var created models.AnalyzeResponse
+	decodeData(t, w, &created)
+	assert.Equal(t, []interface{}{"planning", "q3"}, created.Metadata["tags"])
+	
+	stored, err := db.GetAnalysis(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"planning", "q3"}, stored.Metadata["tags"])
+	
+	for _, query := range []string{"tag=planning", "tag=planning&tag=q3"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		
+		var resp struct {
+			Count int `json:"count"`
+		}
+		decodeMeta(t, w, &resp)
+		assert.Equal(t, 1, resp.Count, query)
+	}
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?tag=planning&tag=other", nil))
+	var resp struct {
+		Count int `json:"count"`
+	}
+	decodeMeta(t, w, &resp)
+	assert.Equal(t, 0, resp.Count)
+}
+
+func TestHandler_AnalyzeText_SentimentFallback(t *testing.T) {
+	tests := []struct {
+		name      string
+		sentiment string
+		expected  string
+	}{
+		{
+			name:      "Missing sentiment uses lexicon",
+			sentiment: "",
+			expected:  "positive",
+		},
+		{
+			name:      "Unrecognized sentiment uses lexicon",
+			sentiment: "ecstatic",
+			expected:  "positive",
+		},
+		{
+			name:      "Provider sentiment is authoritative",
+			sentiment: "negative",
+			expected:  "negative",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newStubProvider()
+			provider.result.Sentiment = tt.sentiment
+			h, _ := newTestHandler(t, provider)
+			
+			r := gin.New()
+			r.POST("/analyze", h.AnalyzeText)
+			
+			w := postJSON(r, "/analyze", models.AnalyzeRequest{
+				Text: "The launch was a great success and the team did an excellent job.",
+			})
+			require.Equal(t, http.StatusOK, w.Code)
+			
+			var resp models.AnalyzeResponse
+			decodeData(t, w, &resp)
+			assert.Equal(t, tt.expected, resp.Metadata["sentiment"])
+		})
+	}
+}
+
+// TestHandler_AnalyzeText_SentimentFallbackParsed runs the fallback behind a
+// real provider, so the response goes through the shared JSON parser.
+func TestHandler_AnalyzeText_SentimentFallbackParsed(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{
+			name:     "Missing sentiment uses lexicon",
+			content:  `{"summary": "s", "topics": ["launch"]}`,
+			expected: "positive",
+		},
+		{
+			name:     "Unrecognized sentiment uses lexicon",
+			content:  `{"summary": "s", "topics": ["launch"], "sentiment": "ecstatic"}`,
+			expected: "positive",
+		},
+		{
+			name:     "Provider sentiment is authoritative",
+			content:  `{"summary": "s", "topics": ["launch"], "sentiment": "Negative"}`,
+			expected: "negative",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"choices": []map[string]interface{}{
+						{"message": map[string]string{"role": "assistant", "content": tt.content}},
+					},
+				})
+			}))
+			defer server.Close()
+			
+			provider, err := llm.NewProvider(llm.Config{
+				Provider:        "azure",
+				AzureEndpoint:   server.URL,
+				AzureAPIKey:     "key",
+				AzureDeployment: "test",
+			})
+			require.NoError(t, err)
+			h, _ := newTestHandler(t, provider)
+			
+			r := gin.New()
+			r.POST("/analyze", h.AnalyzeText)
+			
+			w := postJSON(r, "/analyze", models.AnalyzeRequest{
+				Text: "The launch was a great success and the team did an excellent job.",
+			})
+			require.Equal(t, http.StatusOK, w.Code)
+			
+			var resp models.AnalyzeResponse
+			decodeData(t, w, &resp)
+			assert.Equal(t, tt.expected, resp.Metadata["sentiment"])
+			if tt.expected == "positive" {
+				assert.Greater(t, resp.Metadata["sentiment_score"], 0.0)
+			}
+		})
+	}
+}
+
+func TestHandler_AnalyzeText_NormalizesTopics(t *testing.T) {
+	provider := newStubProvider()
+	provider.result.Topics = []string{"Machine-Learning", "ML", "Robotics"}
+	h, db := newTestHandlerWithConfig(t, provider, Config{
+		TopicAliases: map[string]string{"ml": "machine learning"},
+	})
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.GET("/search", h.SearchAnalyses)
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "Notes on training robots with neural networks."})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var created models.AnalyzeResponse
+	decodeData(t, w, &created)
+	
+	stored, err := db.GetAnalysis(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"machine learning", "robotics"}, stored.MetadataStrings("topics"))
+	
+	for _, topic := range []string{"machine%20learning", "Machine_Learning", "ML"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?topic="+topic, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		
+		var resp struct {
+			Count int `json:"count"`
+		}
+		decodeMeta(t, w, &resp)
+		assert.Equal(t, 1, resp.Count, topic)
+	}
+}
+
+func TestHandler_AnalyzeText_NormalizesText(t *testing.T) {
+	provider := newFlakyProvider(nil)
+	h, db := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	pasted := "\u201cServer\u00a0down\u201d \u2014 the  clus\u200bter\u2019s fine.\r\n"
+	normalized := "\"Server down\" \u2014 the cluster's fine."
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: pasted})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var created models.AnalyzeResponse
+	decodeData(t, w, &created)
+	stored, err := db.GetAnalysis(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, normalized, stored.Text)
+	
+	w = postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: []string{pasted}})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	assert.Equal(t, map[string]int{normalized: 2}, provider.calls)
+}
+
+func TestHandler_AnalyzeText_TimingBreakdown(t *testing.T) {
+	provider := &delayedProvider{stubProvider: newStubProvider(), delay: 30 * time.Millisecond}
+	h, db := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "Timing breakdown for a slow local model."})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var resp models.AnalyzeResponse
+	decodeData(t, w, &resp)
+	
+	var sum int64
+	for _, key := range []string{"llm_ms", "keyword_ms", "db_ms"} {
+		value, ok := resp.Metadata[key].(float64)
+		require.True(t, ok, key)
+		assert.GreaterOrEqual(t, value, 0.0, key)
+		sum += int64(value)
+	}
+	assert.GreaterOrEqual(t, resp.Metadata["llm_ms"], 30.0)
+	assert.InDelta(t, resp.ProcessingMS, sum, 5)
+	
+	stored, err := db.GetAnalysis(resp.ID)
+	require.NoError(t, err)
+	assert.Contains(t, stored.Metadata, "llm_ms")
+	assert.Contains(t, stored.Metadata, "keyword_ms")
+}
+
+func TestHandler_AnalyzeText_DetectsLanguageForKeywords(t *testing.T) {
+	h, _ := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{
+		Text: "La investigación sobre el clima muestra que el clima de la región cambia.",
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var resp models.AnalyzeResponse
+	decodeData(t, w, &resp)
+	assert.Equal(t, "es", resp.Metadata["language"])
+	assert.Contains(t, resp.Metadata["keywords"], "clima")
+	assert.NotContains(t, resp.Metadata["keywords"], "sobre")
+}
+
+func TestHandler_AnalyzeText_CapsOversizedMetadata(t *testing.T) {
+	provider := newStubProvider()
+	provider.result.Topics = nil
+	for i := 0; i < 50; i++ {
+		provider.result.Topics = append(provider.result.Topics, fmt.Sprintf("topic %d", i))
+	}
+	h, db := newTestHandlerWithConfig(t, provider, Config{MaxMetadataItems: 2})
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{
+		Text: "The deployment pipeline, the monitoring dashboard and the migration tooling all needed attention.",
+		Tags: []string{"ops", "infra", "q3"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var resp models.AnalyzeResponse
+	decodeData(t, w, &resp)
+	assert.True(t, resp.MetadataTruncated)
+	assert.Len(t, resp.Metadata["topics"], 3)
+	assert.Len(t, resp.Metadata["keywords"], 2)
+	assert.Len(t, resp.Metadata["tags"], 2)
+	
+	stored, err := db.GetAnalysis(resp.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.MetadataStrings("topics"), 3)
+	assert.Len(t, stored.MetadataStrings("keywords"), 2)
+	
+	h, _ = newTestHandler(t, newStubProvider())
+	r = gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	
+	w = postJSON(r, "/analyze", models.AnalyzeRequest{Text: "Short note about the deployment."})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var untouched models.AnalyzeResponse
+	decodeData(t, w, &untouched)
+	assert.False(t, untouched.MetadataTruncated)
+}
+
+func TestHandler_AnalyzeText_KeywordMinLengthOverride(t *testing.T) {
+	h, _ := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	
+	text := "CO readings rose. The CO sensor and the CO alarm confirmed combustion in the chamber."
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: text})
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp models.AnalyzeResponse
+	decodeData(t, w, &resp)
+	assert.NotContains(t, resp.Metadata["keywords"], "CO")
+	
+	w = postJSON(r, "/analyze", models.AnalyzeRequest{Text: text, KeywordMinLength: 2})
+	require.Equal(t, http.StatusOK, w.Code)
+	decodeData(t, w, &resp)
+	assert.Contains(t, resp.Metadata["keywords"], "CO")
+}
+
+func TestHandler_AnalyzeText_SuppressesTopicKeywords(t *testing.T) {
+	text := "The deployment failed. A second deployment followed the server restart, and the deployment pipeline logged the server error in the cluster upgrade."
+	
+	tests := []struct {
+		name     string
+		config   Config
+		expected []string
+	}{
+		{"backfill", Config{}, []string{"server", "cluster", "error"}},
+		{"no backfill", Config{DisableKeywordBackfill: true}, []string{"server", "cluster"}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newStubProvider()
+			provider.result.Topics = []string{"Deployment", "infrastructure"}
+			h, _ := newTestHandlerWithConfig(t, provider, tt.config)
+			
+			r := gin.New()
+			r.POST("/analyze", h.AnalyzeText)
+			
+			w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: text})
+			require.Equal(t, http.StatusOK, w.Code)
+			
+			var resp models.AnalyzeResponse
+			decodeData(t, w, &resp)
+			keywords := resp.Metadata["keywords"].([]interface{})
+			assert.NotContains(t, keywords, "deployment")
+			assert.Len(t, keywords, len(tt.expected))
+			for _, keyword := range tt.expected {
+				assert.Contains(t, keywords, keyword)
+			}
+		})
+	}
+}
+
+func TestHandler_AnalyzeText_ErrorRetryHints(t *testing.T) {
+	tests := []struct {
+		name        string
+		providerErr error
+		body        string
+		status      int
+		code        models.ErrorCode
+		retryable   bool
+		retryAfter  string
+	}{
+		{"llm unavailable", llm.ErrLLMUnavailable, `{"text":"Some text."}`, http.StatusServiceUnavailable, models.CodeLLMUnavailable, true, "3"},
+		{"timeout", context.DeadlineExceeded, `{"text":"Some text."}`, http.StatusGatewayTimeout, models.CodeTimeout, true, "3"},
+		{"invalid json", fmt.Errorf("%w: unexpected end of input", llm.ErrInvalidJSON), `{"text":"Some text."}`, http.StatusBadGateway, models.CodeLLMBadResponse, false, ""},
+		{"schema mismatch", &llm.SchemaError{Field: "topics", Got: "number"}, `{"text":"Some text."}`, http.StatusBadGateway, models.CodeLLMBadResponse, false, ""},
+		{"rejected request", errors.New("openai returned status 401"), `{"text":"Some text."}`, http.StatusBadGateway, models.CodeLLMBadResponse, false, ""},
+		{"blocked prompt", fmt.Errorf("%w: gemini block reason SAFETY", llm.ErrPromptBlocked), `{"text":"Some text."}`, http.StatusBadGateway, models.CodeLLMBadResponse, false, ""},
+		{"invalid request", nil, `{"text":`, http.StatusBadRequest, models.CodeInvalidRequest, false, ""},
+		{"empty input", llm.ErrEmptyInput, `{"text":"Some text."}`, http.StatusBadRequest, models.CodeEmptyInput, false, ""},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newStubProvider()
+			provider.err = tt.providerErr
+			h, _ := newTestHandlerWithConfig(t, provider, Config{RetryAfter: 2500 * time.Millisecond})
+			
+			r := gin.New()
+			r.POST("/analyze", h.AnalyzeText)
+			
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(tt.body)))
+			require.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.retryAfter, w.Header().Get("Retry-After"))
+			
+			errResp := decodeError(t, w)
+			assert.Equal(t, tt.code, errResp.Code)
+			assert.Equal(t, tt.retryable, errResp.Retryable)
+		})
+	}
+}
+
+func TestHandler_AnalyzeText_IdempotencyReplay(t *testing.T) {
+	provider := newFlakyProvider(nil)
+	h, db := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	
+	send := func(key, text string) models.AnalyzeResponse {
+		body, err := json.Marshal(models.AnalyzeRequest{Text: text})
+		require.NoError(t, err)
+		
+		req := httptest.NewRequest(http.MethodPost, "/analyze", bytes.NewReader(body))
+		req.Header.Set("Idempotency-Key", key)
+		
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+		
+		var resp models.AnalyzeResponse
+		decodeData(t, w, &resp)
+		return resp
+	}
+	
+	first := send("key-1", "An article about distributed systems.")
+	replay := send("key-1", "An article about distributed systems.")
+	other := send("key-2", "An article about distributed systems.")
+	
+	assert.Equal(t, first.ID, replay.ID)
+	assert.NotEqual(t, first.ID, other.ID)
+	assert.Equal(t, 2, provider.calls["An article about distributed systems."])
+	
+	stored, err := db.SearchAnalyses(models.SearchQuery{})
+	require.NoError(t, err)
+	assert.Len(t, stored, 2)
+}
+
+func TestHandler_AnalyzeText_IdempotencyConcurrent(t *testing.T) {
+	provider := newFlakyProvider(nil)
+	h, db := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	
+	body, err := json.Marshal(models.AnalyzeRequest{Text: "Concurrent submission text."})
+	require.NoError(t, err)
+	
+	const requests = 8
+	ids := make([]string, requests)
+	
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			
+			req := httptest.NewRequest(http.MethodPost, "/analyze", bytes.NewReader(body))
+			req.Header.Set("Idempotency-Key", "same-key")
+			
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			
+			var resp struct {
+				Data models.AnalyzeResponse `json:"data"`
+			}
+			if w.Code == http.StatusOK && json.Unmarshal(w.Body.Bytes(), &resp) == nil {
+				ids[index] = resp.Data.ID
+			}
+		}(i)
+	}
+	wg.Wait()
+	
+	for _, id := range ids {
+		assert.NotEmpty(t, id)
+		assert.Equal(t, ids[0], id)
+	}
+	assert.Equal(t, 1, provider.calls["Concurrent submission text."])
+	
+	stored, err := db.SearchAnalyses(models.SearchQuery{})
+	require.NoError(t, err)
+	assert.Len(t, stored, 1)
+}
+
+func TestHandler_AnalyzeText_MinConfidenceToStore(t *testing.T) {
+	longText := strings.Repeat("Distributed systems coordinate work between many machines reliably. ", 8)
+	
+	tests := []struct {
+		name      string
+		text      string
+		minimum   float64
+		persisted bool
+	}{
+		{name: "Above minimum is stored", text: longText, minimum: 0.6, persisted: true},
+		{name: "Below minimum is returned but not stored", text: "Tiny text.", minimum: 0.8, persisted: false},
+		{name: "Zero minimum disables the check", text: "Tiny text.", minimum: 0, persisted: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, db := newTestHandlerWithConfig(t, newStubProvider(), Config{MinConfidenceToStore: tt.minimum})
+			
+			r := gin.New()
+			r.POST("/analyze", h.AnalyzeText)
+			
+			w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: tt.text})
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			
+			var resp models.AnalyzeResponse
+			decodeData(t, w, &resp)
+			assert.Equal(t, tt.persisted, resp.Persisted)
+			assert.NotEmpty(t, resp.Summary)
+			
+			stored, err := db.GetAnalysis(resp.ID)
+			require.NoError(t, err)
+			assert.Equal(t, tt.persisted, stored != nil)
+		})
+	}
+}
+
+func TestHandler_BatchAnalyzeText_MinConfidenceToStore(t *testing.T) {
+	longText := strings.Repeat("Distributed systems coordinate work between many machines reliably. ", 8)
+	h, db := newTestHandlerWithConfig(t, newStubProvider(), Config{MinConfidenceToStore: 0.8})
+	
+	r := gin.New()
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	w := postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: []string{longText, "Tiny text."}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var resp models.BatchAnalyzeResponse
+	decodeData(t, w, &resp)
+	require.Len(t, resp.Results, 2)
+	assert.Equal(t, 2, resp.SuccessCount)
+	
+	stored, err := db.SearchAnalyses(models.SearchQuery{})
+	require.NoError(t, err)
+	require.Len(t, stored, 1)
+	for _, result := range resp.Results {
+		assert.Equal(t, result.ID == stored[0].ID, result.Persisted)
+	}
+}
+
+func TestHandler_AnalyzeText_ConfidenceThreshold(t *testing.T) {
+	longText := strings.Repeat("Distributed systems coordinate work between many machines reliably. ", 8)
+	
+	tests := []struct {
+		name          string
+		text          string
+		threshold     float64
+		lowConfidence bool
+	}{
+		{
+			name:          "Above threshold is not flagged",
+			text:          longText,
+			threshold:     0.6,
+			lowConfidence: false,
+		},
+		{
+			name:          "Below threshold is flagged with reasons",
+			text:          "Tiny text.",
+			threshold:     0.8,
+			lowConfidence: true,
+		},
+		{
+			name:          "Zero threshold disables the check",
+			text:          "Tiny text.",
+			threshold:     0,
+			lowConfidence: false,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newTestHandlerWithConfig(t, newStubProvider(), Config{ConfidenceWarnThreshold: tt.threshold})
+			
+			r := gin.New()
+			r.POST("/analyze", h.AnalyzeText)
+			
+			w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: tt.text})
+			require.Equal(t, http.StatusOK, w.Code)
+			
+			var resp models.AnalyzeResponse
+			decodeData(t, w, &resp)
+			assert.Equal(t, tt.lowConfidence, resp.LowConfidence)
+			
+			if tt.lowConfidence {
+				assert.Contains(t, resp.ConfidenceWarning, "text is very short")
+			} else {
+				assert.Empty(t, resp.ConfidenceWarning)
+			}
+		})
+	}
+}
+
+func TestHandler_BatchAnalyzeText_FailureCap(t *testing.T) {
+	texts := make([]string, 8)
+	failures := make(map[string]int)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("batch text %d", i)
+		if i > 0 {
+			failures[texts[i]] = 1
+		}
+	}
+	
+	provider := newFlakyProvider(failures)
+	h, _ := newTestHandlerWithConfig(t, provider, Config{BatchMaxFailures: 3})
+	
+	r := gin.New()
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	r.POST("/batch-analyze/retry", h.RetryBatchAnalyze)
+	
+	w := postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: texts})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var resp models.BatchAnalyzeResponse
+	decodeData(t, w, &resp)
+	
+	assert.Equal(t, 1, resp.SuccessCount)
+	assert.Equal(t, 7, resp.FailedCount)
+	assert.True(t, resp.Truncated)
+	require.Len(t, resp.Failed, 3)
+	assert.Equal(t, []int{1, 2, 3}, []int{resp.Failed[0].Index, resp.Failed[1].Index, resp.Failed[2].Index})
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7}, resp.FailedIndices)
+	
+	// The failures left out of the capped list are retried as well.
+	w = postJSON(r, "/batch-analyze/retry", models.BatchRetryRequest{Texts: texts, Previous: resp})
+	require.Equal(t, http.StatusOK, w.Code)
+	var retried models.BatchAnalyzeResponse
+	decodeData(t, w, &retried)
+	assert.Equal(t, 8, retried.SuccessCount)
+	assert.Empty(t, retried.FailedIndices)
+	for _, text := range texts[1:] {
+		assert.Equal(t, 2, provider.calls[text], text)
+	}
+}
+
+func TestHandler_BatchAnalyzeText_FailuresWithinCap(t *testing.T) {
+	texts := []string{"ok text", "failing text"}
+	h, _ := newTestHandlerWithConfig(t, newFlakyProvider(map[string]int{"failing text": 1}), Config{BatchMaxFailures: 3})
+	
+	r := gin.New()
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	w := postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: texts})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var resp models.BatchAnalyzeResponse
+	decodeData(t, w, &resp)
+	
+	assert.Equal(t, 1, resp.SuccessCount)
+	assert.Equal(t, 1, resp.FailedCount)
+	assert.False(t, resp.Truncated)
+	assert.Len(t, resp.Failed, 1)
+}
+
+func TestHandler_WorkerPoolCapsConcurrency(t *testing.T) {
+	provider := &concurrencyProvider{delay: 20 * time.Millisecond}
+	h, _ := newTestHandlerWithConfig(t, provider, Config{WorkerPoolSize: 2})
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	texts := make([]string, 5)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("pooled text %d", i)
+	}
+	
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: texts})
+		}()
+		go func() {
+			defer wg.Done()
+			postJSON(r, "/analyze", models.AnalyzeRequest{Text: "single pooled text"})
+		}()
+	}
+	wg.Wait()
+	
+	assert.LessOrEqual(t, provider.max, 2)
+	assert.Greater(t, provider.max, 0)
+}
+
+func TestHandler_SearchAnalyses_ConfiguredLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		query    string
+		expected int
+	}{
+		{name: "Default limit applied", query: "", expected: 3},
+		{name: "Explicit limit within max", query: "limit=4", expected: 4},
+		{name: "Limit clamped to configured max", query: "limit=50", expected: 5},
+	}
+	
+	h, db := newTestHandlerWithConfig(t, newStubProvider(), Config{SearchDefaultLimit: 3, SearchMaxLimit: 5})
+	for i := 0; i < 8; i++ {
+		seedAnalysis(t, db, fmt.Sprintf("l%d", i), []string{"limits"})
+	}
+	
+	r := gin.New()
+	r.GET("/search", h.SearchAnalyses)
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?"+tt.query, nil))
+			require.Equal(t, http.StatusOK, w.Code)
+			
+			var resp struct {
+				Count int `json:"count"`
+			}
+			decodeMeta(t, w, &resp)
+			assert.Equal(t, tt.expected, resp.Count)
+		})
+	}
+}
+
+func TestHandler_SearchAnalyses_Sentiment(t *testing.T) {
+	h, db := newTestHandlerWithConfig(t, newStubProvider(), Config{
+		ValidSentiments: []string{"negative", "neutral", "positive", "mixed"},
+	})
+	for id, sentiment := range map[string]string{"n1": "negative", "n2": "negative", "p1": "positive", "m1": "mixed"} {
+		err := db.SaveAnalysis(&models.TextAnalysis{
+			ID:         id,
+			Text:       "Text for " + id,
+			Summary:    "Summary for " + id,
+			Metadata:   map[string]interface{}{"sentiment": sentiment},
+			Confidence: 0.8,
+			CreatedAt:  time.Now(),
+		})
+		require.NoError(t, err)
+	}
+	
+	r := gin.New()
+	r.GET("/search", h.SearchAnalyses)
+	
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"sentiment=negative", []string{"n1", "n2"}},
+		{"sentiment=Mixed", []string{"m1"}},
+		{"sentiment=neutral", []string{}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?"+tt.query, nil))
+			require.Equal(t, http.StatusOK, w.Code)
+			
+			var results []models.TextAnalysis
+			decodeData(t, w, &results)
+			ids := []string{}
+			for _, result := range results {
+				ids = append(ids, result.ID)
+			}
+			assert.ElementsMatch(t, tt.expected, ids)
+		})
+	}
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?sentiment=furious", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, models.CodeInvalidRequest, decodeError(t, w).Code)
+}
+
+func TestHandler_SearchAnalyses_Facets(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	seeds := []struct {
+		id        string
+		sentiment string
+		topics    []string
+		tag       string
+	}{
+		{"a1", "positive", []string{"golang", "databases"}, "ops"},
+		{"a2", "positive", []string{"golang"}, "ops"},
+		{"a3", "negative", []string{"databases", "outages"}, "ops"},
+		{"a4", "", []string{"golang"}, "ops"},
+		{"b1", "negative", []string{"marketing"}, "sales"},
+	}
+	for _, seed := range seeds {
+		metadata := map[string]interface{}{"topics": seed.topics, "tags": []string{seed.tag}}
+		if seed.sentiment != "" {
+			metadata["sentiment"] = seed.sentiment
+		}
+		err := db.SaveAnalysis(&models.TextAnalysis{
+			ID:         seed.id,
+			Text:       "Text for " + seed.id,
+			Summary:    "Summary for " + seed.id,
+			Metadata:   metadata,
+			Confidence: 0.8,
+			CreatedAt:  time.Now(),
+		})
+		require.NoError(t, err)
+	}
+	
+	r := gin.New()
+	r.GET("/search", h.SearchAnalyses)
+	
+	search := func(query string) (*httptest.ResponseRecorder, []models.TextAnalysis) {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var results []models.TextAnalysis
+		decodeData(t, w, &results)
+		return w, results
+	}
+	
+	_, all := search("tag=ops")
+	w, page := search("tag=ops&limit=1&facets=true")
+	assert.Len(t, page, 1)
+	
+	var meta struct {
+		Facets models.SearchFacets `json:"facets"`
+	}
+	decodeMeta(t, w, &meta)
+	
+	// The facets cover the whole filtered set, not just the page.
+	sentiments := map[string]int{}
+	topics := map[string]int{}
+	for _, result := range all {
+		sentiments[result.Metadata["sentiment"].(string)]++
+		for _, topic := range result.MetadataStrings("topics") {
+			topics[topic]++
+		}
+	}
+	assert.Equal(t, len(all), meta.Facets.Total)
+	assert.Equal(t, sentiments, meta.Facets.Sentiments)
+	assert.Equal(t, map[string]int{"positive": 2, "negative": 1, "neutral": 1}, meta.Facets.Sentiments)
+	assert.Equal(t, []models.TopicCount{
+		{Topic: "golang", Count: 3},
+		{Topic: "databases", Count: 2},
+		{Topic: "outages", Count: 1},
+	}, meta.Facets.Topics)
+	for _, topic := range meta.Facets.Topics {
+		assert.Equal(t, topics[topic.Topic], topic.Count)
+	}
+	
+	w, _ = search("tag=ops")
+	var plain map[string]interface{}
+	decodeMeta(t, w, &plain)
+	assert.NotContains(t, plain, "facets")
+	
+	w, _ = search("tag=missing&facets=true")
+	var empty struct {
+		Facets models.SearchFacets `json:"facets"`
+	}
+	decodeMeta(t, w, &empty)
+	assert.Zero(t, empty.Facets.Total)
+	assert.Empty(t, empty.Facets.Sentiments)
+	assert.Empty(t, empty.Facets.Topics)
+}
+
+func TestHandler_MetadataFields(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	err := db.SaveAnalysis(&models.TextAnalysis{
+		ID:      "f1",
+		Text:    "Text for f1",
+		Summary: "Summary for f1",
+		Metadata: map[string]interface{}{
+			"title":     "Fields",
+			"topics":    []string{"golang"},
+			"sentiment": "positive",
+			"keywords":  []string{"projection"},
+		},
+		CreatedAt: time.Now(),
+	})
+	require.NoError(t, err)
+	analysis, err := db.GetAnalysis("f1")
+	require.NoError(t, err)
+	require.NoError(t, db.UpdateAnalysis(analysis))
+	
+	r := gin.New()
+	r.GET("/search", h.SearchAnalyses)
+	r.GET("/analyses/:id/history", h.GetAnalysisHistory)
+	r.GET("/export.ndjson", h.ExportNDJSON)
+	
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		return w
+	}
+	
+	keys := func(metadata map[string]interface{}) []string {
+		result := make([]string, 0, len(metadata))
+		for key := range metadata {
+			result = append(result, key)
+		}
+		sort.Strings(result)
+		return result
+	}
+	
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"?fields=topics,sentiment", []string{"sentiment", "topics"}},
+		{"?fields=+topics+,,missing", []string{"topics"}},
+		{"?fields=missing", []string{}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			var results []models.TextAnalysis
+			decodeData(t, get("/search"+tt.query), &results)
+			require.Len(t, results, 1)
+			assert.Equal(t, tt.expected, keys(results[0].Metadata))
+			
+			var versions []models.AnalysisVersion
+			decodeData(t, get("/analyses/f1/history"+tt.query), &versions)
+			require.Len(t, versions, 1)
+			assert.Equal(t, tt.expected, keys(versions[0].Metadata))
+			
+			var exported models.TextAnalysis
+			require.NoError(t, json.Unmarshal(get("/export.ndjson"+tt.query).Body.Bytes(), &exported))
+			assert.Equal(t, tt.expected, keys(exported.Metadata))
+		})
+	}
+	
+	var results []models.TextAnalysis
+	decodeData(t, get("/search"), &results)
+	require.Len(t, results, 1)
+	assert.Subset(t, keys(results[0].Metadata), []string{"keywords", "sentiment", "title", "topics", "schema_version"})
+}
+
+func TestHandler_ProviderAndModel(t *testing.T) {
+	h, db := newTestHandlerWithConfig(t, newStubProvider(), Config{LLMProvider: "openai", LLMModel: "gpt-4o-mini"})
+	require.NoError(t, db.SaveAnalysis(&models.TextAnalysis{
+		ID:         "older",
+		Text:       "Analyzed by another provider.",
+		Summary:    "Summary",
+		Metadata:   map[string]interface{}{"provider": "ollama", "model": "llama3"},
+		Confidence: 0.8,
+		CreatedAt:  time.Now(),
+	}))
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.GET("/search", h.SearchAnalyses)
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "Quarterly revenue grew in every region."})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp models.AnalyzeResponse
+	decodeData(t, w, &resp)
+	
+	stored, err := db.GetAnalysis(resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "openai", stored.Metadata["provider"])
+	assert.Equal(t, "gpt-4o-mini", stored.Metadata["model"])
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?provider=OpenAI", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var results []models.TextAnalysis
+	decodeData(t, w, &results)
+	require.Len(t, results, 1)
+	assert.Equal(t, resp.ID, results[0].ID)
+	assert.Equal(t, "gpt-4o-mini", results[0].Metadata["model"])
+	
+	w = postJSON(r, "/analyze", models.AnalyzeRequest{Text: "Only keywords here.", Include: []string{"keywords"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var keywordsOnly models.AnalyzeResponse
+	decodeData(t, w, &keywordsOnly)
+	assert.NotContains(t, keywordsOnly.Metadata, "provider", "no provider is recorded when it was not called")
+}
+
+func TestHandler_NodeID(t *testing.T) {
+	h, db := newTestHandlerWithConfig(t, newStubProvider(), Config{NodeID: "api-2"})
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "Served by the second instance."})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp models.AnalyzeResponse
+	decodeData(t, w, &resp)
+	assert.Equal(t, "api-2", resp.Metadata["node_id"])
+	
+	stored, err := db.GetAnalysis(resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "api-2", stored.Metadata["node_id"])
+	
+	w = postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: []string{"Batch item on the same node."}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var batch models.BatchAnalyzeResponse
+	decodeData(t, w, &batch)
+	require.Len(t, batch.Results, 1)
+	stored, err = db.GetAnalysis(batch.Results[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "api-2", stored.Metadata["node_id"])
+	
+	h, _ = newTestHandler(t, newStubProvider())
+	r = gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	w = postJSON(r, "/analyze", models.AnalyzeRequest{Text: "No node configured."})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var plain models.AnalyzeResponse
+	decodeData(t, w, &plain)
+	assert.NotContains(t, plain.Metadata, "node_id")
+}
+
+func TestHandler_SearchAnalyses_ConfidenceRange(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	seedAnalysis(t, db, "a1", []string{"golang"})
+	
+	r := gin.New()
+	r.GET("/search", h.SearchAnalyses)
+	
+	tests := []struct {
+		query    string
+		status   int
+		expected int
+	}{
+		{"min_confidence=0.7&max_confidence=0.9", http.StatusOK, 1},
+		{"min_confidence=0.85", http.StatusOK, 0},
+		{"min_confidence=0.9&max_confidence=0.7", http.StatusBadRequest, 0},
+		{"max_confidence=1.5", http.StatusBadRequest, 0},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?"+tt.query, nil))
+			require.Equal(t, tt.status, w.Code)
+			
+			if tt.status != http.StatusOK {
+				assert.Equal(t, models.CodeInvalidRequest, decodeError(t, w).Code)
+				return
+			}
+			
+			var meta struct {
+				Count int `json:"count"`
+			}
+			decodeMeta(t, w, &meta)
+			assert.Equal(t, tt.expected, meta.Count)
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, Config{SearchDefaultLimit: 50, SearchMaxLimit: 100}.Validate())
+	assert.NoError(t, Config{SearchDefaultLimit: 100, SearchMaxLimit: 100}.Validate())
+	assert.Error(t, Config{SearchDefaultLimit: 200, SearchMaxLimit: 100}.Validate())
+	assert.NoError(t, Config{InvalidUTF8: InvalidUTF8Sanitize}.Validate())
+	assert.Error(t, Config{InvalidUTF8: "replace"}.Validate())
+	assert.NoError(t, Config{MinConfidenceToStore: 0.5}.Validate())
+	assert.Error(t, Config{MinConfidenceToStore: 1.5}.Validate())
+}
+
+func TestHandler_SearchAnalyses_Cursor(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	for i := 0; i < 3; i++ {
+		seedAnalysis(t, db, fmt.Sprintf("c%d", i), []string{"cursor"})
+	}
+	
+	r := gin.New()
+	r.GET("/search", h.SearchAnalyses)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?limit=2", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var first struct {
+		Count      int    `json:"count"`
+		NextCursor string `json:"next_cursor"`
+	}
+	decodeMeta(t, w, &first)
+	assert.Equal(t, 2, first.Count)
+	require.NotEmpty(t, first.NextCursor)
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?limit=2&cursor="+first.NextCursor, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var second struct {
+		Count      int    `json:"count"`
+		NextCursor string `json:"next_cursor"`
+	}
+	decodeMeta(t, w, &second)
+	assert.Equal(t, 1, second.Count)
+	assert.Empty(t, second.NextCursor)
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?cursor=garbage!", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestHandler_IncludeText(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	seedAnalysis(t, db, "t1", []string{"golang"})
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	r.GET("/search", h.SearchAnalyses)
+	
+	textOf := func(w *httptest.ResponseRecorder) (string, bool) {
+		var data map[string]interface{}
+		decodeData(t, w, &data)
+		text, ok := data["text"].(string)
+		return text, ok
+	}
+	
+	analyzeTests := []struct {
+		query    string
+		expected bool
+	}{
+		{"", false},
+		{"?include_text=false", false},
+		{"?include_text=true", true},
+		{"?include_text=maybe", false},
+	}
+	for _, tt := range analyzeTests {
+		t.Run("analyze"+tt.query, func(t *testing.T) {
+			w := postJSON(r, "/analyze"+tt.query, models.AnalyzeRequest{Text: "Echo this text back."})
+			require.Equal(t, http.StatusOK, w.Code)
+			text, ok := textOf(w)
+			assert.Equal(t, tt.expected, ok)
+			if tt.expected {
+				assert.Equal(t, "Echo this text back.", text)
+			}
+		})
+	}
+	
+	w := postJSON(r, "/batch-analyze?include_text=true", models.BatchAnalyzeRequest{Texts: []string{"first text"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	var batch models.BatchAnalyzeResponse
+	decodeData(t, w, &batch)
+	require.Len(t, batch.Results, 1)
+	assert.Equal(t, "first text", batch.Results[0].Text)
+	
+	w = postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: []string{"second text"}})
+	var trimmed models.BatchAnalyzeResponse
+	decodeData(t, w, &trimmed)
+	require.Len(t, trimmed.Results, 1)
+	assert.Empty(t, trimmed.Results[0].Text)
+	
+	search := func(query string) []map[string]interface{} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?topic=golang"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		var results []map[string]interface{}
+		decodeData(t, w, &results)
+		require.Len(t, results, 1)
+		return results
+	}
+	
+	assert.Equal(t, "Text for t1", search("")[0]["text"])
+	assert.Equal(t, "Text for t1", search("&include_text=true")[0]["text"])
+	assert.NotContains(t, search("&include_text=false")[0], "text")
+}
+
+type countingStrategy struct {
+	calls int
+}
+
+func (s *countingStrategy) ExtractKeywords(text string, topN int) []string {
+	s.calls++
+	return []string{"counted"}
+}
+
+func (s *countingStrategy) ExtractKeywordsWithMinLength(text string, topN, minLength int) []string {
+	s.calls++
+	return []string{"counted"}
+}
+
+func TestHandler_AnalyzeText_Include(t *testing.T) {
+	tests := []struct {
+		name         string
+		include      []string
+		present      []string
+		absent       []string
+		llmCalls     int
+		keywordCalls int
+		summary      bool
+	}{
+		{
+			name:         "Default computes everything",
+			present:      []string{"title", "topics", "sentiment", "sentiment_score", "keywords", "language"},
+			llmCalls:     1,
+			keywordCalls: 1,
+			summary:      true,
+		},
+		{
+			name:     "Summary only",
+			include:  []string{"summary"},
+			absent:   []string{"title", "topics", "sentiment", "keywords", "language", "keyword_ms"},
+			llmCalls: 1,
+			summary:  true,
+		},
+		{
+			name:         "Keywords and topics",
+			include:      []string{"keywords", "topics"},
+			present:      []string{"topics", "keywords"},
+			absent:       []string{"title", "sentiment", "language"},
+			llmCalls:     1,
+			keywordCalls: 1,
+		},
+		{
+			name:         "Local components skip the provider",
+			include:      []string{"keywords", "language"},
+			present:      []string{"keywords", "language"},
+			absent:       []string{"title", "topics", "sentiment"},
+			keywordCalls: 1,
+		},
+		{
+			name:    "Language without keywords",
+			include: []string{"language"},
+			present: []string{"language"},
+			absent:  []string{"keywords", "topics"},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newFlakyProvider(nil)
+			h, db := newTestHandler(t, provider)
+			strategy := &countingStrategy{}
+			h.keywordExtractors = map[string]keywordStrategy{analyzer.DefaultLanguage: strategy}
+			
+			r := gin.New()
+			r.POST("/analyze", h.AnalyzeText)
+			
+			text := "The quick brown fox jumps over the lazy dog."
+			w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: text, Include: tt.include})
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			
+			var resp models.AnalyzeResponse
+			decodeData(t, w, &resp)
+			for _, key := range tt.present {
+				assert.Contains(t, resp.Metadata, key)
+			}
+			for _, key := range tt.absent {
+				assert.NotContains(t, resp.Metadata, key)
+			}
+			assert.Equal(t, tt.summary, resp.Summary != "")
+			assert.Equal(t, tt.llmCalls, provider.calls[text])
+			assert.Equal(t, tt.keywordCalls, strategy.calls)
+			
+			stored, err := db.GetAnalysis(resp.ID)
+			require.NoError(t, err)
+			assert.Equal(t, resp.Summary, stored.Summary)
+			for _, key := range tt.present {
+				assert.Contains(t, stored.Metadata, key)
+			}
+			for _, key := range tt.absent {
+				assert.NotContains(t, stored.Metadata, key, "skipped components read back absent")
+			}
+			if tt.include == nil {
+				assert.NotContains(t, stored.Metadata, "components")
+			} else {
+				assert.ElementsMatch(t, tt.include, stored.MetadataStrings("components"))
+			}
+		})
+	}
+	
+	t.Run("Unknown component", func(t *testing.T) {
+		h, _ := newTestHandler(t, newStubProvider())
+		r := gin.New()
+		r.POST("/analyze", h.AnalyzeText)
+		
+		w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "Some text.", Include: []string{"entities"}})
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, models.CodeInvalidRequest, decodeError(t, w).Code)
+	})
+}
+
+func TestHandler_AnalyzeText_PartialRoundTrip(t *testing.T) {
+	h, _ := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.GET("/search", h.SearchAnalyses)
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "A short note about nothing much.", Include: []string{"summary"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?facets=true", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var results []models.TextAnalysis
+	decodeData(t, w, &results)
+	require.Len(t, results, 1)
+	for _, key := range []string{"title", "topics", "sentiment", "sentiment_score", "keywords", "language"} {
+		assert.NotContains(t, results[0].Metadata, key)
+	}
+	assert.Equal(t, []string{"summary"}, results[0].MetadataStrings("components"))
+	
+	var meta struct {
+		Facets models.SearchFacets `json:"facets"`
+	}
+	decodeMeta(t, w, &meta)
+	assert.Equal(t, 1, meta.Facets.Total)
+	assert.Empty(t, meta.Facets.Sentiments, "a skipped sentiment is not counted as neutral")
+}
+
+func TestTruncateAtWord(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		limit     int
+		expected  string
+		truncated bool
+	}{
+		{"within limit", "Short summary.", 20, "Short summary.", false},
+		{"disabled", "Short summary.", 0, "Short summary.", false},
+		{"word boundary", "The quick brown fox jumps over the lazy dog", 20, "The quick brown...", true},
+		{"cut lands on a space", "The quick brown fox jumps", 18, "The quick brown...", true},
+		{"trailing punctuation dropped", "Alpha, beta, gamma, delta", 16, "Alpha, beta...", true},
+		{"single long word", "Supercalifragilistic", 10, "Superca...", true},
+		{"counts characters not bytes", "café crème brûlée dessert", 20, "café crème brûlée...", true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, truncated := truncateAtWord(tt.text, tt.limit)
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, tt.truncated, truncated)
+			if tt.limit > 0 {
+				assert.LessOrEqual(t, len([]rune(got)), tt.limit)
+			}
+		})
+	}
+}
+
+func TestHandler_MaxSummaryChars(t *testing.T) {
+	provider := newStubProvider()
+	provider.result.Summary = strings.Repeat("This summary keeps going and going. ", 20)
+	h, db := newTestHandlerWithConfig(t, provider, Config{MaxSummaryChars: 50})
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "A text whose summary runs away."})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var single models.AnalyzeResponse
+	decodeData(t, w, &single)
+	
+	w = postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: []string{"Another runaway text."}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var batch models.BatchAnalyzeResponse
+	decodeData(t, w, &batch)
+	require.Len(t, batch.Results, 1)
+	
+	for _, resp := range []models.AnalyzeResponse{single, batch.Results[0]} {
+		assert.Equal(t, "This summary keeps going and going. This...", resp.Summary)
+		assert.Equal(t, true, resp.Metadata["summary_truncated"])
+		
+		stored, err := db.GetAnalysis(resp.ID)
+		require.NoError(t, err)
+		assert.Equal(t, resp.Summary, stored.Summary)
+	}
+	
+	provider.result.Summary = "A short summary."
+	w = postJSON(r, "/analyze", models.AnalyzeRequest{Text: "A text with a short summary."})
+	require.Equal(t, http.StatusOK, w.Code)
+	var short models.AnalyzeResponse
+	decodeData(t, w, &short)
+	assert.Equal(t, "A short summary.", short.Summary)
+	assert.NotContains(t, short.Metadata, "summary_truncated")
+}
\ No newline at end of file
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"html"
+	"strings"
+	"unicode"
+	
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+const (
+	highlightPre      = "<mark>"
+	highlightPost     = "</mark>"
+	maxHighlightChars = 160
+)
+
+// searchHighlight finds keyword in the text of an analysis, or in its
+// summary when the text has no match, and returns an excerpt around the
+// first match. It returns nil when neither contains the keyword, which
+// happens when the search matched the metadata only.
+func searchHighlight(analysis *models.TextAnalysis, keyword string) *models.SearchHighlight {
+	for _, field := range []struct {
+		name  string
+		value string
+	}{
+		{"text", analysis.Text},
+		{"summary", analysis.Summary},
+	} {
+		if snippet, matches := highlightExcerpt(field.value, keyword, maxHighlightChars); matches > 0 {
+			return &models.SearchHighlight{Field: field.name, Snippet: snippet, Matches: matches}
+		}
+	}
+	return nil
+}
+
+// highlightExcerpt cuts a window of at most limit characters around the
+// first case-insensitive match of keyword in text and wraps every match
+// inside it in <mark> tags. The window is trimmed to word boundaries and
+// marked with an ellipsis where it cuts the text; neither the ellipses nor
+// the tags count toward limit. The excerpt is HTML-escaped so it can be
+// inserted into a page as is. It also returns the number of matches in the
+// whole text. The window always covers the first match, so a keyword longer
+// than limit is returned whole.
+func highlightExcerpt(text, keyword string, limit int) (string, int) {
+	runes := []rune(text)
+	needle := []rune(keyword)
+	if len(needle) == 0 {
+		return "", 0
+	}
+	for i, r := range needle {
+		needle[i] = unicode.ToLower(r)
+	}
+	
+	matches := findMatches(runes, needle)
+	if len(matches) == 0 {
+		return "", 0
+	}
+	
+	first := matches[0]
+	start := first - (limit-len(needle))/2
+	if start < 0 {
+		start = 0
+	}
+	end := start + limit
+	if end > len(runes) {
+		end = len(runes)
+		if start = end - limit; start < 0 {
+			start = 0
+		}
+	}
+	if start > first {
+		start = first
+	}
+	if end < first+len(needle) {
+		end = first + len(needle)
+	}
+	
+	// Drop the partial words at the cut edges, but never the first match.
+	if start > 0 && !unicode.IsSpace(runes[start-1]) {
+		for i := start; i < first; i++ {
+			if unicode.IsSpace(runes[i]) {
+				start = i + 1
+				break
+			}
+		}
+	}
+	if end < len(runes) && !unicode.IsSpace(runes[end]) {
+		for i := end - 1; i >= first+len(needle); i-- {
+			if unicode.IsSpace(runes[i]) {
+				end = i
+				break
+			}
+		}
+	}
+	
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(summaryEllipsis)
+	}
+	pos := start
+	for _, match := range matches {
+		if match < start || match+len(needle) > end {
+			continue
+		}
+		b.WriteString(escapeExcerpt(runes[pos:match]))
+		b.WriteString(highlightPre)
+		b.WriteString(escapeExcerpt(runes[match : match+len(needle)]))
+		b.WriteString(highlightPost)
+		pos = match + len(needle)
+	}
+	b.WriteString(escapeExcerpt(runes[pos:end]))
+	if end < len(runes) {
+		b.WriteString(summaryEllipsis)
+	}
+	return b.String(), len(matches)
+}
+
+// findMatches returns the rune offsets of the non-overlapping matches of the
+// lower-cased needle in runes. Comparing rune by rune keeps the offsets valid
+// for characters whose lower case has a different UTF-8 length.
+func findMatches(runes, needle []rune) []int {
+	var matches []int
+	for i := 0; i+len(needle) <= len(runes); i++ {
+		matched := true
+		for j, r := range needle {
+			if unicode.ToLower(runes[i+j]) != r {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, i)
+			i += len(needle) - 1
+		}
+	}
+	return matches
+}
+
+// escapeExcerpt flattens line breaks so an excerpt reads as one line and
+// escapes it for HTML.
+func escapeExcerpt(runes []rune) string {
+	return html.EscapeString(strings.Map(func(r rune) rune {
+		if r == '\n' || r == '\r' || r == '\t' {
+			return ' '
+		}
+		return r
+	}, string(runes)))
+}
\ No newline at end of file
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+var markTags = regexp.MustCompile(`</?mark>`)
+
+// visibleChars counts the characters of an excerpt without its tags and
+// ellipses.
+func visibleChars(snippet string) int {
+	snippet = markTags.ReplaceAllString(snippet, "")
+	snippet = strings.TrimSuffix(strings.TrimPrefix(snippet, summaryEllipsis), summaryEllipsis)
+	return utf8.RuneCountInString(snippet)
+}
+
+func TestHighlightExcerpt(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		keyword  string
+		limit    int
+		expected string
+		matches  int
+	}{
+		{"short text", "The cache warmed up.", "cache", 160, "The <mark>cache</mark> warmed up.", 1},
+		{"case insensitive", "Cache misses, then CACHE hits.", "cache", 160, "<mark>Cache</mark> misses, then <mark>CACHE</mark> hits.", 2},
+		{"no match", "Nothing relevant here.", "cache", 160, "", 0},
+		{"cut at word boundaries", "one two three four cache five six seven eight", "cache", 16, "...four <mark>cache</mark> five...", 1},
+		{"match at start", "cache one two three four five six", "cache", 14, "<mark>cache</mark> one two...", 1},
+		{"match at end", "one two three four five cache", "cache", 14, "...five <mark>cache</mark>", 1},
+		{"matches outside window counted", "cache a b c d e f g h i j k l m n o p cache", "cache", 12, "<mark>cache</mark> a b c...", 2},
+		{"line breaks flattened", "first line\ncache line", "cache", 160, "first line <mark>cache</mark> line", 1},
+		{"html escaped", "<b>cache</b> & more", "cache", 160, "&lt;b&gt;<mark>cache</mark>&lt;/b&gt; &amp; more", 1},
+		{"keyword longer than limit", "a verylongkeyword b", "verylongkeyword", 5, "...<mark>verylongkeyword</mark>...", 1},
+		{"multibyte text", "Ärger über den ÜBERLAUF im Cache", "überlauf", 160, "Ärger über den <mark>ÜBERLAUF</mark> im Cache", 1},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			snippet, matches := highlightExcerpt(tt.text, tt.keyword, tt.limit)
+			assert.Equal(t, tt.expected, snippet)
+			assert.Equal(t, tt.matches, matches)
+		})
+	}
+}
+
+func TestHighlightExcerpt_Bounded(t *testing.T) {
+	text := strings.Repeat("filler words around ", 50) + "the outage began " + strings.Repeat("and more filler text ", 50) + "outage"
+	
+	snippet, matches := highlightExcerpt(text, "outage", maxHighlightChars)
+	assert.Equal(t, 2, matches)
+	assert.Contains(t, snippet, "<mark>outage</mark>")
+	assert.Equal(t, 1, strings.Count(snippet, "<mark>"), "the second match is outside the excerpt")
+	assert.LessOrEqual(t, visibleChars(snippet), maxHighlightChars)
+	assert.True(t, strings.HasPrefix(snippet, summaryEllipsis))
+	assert.True(t, strings.HasSuffix(snippet, summaryEllipsis))
+}
+
+func TestHandler_SearchAnalyses_Highlight(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	seeds := []*models.TextAnalysis{
+		{ID: "h1", Text: strings.Repeat("Background detail. ", 20) + "The replica lagged behind the primary. " + strings.Repeat("More detail. ", 20), Summary: "Replication trouble."},
+		{ID: "h2", Text: "Nothing to see in the text.", Summary: "The replica recovered."},
+		{ID: "h3", Text: "Unrelated text.", Summary: "Unrelated summary.", Metadata: map[string]interface{}{"topics": []string{"replica"}}},
+	}
+	for _, seed := range seeds {
+		seed.CreatedAt = time.Now()
+		require.NoError(t, db.SaveAnalysis(seed))
+	}
+	
+	r := gin.New()
+	r.GET("/search", h.SearchAnalyses)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?keyword=replica&highlight=true&include_text=false", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var results []models.TextAnalysis
+	decodeData(t, w, &results)
+	assert.Len(t, results, 3)
+	
+	var meta struct {
+		Highlights map[string]models.SearchHighlight `json:"highlights"`
+	}
+	decodeMeta(t, w, &meta)
+	require.Len(t, meta.Highlights, 2, "h3 matched on metadata only")
+	
+	text := meta.Highlights["h1"]
+	assert.Equal(t, "text", text.Field)
+	assert.Equal(t, 1, text.Matches)
+	assert.Contains(t, text.Snippet, "<mark>replica</mark>")
+	assert.LessOrEqual(t, visibleChars(text.Snippet), maxHighlightChars)
+	
+	summary := meta.Highlights["h2"]
+	assert.Equal(t, "summary", summary.Field)
+	assert.Equal(t, "The <mark>replica</mark> recovered.", summary.Snippet)
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?keyword=replica", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var plain map[string]interface{}
+	decodeMeta(t, w, &plain)
+	assert.NotContains(t, plain, "highlights")
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?topic=replica&highlight=true", nil))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, models.CodeInvalidRequest, decodeError(t, w).Code)
+}
\ No newline at end of file
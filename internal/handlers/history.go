@@ -0,0 +1,32 @@
+package handlers
+
+import (
+	"net/http"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+func (h *Handler) GetAnalysisHistory(c *gin.Context) {
+	analysis, ok := h.loadAnalysisPrimary(c)
+	if !ok {
+		return
+	}
+	
+	versions, err := h.store(c.Request.Context()).GetAnalysisHistoryContext(c.Request.Context(), analysis.ID)
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	fields := metadataFields(c)
+	for _, version := range versions {
+		version.Metadata = projectMetadata(version.Metadata, fields)
+	}
+	
+	response.List(c, http.StatusOK, "versions", versions, gin.H{
+		"id":    analysis.ID,
+		"count": len(versions),
+	})
+}
\ No newline at end of file
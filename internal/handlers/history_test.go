@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_GetAnalysisHistory(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	seedAnalysis(t, db, "a1", []string{"golang"})
+	
+	for _, summary := range []string{"Revised once", "Revised twice"} {
+		analysis, err := db.GetAnalysis("a1")
+		require.NoError(t, err)
+		analysis.Summary = summary
+		require.NoError(t, db.UpdateAnalysis(analysis))
+	}
+	
+	r := gin.New()
+	r.GET("/analyses/:id/history", h.GetAnalysisHistory)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/analyses/a1/history", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var meta struct {
+		ID    string `json:"id"`
+		Count int    `json:"count"`
+	}
+	decodeMeta(t, w, &meta)
+	assert.Equal(t, "a1", meta.ID)
+	assert.Equal(t, 2, meta.Count)
+	
+	var versions []models.AnalysisVersion
+	decodeData(t, w, &versions)
+	require.Len(t, versions, 2)
+	assert.Equal(t, 2, versions[0].Version)
+	assert.Equal(t, "Revised once", versions[0].Summary)
+	assert.Equal(t, 1, versions[1].Version)
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/analyses/missing/history", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
\ No newline at end of file
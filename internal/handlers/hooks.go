@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+// AnalysisHook post-processes an analysis after the LLM and keyword steps and
+// before it is stored. Hooks may change the summary and metadata in place;
+// metadata caps are applied after every hook has run. Returning an error
+// aborts the analysis and nothing is stored.
+type AnalysisHook func(ctx context.Context, analysis *models.TextAnalysis) error
+
+// AddHook appends hooks to the pipeline. They run in registration order for
+// single and batch analyses. Register hooks before serving requests; the list
+// is not guarded for concurrent changes.
+func (h *Handler) AddHook(hooks ...AnalysisHook) {
+	h.hooks = append(h.hooks, hooks...)
+}
+
+func (h *Handler) runHooks(ctx context.Context, analysis *models.TextAnalysis) error {
+	for i, hook := range h.hooks {
+		if err := hook(ctx, analysis); err != nil {
+			return fmt.Errorf("analysis hook %d: %w", i, err)
+		}
+	}
+	return nil
+}
\ No newline at end of file
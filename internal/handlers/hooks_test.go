@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_AnalysisHooks(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	var order []string
+	h.AddHook(
+		func(ctx context.Context, analysis *models.TextAnalysis) error {
+			order = append(order, "flag")
+			analysis.Metadata["compliance_flag"] = strings.Contains(analysis.Text, "confidential")
+			return nil
+		},
+		func(ctx context.Context, analysis *models.TextAnalysis) error {
+			order = append(order, "classify")
+			analysis.Metadata["classifier"] = "internal-v1"
+			return nil
+		},
+	)
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "This confidential report covers quarterly revenue."})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, []string{"flag", "classify"}, order)
+	
+	var resp models.AnalyzeResponse
+	decodeData(t, w, &resp)
+	assert.Equal(t, true, resp.Metadata["compliance_flag"])
+	
+	stored, err := db.GetAnalysis(resp.ID)
+	require.NoError(t, err)
+	assert.Equal(t, true, stored.Metadata["compliance_flag"])
+	assert.Equal(t, "internal-v1", stored.Metadata["classifier"])
+	
+	w = postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: []string{"Public release notes for the new version."}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var batch models.BatchAnalyzeResponse
+	decodeData(t, w, &batch)
+	require.Len(t, batch.Results, 1)
+	
+	stored, err = db.GetAnalysis(batch.Results[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, false, stored.Metadata["compliance_flag"])
+	assert.Equal(t, "internal-v1", stored.Metadata["classifier"])
+}
+
+func TestHandler_AnalysisHookError(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	h.AddHook(func(ctx context.Context, analysis *models.TextAnalysis) error {
+		return errors.New("classifier unreachable")
+	})
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "This report covers quarterly revenue."})
+	require.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+	apiErr := decodeError(t, w)
+	assert.Equal(t, models.CodeHookFailed, apiErr.Code)
+	assert.Contains(t, apiErr.Details, "classifier unreachable")
+	assert.False(t, apiErr.Retryable)
+	
+	w = postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: []string{"First text.", "Second text."}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var batch models.BatchAnalyzeResponse
+	decodeData(t, w, &batch)
+	assert.Empty(t, batch.Results)
+	require.Len(t, batch.Failed, 2)
+	assert.Contains(t, batch.Failed[0].Error, "classifier unreachable")
+	
+	recent, err := db.GetRecentAnalyses(10)
+	require.NoError(t, err)
+	assert.Empty(t, recent, "nothing is stored when a hook fails")
+}
+
+func TestHandler_AnalysisHooks_Chunked(t *testing.T) {
+	chunks := []string{
+		"Go services talk to SQLite through database/sql.",
+		"The Go HTTP server handles each request in a goroutine.",
+	}
+	
+	h, db := newTestHandler(t, newChunkProvider())
+	var seen []string
+	h.AddHook(func(ctx context.Context, analysis *models.TextAnalysis) error {
+		seen = append(seen, analysis.ID)
+		analysis.Metadata["classifier"] = "internal-v1"
+		return nil
+	})
+	
+	r := gin.New()
+	r.POST("/analyze/chunked", h.AnalyzeChunked)
+	
+	w := postJSON(r, "/analyze/chunked", models.ChunkedAnalyzeRequest{Chunks: chunks})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp models.ChunkedAnalyzeResponse
+	decodeData(t, w, &resp)
+	assert.Equal(t, "internal-v1", resp.Metadata["classifier"])
+	assert.ElementsMatch(t, append([]string{resp.ID}, resp.ChunkIDs...), seen)
+	
+	for _, id := range append([]string{resp.ID}, resp.ChunkIDs...) {
+		stored, err := db.GetAnalysis(id)
+		require.NoError(t, err)
+		require.NotNil(t, stored)
+		assert.Equal(t, "internal-v1", stored.Metadata["classifier"], id)
+	}
+	
+	// A hook rejecting only the aggregate still fails the whole document.
+	h, db = newTestHandler(t, newChunkProvider())
+	h.AddHook(func(ctx context.Context, analysis *models.TextAnalysis) error {
+		if _, ok := analysis.Metadata["chunk_count"]; ok {
+			return errors.New("classifier unreachable")
+		}
+		return nil
+	})
+	
+	r = gin.New()
+	r.POST("/analyze/chunked", h.AnalyzeChunked)
+	
+	w = postJSON(r, "/analyze/chunked", models.ChunkedAnalyzeRequest{Chunks: chunks})
+	require.Equal(t, http.StatusInternalServerError, w.Code, w.Body.String())
+	assert.Equal(t, models.CodeHookFailed, decodeError(t, w).Code)
+	
+	recent, err := db.GetRecentAnalyses(10)
+	require.NoError(t, err)
+	assert.Empty(t, recent, "nothing is stored when a hook fails")
+}
\ No newline at end of file
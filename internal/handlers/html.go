@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"bytes"
+	"html/template"
+	"net/http"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+var analysisTemplate = template.Must(template.New("analysis").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 48rem; margin: 2rem auto; line-height: 1.5; }
+.badge { display: inline-block; padding: 0.1rem 0.6rem; border-radius: 1rem; background: #e5e7eb; }
+.badge.positive { background: #bbf7d0; }
+.badge.negative { background: #fecaca; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>{{.Summary}}</p>
+<h2>Topics</h2>
+{{if .Topics}}<ul>{{range .Topics}}<li>{{.}}</li>{{end}}</ul>{{else}}<p><em>None</em></p>{{end}}
+<h2>Keywords</h2>
+{{if .Keywords}}<ul>{{range .Keywords}}<li>{{.}}</li>{{end}}</ul>{{else}}<p><em>None</em></p>{{end}}
+<p>Sentiment: <span class="badge {{.Sentiment}}">{{.Sentiment}}</span></p>
+<p>Confidence: {{printf "%.2f" .Confidence}}</p>
+<p>Created: {{.Created}}</p>
+</body>
+</html>
+`))
+
+type analysisView struct {
+	Title      string
+	Summary    string
+	Topics     []string
+	Keywords   []string
+	Sentiment  string
+	Confidence float64
+	Created    string
+}
+
+// ExportHTML renders a single analysis as a standalone HTML page for quick
+// inspection in a browser. Stored values are escaped by html/template.
+func (h *Handler) ExportHTML(c *gin.Context) {
+	analysis, ok := h.loadAnalysis(c)
+	if !ok {
+		return
+	}
+	
+	var buf bytes.Buffer
+	if err := analysisTemplate.Execute(&buf, newAnalysisView(analysis)); err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeRenderError, err)
+		return
+	}
+	
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+func newAnalysisView(analysis *models.TextAnalysis) analysisView {
+	title, _ := analysis.Metadata["title"].(string)
+	if title == "" {
+		title = "Untitled Analysis"
+	}
+	
+	sentiment, _ := analysis.Metadata["sentiment"].(string)
+	if sentiment == "" {
+		sentiment = "unknown"
+	}
+	
+	return analysisView{
+		Title:      title,
+		Summary:    analysis.Summary,
+		Topics:     analysis.MetadataStrings("topics"),
+		Keywords:   analysis.MetadataStrings("keywords"),
+		Sentiment:  sentiment,
+		Confidence: analysis.Confidence,
+		Created:    analysis.CreatedAt.UTC().Format(time.RFC3339),
+	}
+}
\ No newline at end of file
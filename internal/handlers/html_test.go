@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_ExportHTML(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	
+	err := db.SaveAnalysis(&models.TextAnalysis{
+		ID:      "html-1",
+		Text:    "Original text",
+		Summary: `Click <script>alert("x")</script> & enjoy.`,
+		Metadata: map[string]interface{}{
+			"title":     "<b>Bold</b> claims",
+			"topics":    []string{"security"},
+			"keywords":  []string{"<img src=x onerror=alert(1)>"},
+			"sentiment": `positive" onmouseover="alert(1)`,
+		},
+		Confidence: 0.85,
+		CreatedAt:  time.Date(2024, 3, 1, 12, 30, 0, 0, time.UTC),
+	})
+	require.NoError(t, err)
+	
+	r := gin.New()
+	r.GET("/analyses/:id/html", h.ExportHTML)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/analyses/html-1/html", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	
+	body := w.Body.String()
+	assert.Contains(t, body, "<h1>&lt;b&gt;Bold&lt;/b&gt; claims</h1>")
+	assert.Contains(t, body, "Click &lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; enjoy.")
+	assert.Contains(t, body, "<li>security</li>")
+	assert.Contains(t, body, "<li>&lt;img src=x onerror=alert(1)&gt;</li>")
+	assert.Contains(t, body, "Confidence: 0.85")
+	assert.Contains(t, body, "Created: 2024-03-01T12:30:00Z")
+	
+	assert.NotContains(t, body, "<script>")
+	assert.NotContains(t, body, "<b>Bold")
+	assert.NotContains(t, body, "<img")
+	assert.NotContains(t, body, `" onmouseover="`)
+}
+
+func TestHandler_ExportHTML_NotFound(t *testing.T) {
+	h, _ := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.GET("/analyses/:id/html", h.ExportHTML)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/analyses/missing/html", nil))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
\ No newline at end of file
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"net/http"
+	"sync"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedLock
+}
+
+type keyedLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+func newKeyedMutex() *keyedMutex {
+	return &keyedMutex{locks: make(map[string]*keyedLock)}
+}
+
+func (km *keyedMutex) Lock(key string) func() {
+	km.mu.Lock()
+	lock, ok := km.locks[key]
+	if !ok {
+		lock = &keyedLock{}
+		km.locks[key] = lock
+	}
+	lock.refs++
+	km.mu.Unlock()
+	
+	lock.mu.Lock()
+	
+	return func() {
+		lock.mu.Unlock()
+		
+		km.mu.Lock()
+		lock.refs--
+		if lock.refs == 0 {
+			delete(km.locks, key)
+		}
+		km.mu.Unlock()
+	}
+}
+
+func (h *Handler) replayIdempotent(c *gin.Context, key string) bool {
+	analysisID, err := h.store(c.Request.Context()).GetIdempotencyKeyContext(c.Request.Context(), key, time.Now().Add(-h.config.IdempotencyTTL))
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return true
+	}
+	if analysisID == "" {
+		return false
+	}
+	
+	// The key was just read from the primary, so read the analysis there too:
+	// a lagging replica would miss it and the request would run twice.
+	analysis, err := h.store(c.Request.Context()).GetAnalysisPrimaryContext(c.Request.Context(), analysisID)
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return true
+	}
+	if analysis == nil {
+		return false
+	}
+	
+	resp := models.AnalyzeResponse{
+		ID:           analysis.ID,
+		Summary:      analysis.Summary,
+		Metadata:     analysis.Metadata,
+		Confidence:   analysis.Confidence,
+		ProcessingMS: analysis.ProcessingMS,
+		Persisted:    true,
+	}
+	if includeText(c, false) {
+		resp.Text = analysis.Text
+	}
+	
+	c.Header("Idempotent-Replayed", "true")
+	response.JSON(c, http.StatusOK, resp)
+	return true
+}
\ No newline at end of file
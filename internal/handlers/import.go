@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/database"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+const (
+	maxImportSize  = 1000
+	maxImportIDLen = 128
+)
+
+// ImportAnalyses loads analyses computed elsewhere, such as a system being
+// migrated from, without calling the LLM. Each analysis is validated and the
+// valid ones are inserted in one transaction; the response reports every
+// item as imported, skipped or failed.
+func (h *Handler) ImportAnalyses(c *gin.Context) {
+	var query models.ImportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	if !h.checkBodyEncoding(c) {
+		return
+	}
+	
+	var analyses []*models.TextAnalysis
+	if err := c.ShouldBindJSON(&analyses); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	if len(analyses) == 0 {
+		response.RespondError(c, http.StatusBadRequest, models.CodeEmptyInput, errors.New("no analyses provided"))
+		return
+	}
+	if len(analyses) > maxImportSize {
+		response.RespondError(c, http.StatusBadRequest, models.CodeBatchSizeExceeded, fmt.Errorf("maximum %d analyses allowed per import", maxImportSize))
+		return
+	}
+	
+	resp := models.ImportResponse{Imported: make([]string, 0, len(analyses))}
+	valid := make([]*models.TextAnalysis, 0, len(analyses))
+	indices := make([]int, 0, len(analyses))
+	for i, analysis := range analyses {
+		if err := h.prepareImport(analysis); err != nil {
+			resp.Failed = append(resp.Failed, models.BatchError{Index: i, Error: err.Error()})
+			continue
+		}
+		valid = append(valid, analysis)
+		indices = append(indices, i)
+	}
+	
+	if len(valid) > 0 {
+		ctx := c.Request.Context()
+		results, err := h.store(ctx).ImportAnalysesContext(ctx, valid)
+		if err != nil {
+			response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+			return
+		}
+		
+		for i, err := range results {
+			switch {
+			case err == nil:
+				resp.Imported = append(resp.Imported, valid[i].ID)
+			case errors.Is(err, database.ErrDuplicateID) && query.OnDuplicate == "skip":
+				resp.Skipped = append(resp.Skipped, valid[i].ID)
+			default:
+				resp.Failed = append(resp.Failed, models.BatchError{Index: indices[i], Error: err.Error()})
+			}
+		}
+	}
+	
+	sort.Slice(resp.Failed, func(i, j int) bool {
+		return resp.Failed[i].Index < resp.Failed[j].Index
+	})
+	resp.ImportedCount = len(resp.Imported)
+	resp.SkippedCount = len(resp.Skipped)
+	resp.FailedCount = len(resp.Failed)
+	
+	response.JSON(c, http.StatusOK, resp)
+}
+
+// prepareImport checks an imported analysis and fills in what may be left
+// out: metadata defaults to empty and created_at to now. Topics and tags are
+// normalized as for new analyses, so searches and facets find them.
+func (h *Handler) prepareImport(analysis *models.TextAnalysis) error {
+	if analysis == nil {
+		return errors.New("analysis cannot be null")
+	}
+	
+	analysis.ID = strings.TrimSpace(analysis.ID)
+	switch {
+	case analysis.ID == "":
+		return errors.New("id is required")
+	case len(analysis.ID) > maxImportIDLen:
+		return fmt.Errorf("id exceeds %d characters", maxImportIDLen)
+	case strings.TrimSpace(analysis.Text) == "":
+		return errors.New("text cannot be empty")
+	case analysis.Confidence < 0 || analysis.Confidence > 1:
+		return fmt.Errorf("confidence %v must be between 0 and 1", analysis.Confidence)
+	case analysis.ProcessingMS < 0:
+		return errors.New("processing_ms cannot be negative")
+	}
+	
+	if analysis.Metadata == nil {
+		analysis.Metadata = map[string]interface{}{}
+	}
+	if _, ok := analysis.Metadata["topics"]; ok {
+		analysis.Metadata["topics"] = h.topicNormalizer.NormalizeAll(analysis.MetadataStrings("topics"))
+	}
+	if _, ok := analysis.Metadata["tags"]; ok {
+		analysis.Metadata["tags"] = normalizeTags(analysis.MetadataStrings("tags"))
+	}
+	if analysis.CreatedAt.IsZero() {
+		analysis.CreatedAt = time.Now()
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,195 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func importFixtures() []models.TextAnalysis {
+	created := time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC)
+	return []models.TextAnalysis{
+		{
+			ID:      "legacy-1",
+			Text:    "Quarterly billing report.",
+			Summary: "Billing was up.",
+			Metadata: map[string]interface{}{
+				"topics": []string{"billing"},
+				"tags":   []string{"legacy"},
+			},
+			Confidence:   0.8,
+			CreatedAt:    created,
+			ProcessingMS: 120,
+		},
+		{
+			ID:         "legacy-2",
+			Text:       "Support ticket backlog.",
+			Summary:    "Tickets piled up.",
+			Confidence: 0.6,
+			CreatedAt:  created.Add(time.Hour),
+		},
+	}
+}
+
+func TestHandler_ImportAnalyses(t *testing.T) {
+	provider := newFlakyProvider(nil)
+	h, db := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/import", h.ImportAnalyses)
+	
+	w := postJSON(r, "/import", importFixtures())
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var resp models.ImportResponse
+	decodeData(t, w, &resp)
+	assert.Equal(t, []string{"legacy-1", "legacy-2"}, resp.Imported)
+	assert.Equal(t, 2, resp.ImportedCount)
+	assert.Zero(t, resp.FailedCount)
+	assert.Empty(t, provider.calls)
+	
+	stored, err := db.GetAnalysis("legacy-1")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "Billing was up.", stored.Summary)
+	assert.Equal(t, 0.8, stored.Confidence)
+	assert.Equal(t, time.Date(2023, 5, 1, 12, 0, 0, 0, time.UTC), stored.CreatedAt.UTC())
+	assert.Equal(t, []string{"billing"}, stored.MetadataStrings("topics"))
+	
+	tagged, err := db.SearchAnalyses(models.SearchQuery{SearchFilter: models.SearchFilter{Tags: []string{"legacy"}}})
+	require.NoError(t, err)
+	assert.Len(t, tagged, 1)
+}
+
+func TestHandler_ImportAnalyses_NormalizesTopicsAndTags(t *testing.T) {
+	h, db := newTestHandlerWithConfig(t, newStubProvider(), Config{
+		TopicAliases: map[string]string{"ml": "machine learning"},
+	})
+	
+	r := gin.New()
+	r.POST("/import", h.ImportAnalyses)
+	r.GET("/search", h.SearchAnalyses)
+	
+	w := postJSON(r, "/import", []models.TextAnalysis{{
+		ID:         "legacy-ml",
+		Text:       "Model training notes.",
+		Summary:    "Training went well.",
+		Metadata:   map[string]interface{}{"topics": []string{"Machine-Learning", "ML", "Data_Quality"}, "tags": []string{" Legacy ", "legacy", "Q3"}},
+		Confidence: 0.7,
+	}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	stored, err := db.GetAnalysis("legacy-ml")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, []string{"machine learning", "data quality"}, stored.MetadataStrings("topics"))
+	assert.Equal(t, []string{"legacy", "q3"}, stored.MetadataStrings("tags"))
+	
+	for _, query := range []string{"topic=machine-learning", "topic=ML", "tag=Legacy", "tag=q3"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/search?"+query, nil))
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		
+		var meta struct {
+			Count int `json:"count"`
+		}
+		decodeMeta(t, w, &meta)
+		assert.Equal(t, 1, meta.Count, query)
+	}
+}
+
+func TestHandler_ImportAnalyses_Duplicates(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/import", h.ImportAnalyses)
+	
+	fixtures := importFixtures()
+	require.Equal(t, http.StatusOK, postJSON(r, "/import", fixtures[:1]).Code)
+	
+	fresh := models.TextAnalysis{ID: "legacy-3", Text: "New text.", Confidence: 0.5}
+	
+	t.Run("Error by default", func(t *testing.T) {
+		changed := fixtures[0]
+		changed.Summary = "Overwritten?"
+		w := postJSON(r, "/import", []models.TextAnalysis{changed, fresh})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		
+		var resp models.ImportResponse
+		decodeData(t, w, &resp)
+		assert.Equal(t, []string{"legacy-3"}, resp.Imported)
+		require.Len(t, resp.Failed, 1)
+		assert.Equal(t, 0, resp.Failed[0].Index)
+		assert.Contains(t, resp.Failed[0].Error, "already exists")
+		
+		stored, err := db.GetAnalysis("legacy-1")
+		require.NoError(t, err)
+		assert.Equal(t, "Billing was up.", stored.Summary)
+	})
+	
+	t.Run("Skip on request", func(t *testing.T) {
+		w := postJSON(r, "/import?on_duplicate=skip", fixtures)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		
+		var resp models.ImportResponse
+		decodeData(t, w, &resp)
+		assert.Equal(t, []string{"legacy-2"}, resp.Imported)
+		assert.Equal(t, []string{"legacy-1"}, resp.Skipped)
+		assert.Zero(t, resp.FailedCount)
+	})
+	
+	t.Run("Repeated within one request", func(t *testing.T) {
+		repeat := models.TextAnalysis{ID: "legacy-4", Text: "Twice.", Confidence: 0.5}
+		w := postJSON(r, "/import", []models.TextAnalysis{repeat, repeat})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		
+		var resp models.ImportResponse
+		decodeData(t, w, &resp)
+		assert.Equal(t, []string{"legacy-4"}, resp.Imported)
+		require.Len(t, resp.Failed, 1)
+		assert.Equal(t, 1, resp.Failed[0].Index)
+	})
+	
+	t.Run("Unknown mode", func(t *testing.T) {
+		w := postJSON(r, "/import?on_duplicate=replace", fixtures)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+}
+
+func TestHandler_ImportAnalyses_Validation(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/import", h.ImportAnalyses)
+	
+	w := postJSON(r, "/import", []models.TextAnalysis{
+		{ID: "ok", Text: "Fine.", Confidence: 0.5},
+		{Text: "No id.", Confidence: 0.5},
+		{ID: "blank", Text: "   ", Confidence: 0.5},
+		{ID: "too-sure", Text: "Certain.", Confidence: 1.5},
+	})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var resp models.ImportResponse
+	decodeData(t, w, &resp)
+	assert.Equal(t, []string{"ok"}, resp.Imported)
+	require.Len(t, resp.Failed, 3)
+	assert.Equal(t, "id is required", resp.Failed[0].Error)
+	assert.Equal(t, 2, resp.Failed[1].Index)
+	assert.Contains(t, resp.Failed[2].Error, "between 0 and 1")
+	
+	stored, err := db.GetAnalysis("ok")
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.False(t, stored.CreatedAt.IsZero())
+	
+	w = postJSON(r, "/import", []models.TextAnalysis{})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, models.CodeEmptyInput, decodeError(t, w).Code)
+}
\ No newline at end of file
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"sort"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/analyzer"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+const (
+	// maxKeywordGraphCorpus bounds how many analyses, the most recent
+	// matching the filter, are counted; maxKeywordGraphEdges keeps the
+	// heaviest edges so the graph stays small enough to draw.
+	maxKeywordGraphCorpus = 1000
+	maxKeywordGraphEdges  = 200
+)
+
+// KeywordGraph returns the keyword co-occurrence graph of the stored
+// analyses: an edge for every pair of keywords listed together by at least
+// ?min_weight= analyses (default 1), heaviest first. The usual search filters
+// narrow the corpus.
+func (h *Handler) KeywordGraph(c *gin.Context) {
+	var query models.KeywordGraphQuery
+	
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	if !h.prepareFilter(c, &query.SearchFilter) {
+		return
+	}
+	
+	analyses, err := h.store(c.Request.Context()).SearchAnalysesContext(c.Request.Context(), models.SearchQuery{
+		SearchFilter: query.SearchFilter,
+		Limit:        maxKeywordGraphCorpus,
+	})
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	sets := make([][]string, len(analyses))
+	for i, analysis := range analyses {
+		sets[i] = analysis.MetadataStrings("keywords")
+	}
+	pairs, edgesTruncated := analyzer.CountCooccurrences(sets, query.MinWeight, maxKeywordGraphEdges)
+	
+	graph := models.KeywordGraph{
+		Nodes: make([]models.KeywordNode, 0),
+		Edges: make([]models.KeywordEdge, 0, len(pairs)),
+	}
+	onEdge := make(map[string]bool)
+	for _, pair := range pairs {
+		graph.Edges = append(graph.Edges, models.KeywordEdge{Source: pair.A, Target: pair.B, Weight: pair.Count})
+		onEdge[pair.A] = true
+		onEdge[pair.B] = true
+	}
+	
+	counts := make(map[string]int, len(onEdge))
+	for _, set := range sets {
+		seen := make(map[string]bool, len(set))
+		for _, keyword := range set {
+			if onEdge[keyword] && !seen[keyword] {
+				seen[keyword] = true
+				counts[keyword]++
+			}
+		}
+	}
+	for keyword, count := range counts {
+		graph.Nodes = append(graph.Nodes, models.KeywordNode{Keyword: keyword, Count: count})
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		if graph.Nodes[i].Count != graph.Nodes[j].Count {
+			return graph.Nodes[i].Count > graph.Nodes[j].Count
+		}
+		return graph.Nodes[i].Keyword < graph.Nodes[j].Keyword
+	})
+	
+	response.List(c, http.StatusOK, "graph", graph, gin.H{
+		"min_weight":      query.MinWeight,
+		"corpus_size":     len(analyses),
+		"truncated":       len(analyses) == maxKeywordGraphCorpus,
+		"edges_truncated": edgesTruncated,
+	})
+}
\ No newline at end of file
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/database"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func seedKeywords(t *testing.T, db *database.DB, id string, keywords []string) {
+	t.Helper()
+	
+	err := db.SaveAnalysis(&models.TextAnalysis{
+		ID:      id,
+		Text:    "Text for " + id,
+		Summary: "Summary for " + id,
+		Metadata: map[string]interface{}{
+			"topics":   []string{"general"},
+			"keywords": keywords,
+		},
+		Confidence: 0.8,
+		CreatedAt:  time.Now(),
+	})
+	require.NoError(t, err)
+}
+
+func TestHandler_KeywordGraph(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	seedKeywords(t, db, "a1", []string{"server", "database", "outage"})
+	seedKeywords(t, db, "a2", []string{"server", "database"})
+	seedKeywords(t, db, "a3", []string{"database", "server", "backup"})
+	seedKeywords(t, db, "a4", []string{"pasta", "sauce"})
+	seedKeywords(t, db, "a5", []string{"solo"})
+	
+	r := gin.New()
+	r.GET("/stats/keyword-graph", h.KeywordGraph)
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats/keyword-graph", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var graph models.KeywordGraph
+	decodeData(t, w, &graph)
+	assert.Equal(t, []models.KeywordEdge{
+		{Source: "database", Target: "server", Weight: 3},
+		{Source: "backup", Target: "database", Weight: 1},
+		{Source: "backup", Target: "server", Weight: 1},
+		{Source: "database", Target: "outage", Weight: 1},
+		{Source: "outage", Target: "server", Weight: 1},
+		{Source: "pasta", Target: "sauce", Weight: 1},
+	}, graph.Edges)
+	assert.Equal(t, []models.KeywordNode{
+		{Keyword: "database", Count: 3},
+		{Keyword: "server", Count: 3},
+		{Keyword: "backup", Count: 1},
+		{Keyword: "outage", Count: 1},
+		{Keyword: "pasta", Count: 1},
+		{Keyword: "sauce", Count: 1},
+	}, graph.Nodes)
+	
+	var meta map[string]interface{}
+	decodeMeta(t, w, &meta)
+	assert.Equal(t, float64(1), meta["min_weight"])
+	assert.Equal(t, float64(5), meta["corpus_size"])
+	assert.Equal(t, false, meta["truncated"])
+	assert.Equal(t, false, meta["edges_truncated"])
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats/keyword-graph?min_weight=2", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	graph = models.KeywordGraph{}
+	decodeData(t, w, &graph)
+	assert.Equal(t, []models.KeywordEdge{{Source: "database", Target: "server", Weight: 3}}, graph.Edges)
+	assert.Equal(t, []models.KeywordNode{{Keyword: "database", Count: 3}, {Keyword: "server", Count: 3}}, graph.Nodes)
+	
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats/keyword-graph?min_weight=4", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var raw json.RawMessage
+	decodeData(t, w, &raw)
+	assert.JSONEq(t, `{"nodes": [], "edges": []}`, string(raw))
+	
+	for _, query := range []string{"min_weight=0", "min_weight=abc"} {
+		w = httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats/keyword-graph?"+query, nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Equal(t, models.CodeInvalidRequest, decodeError(t, w).Code)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/analyzer"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+const (
+	defaultKeywordCount = 3
+	maxKeywordCount     = 20
+)
+
+// ExtractKeywords runs only the local keyword extractor: the LLM is never
+// called and nothing is stored. count defaults to 3 and is clamped to 20.
+func (h *Handler) ExtractKeywords(c *gin.Context) {
+	var req models.KeywordsRequest
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	req.Text = analyzer.NormalizeText(req.Text)
+	if h.rejectText(c, "text", req.Text) {
+		return
+	}
+	
+	count := req.Count
+	if count <= 0 {
+		count = defaultKeywordCount
+	}
+	if count > maxKeywordCount {
+		count = maxKeywordCount
+	}
+	
+	language, extractor := h.keywordExtractor(req.Text)
+	
+	response.JSON(c, http.StatusOK, models.KeywordsResponse{
+		Keywords: extractor.ExtractKeywords(req.Text, count),
+		Language: language,
+	})
+}
\ No newline at end of file
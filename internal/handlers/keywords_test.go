@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/llm"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_ExtractKeywords(t *testing.T) {
+	// The LLM is never consulted, so an unavailable provider must not matter.
+	provider := newStubProvider()
+	provider.err = llm.ErrLLMUnavailable
+	h, db := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/keywords", h.ExtractKeywords)
+	
+	words := make([]string, 30)
+	for i := range words {
+		words[i] = fmt.Sprintf("The %c%cment failed.", 'a'+i/26, 'a'+i%26)
+	}
+	text := strings.Join(words, " ")
+	
+	tests := []struct {
+		name     string
+		count    int
+		expected int
+	}{
+		{"default count", 0, 3},
+		{"explicit count", 7, 7},
+		{"clamped count", 50, 20},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postJSON(r, "/keywords", models.KeywordsRequest{Text: text, Count: tt.count})
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			
+			var resp models.KeywordsResponse
+			decodeData(t, w, &resp)
+			assert.Len(t, resp.Keywords, tt.expected)
+			assert.Equal(t, "en", resp.Language)
+		})
+	}
+	
+	stored, err := db.GetRecentAnalyses(10)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestHandler_ExtractKeywords_EmptyText(t *testing.T) {
+	h, _ := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/keywords", h.ExtractKeywords)
+	
+	for _, text := range []string{"", "   \n\t"} {
+		w := postJSON(r, "/keywords", models.KeywordsRequest{Text: text})
+		require.Equal(t, http.StatusBadRequest, w.Code)
+		assert.Equal(t, models.CodeEmptyInput, decodeError(t, w).Code)
+	}
+}
\ No newline at end of file
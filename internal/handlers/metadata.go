@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+// maxTopics matches the cap parseJSONResponse applies, so providers that skip
+// it can't store more.
+const maxTopics = 3
+
+// capMetadata truncates every list-valued metadata field to its limit before
+// the analysis is persisted and reports whether anything was dropped.
+func (h *Handler) capMetadata(metadata map[string]interface{}) bool {
+	truncated := false
+	
+	for key, value := range metadata {
+		limit := h.config.MaxMetadataItems
+		if key == "topics" {
+			limit = maxTopics
+		}
+		
+		switch values := value.(type) {
+		case []string:
+			if len(values) > limit {
+				metadata[key] = values[:limit]
+				truncated = true
+			}
+		case []interface{}:
+			if len(values) > limit {
+				metadata[key] = values[:limit]
+				truncated = true
+			}
+		}
+	}
+	
+	return truncated
+}
+
+const summaryEllipsis = "..."
+
+// capSummary shortens a summary longer than MaxSummaryChars characters and
+// reports whether it did. Callers flag the analysis with summary_truncated.
+func (h *Handler) capSummary(summary string) (string, bool) {
+	return truncateAtWord(summary, h.config.MaxSummaryChars)
+}
+
+// truncateAtWord cuts text to at most limit characters, ending at the last
+// word boundary that fits and marked with an ellipsis, which counts toward
+// the limit. A first word longer than that is cut mid-word.
+func truncateAtWord(text string, limit int) (string, bool) {
+	runes := []rune(text)
+	if limit <= 0 || len(runes) <= limit {
+		return text, false
+	}
+	
+	budget := limit - len(summaryEllipsis)
+	if budget <= 0 {
+		return string(runes[:limit]), true
+	}
+	
+	cut := string(runes[:budget])
+	if !unicode.IsSpace(runes[budget]) {
+		if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > 0 {
+			cut = cut[:i]
+		}
+	}
+	cut = strings.TrimRightFunc(cut, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	})
+	return cut + summaryEllipsis, true
+}
+
+// metadataFields returns the metadata keys a client asked for with
+// ?fields=topics,sentiment, or nil when it wants every field.
+func metadataFields(c *gin.Context) []string {
+	var fields []string
+	for _, field := range strings.Split(c.Query("fields"), ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// projectMetadata keeps only the requested fields of metadata; unknown
+// fields are skipped. With no fields the metadata is returned unchanged.
+func projectMetadata(metadata map[string]interface{}, fields []string) map[string]interface{} {
+	if len(fields) == 0 {
+		return metadata
+	}
+	
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		if value, ok := metadata[field]; ok {
+			projected[field] = value
+		}
+	}
+	return projected
+}
+
+// includeText reports whether a response should carry the analyzed text, as
+// asked with ?include_text=true|false. Without a valid value each endpoint
+// keeps its default: analyze responses omit the text and search includes it.
+func includeText(c *gin.Context, fallback bool) bool {
+	include, err := strconv.ParseBool(c.Query("include_text"))
+	if err != nil {
+		return fallback
+	}
+	return include
+}
+
+// omitText clears the echoed text of batch results unless the client asked
+// for it.
+func omitText(c *gin.Context, results []models.AnalyzeResponse) {
+	if includeText(c, false) {
+		return
+	}
+	for i := range results {
+		results[i].Text = ""
+	}
+}
\ No newline at end of file
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+// AddNote attaches a reviewer note to an analysis without touching its
+// metadata or history.
+func (h *Handler) AddNote(c *gin.Context) {
+	analysis, ok := h.loadAnalysisPrimary(c)
+	if !ok {
+		return
+	}
+	
+	var req models.NoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	text := strings.TrimSpace(req.Note)
+	if text == "" {
+		response.RespondError(c, http.StatusBadRequest, models.CodeEmptyInput, errors.New("note cannot be empty"))
+		return
+	}
+	
+	note, err := h.store(c.Request.Context()).AddNoteContext(c.Request.Context(), analysis.ID, text, time.Now())
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	response.JSON(c, http.StatusCreated, note)
+}
+
+func (h *Handler) ListNotes(c *gin.Context) {
+	analysis, ok := h.loadAnalysisPrimary(c)
+	if !ok {
+		return
+	}
+	
+	notes, err := h.store(c.Request.Context()).GetNotesContext(c.Request.Context(), analysis.ID)
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	response.List(c, http.StatusOK, "notes", notes, gin.H{
+		"id":    analysis.ID,
+		"count": len(notes),
+	})
+}
\ No newline at end of file
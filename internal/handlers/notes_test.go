@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_Notes(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	seedAnalysis(t, db, "n1", []string{"golang"})
+	before, err := db.GetAnalysis("n1")
+	require.NoError(t, err)
+	
+	r := gin.New()
+	r.POST("/analyses/:id/notes", h.AddNote)
+	r.GET("/analyses/:id/notes", h.ListNotes)
+	
+	listNotes := func(id string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/analyses/"+id+"/notes", nil))
+		return w
+	}
+	
+	w := listNotes("n1")
+	require.Equal(t, http.StatusOK, w.Code)
+	var notes []models.Note
+	decodeData(t, w, &notes)
+	assert.Empty(t, notes)
+	
+	for _, text := range []string{"  Summary misses the second point.  ", "Topics look right."} {
+		w = postJSON(r, "/analyses/n1/notes", models.NoteRequest{Note: text})
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	}
+	var created models.Note
+	decodeData(t, w, &created)
+	assert.Equal(t, "n1", created.AnalysisID)
+	assert.Equal(t, "Topics look right.", created.Note)
+	assert.NotZero(t, created.ID)
+	
+	w = listNotes("n1")
+	require.Equal(t, http.StatusOK, w.Code)
+	decodeData(t, w, &notes)
+	require.Len(t, notes, 2)
+	assert.Equal(t, "Summary misses the second point.", notes[0].Note)
+	assert.Equal(t, "Topics look right.", notes[1].Note)
+	
+	after, err := db.GetAnalysis("n1")
+	require.NoError(t, err)
+	assert.Equal(t, before.Metadata, after.Metadata)
+	history, err := db.GetAnalysisHistory("n1")
+	require.NoError(t, err)
+	assert.Empty(t, history)
+	
+	w = postJSON(r, "/analyses/n1/notes", models.NoteRequest{Note: "   "})
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, models.CodeEmptyInput, decodeError(t, w).Code)
+	
+	w = postJSON(r, "/analyses/missing/notes", models.NoteRequest{Note: "Orphan note."})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, models.CodeNotFound, decodeError(t, w).Code)
+	
+	w = listNotes("missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, models.CodeNotFound, decodeError(t, w).Code)
+}
\ No newline at end of file
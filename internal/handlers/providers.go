@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	
+	"github.com/user/llm-knowledge-extractor/internal/llm"
+)
+
+// backend is a provider a request can be analyzed with, under the name and
+// model recorded in the metadata of its results. slots, when non-nil, bounds
+// the provider's calls in flight.
+type backend struct {
+	name     string
+	model    string
+	provider llm.Provider
+	slots    chan struct{}
+}
+
+// AddProvider registers an alternative provider that /analyze requests can
+// pick by name with the provider field; the provider passed to New stays the
+// default, under Config.LLMProvider. Names are case-insensitive, and
+// Config.ProviderConcurrency caps each by the same name. Register
+// providers before serving requests; the set is not guarded for concurrent
+// changes.
+func (h *Handler) AddProvider(name, model string, provider llm.Provider) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	h.backends[name] = backend{name: name, model: model, provider: provider, slots: providerSlots(h.config, name)}
+}
+
+func (h *Handler) defaultBackend() backend {
+	return backend{name: h.config.LLMProvider, model: h.config.LLMModel, provider: h.llmProvider, slots: h.defaultSlots}
+}
+
+// providerSlots makes the semaphore for the concurrency cap of the named
+// provider, or returns nil when it has none.
+func providerSlots(config Config, name string) chan struct{} {
+	limit := config.ProviderConcurrency[strings.ToLower(strings.TrimSpace(name))]
+	if limit <= 0 {
+		return nil
+	}
+	return make(chan struct{}, limit)
+}
+
+// backend resolves the provider a request asked for. An empty name, or the
+// name of the default provider, selects the default.
+func (h *Handler) backend(name string) (backend, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || strings.EqualFold(name, h.config.LLMProvider) {
+		return h.defaultBackend(), nil
+	}
+	if b, ok := h.backends[name]; ok {
+		return b, nil
+	}
+	return backend{}, fmt.Errorf("unknown provider %q, expected one of: %s", name, strings.Join(h.providerNames(), ", "))
+}
+
+// providerNames lists the default provider and every registered one, sorted.
+func (h *Handler) providerNames() []string {
+	names := make([]string, 0, len(h.backends)+1)
+	if h.config.LLMProvider != "" {
+		names = append(names, h.config.LLMProvider)
+	}
+	for name := range h.backends {
+		if !strings.EqualFold(name, h.config.LLMProvider) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
\ No newline at end of file
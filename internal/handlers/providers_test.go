@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/llm"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_AnalyzeText_Provider(t *testing.T) {
+	fast := newStubProvider()
+	accurate := &stubProvider{result: &llm.AnalysisResult{
+		Summary:   "A careful summary of the text.",
+		Title:     "Accurate Title",
+		Topics:    []string{"precision"},
+		Sentiment: "positive",
+	}}
+	h, db := newTestHandlerWithConfig(t, fast, Config{
+		LLMProvider:    "mock",
+		ResultCacheTTL: time.Minute,
+	})
+	h.AddProvider("Gemini", "gemini-1.5-pro", accurate)
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	
+	analyze := func(provider string) models.AnalyzeResponse {
+		w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "Tiny text.", Provider: provider})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp models.AnalyzeResponse
+		decodeData(t, w, &resp)
+		return resp
+	}
+	
+	resp := analyze("")
+	assert.Equal(t, "A short summary of the text.", resp.Summary)
+	assert.Equal(t, "mock", resp.Metadata["provider"])
+	assert.NotContains(t, resp.Metadata, "model")
+	
+	// The same text through another provider must not be served from the
+	// default provider's cache entry.
+	resp = analyze("gemini")
+	assert.Equal(t, "A careful summary of the text.", resp.Summary)
+	assert.Equal(t, "Accurate Title", resp.Metadata["title"])
+	assert.Equal(t, "gemini", resp.Metadata["provider"])
+	assert.Equal(t, "gemini-1.5-pro", resp.Metadata["model"])
+	
+	stored, err := db.GetAnalysis(resp.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored)
+	assert.Equal(t, "gemini", stored.Metadata["provider"])
+	
+	resp = analyze("MOCK")
+	assert.Equal(t, "A short summary of the text.", resp.Summary)
+	assert.Equal(t, "mock", resp.Metadata["provider"])
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "Tiny text.", Provider: "openai"})
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	apiErr := decodeError(t, w)
+	assert.Equal(t, models.CodeInvalidRequest, apiErr.Code)
+	assert.Contains(t, apiErr.Message+apiErr.Details, "gemini, mock")
+}
+
+// gatedProvider holds every call until release is closed, tracking how many
+// are in flight.
+type gatedProvider struct {
+	mu      sync.Mutex
+	current int
+	max     int
+	release chan struct{}
+}
+
+func (p *gatedProvider) Analyze(ctx context.Context, text string) (*llm.AnalysisResult, error) {
+	p.mu.Lock()
+	p.current++
+	if p.current > p.max {
+		p.max = p.current
+	}
+	p.mu.Unlock()
+	
+	select {
+	case <-p.release:
+	case <-ctx.Done():
+	}
+	
+	p.mu.Lock()
+	p.current--
+	p.mu.Unlock()
+	
+	return newStubProvider().result, ctx.Err()
+}
+
+func (p *gatedProvider) IsAvailable() bool {
+	return true
+}
+
+func (p *gatedProvider) inFlight() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current
+}
+
+func TestHandler_AnalyzeText_ProviderConcurrency(t *testing.T) {
+	slow := &gatedProvider{release: make(chan struct{})}
+	h, _ := newTestHandlerWithConfig(t, newStubProvider(), Config{
+		WorkerPoolSize:      5,
+		LLMProvider:         "mock",
+		ProviderConcurrency: map[string]int{"Slow": 2},
+	})
+	h.AddProvider("slow", "slow-1", slow)
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	
+	const requests = 10
+	codes := make(chan int, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: fmt.Sprintf("Slow text %d.", i), Provider: "slow"})
+			codes <- w.Code
+		}(i)
+	}
+	
+	require.Eventually(t, func() bool { return slow.inFlight() == 2 }, 5*time.Second, 5*time.Millisecond)
+	
+	// The queued slow requests must not hold the pool slots the default
+	// provider needs.
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "Tiny text."})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 2, slow.inFlight())
+	
+	close(slow.release)
+	wg.Wait()
+	close(codes)
+	
+	for code := range codes {
+		assert.Equal(t, http.StatusOK, code)
+	}
+	assert.Equal(t, 2, slow.max)
+}
\ No newline at end of file
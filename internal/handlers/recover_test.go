@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/analyzer"
+	"github.com/user/llm-knowledge-extractor/internal/llm"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+type panicStrategy struct{}
+
+func (panicStrategy) ExtractKeywords(text string, topN int) []string {
+	panic("keyword strategy exploded")
+}
+
+func (panicStrategy) ExtractKeywordsWithMinLength(text string, topN, minLength int) []string {
+	panic("keyword strategy exploded")
+}
+
+// panicProvider panics when asked to analyze the given text and otherwise
+// behaves like the stub provider.
+type panicProvider struct {
+	stubProvider
+	panicOn string
+}
+
+func (p *panicProvider) Analyze(ctx context.Context, text string) (*llm.AnalysisResult, error) {
+	if text == p.panicOn {
+		panic("provider exploded on " + text)
+	}
+	return p.stubProvider.Analyze(ctx, text)
+}
+
+func TestHandler_KeywordPanicDegrades(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	h.keywordExtractors = map[string]keywordStrategy{analyzer.DefaultLanguage: panicStrategy{}}
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "The server failed and the cluster recovered."})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var resp models.AnalyzeResponse
+	decodeData(t, w, &resp)
+	assert.Empty(t, resp.Metadata["keywords"])
+	assert.Equal(t, analyzer.DefaultLanguage, resp.Metadata["language"])
+	assert.Equal(t, "A short summary of the text.", resp.Summary)
+	
+	stored, err := db.GetAnalysis(resp.ID)
+	require.NoError(t, err)
+	assert.Empty(t, stored.MetadataStrings("keywords"))
+	
+	w = postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: []string{"first text", "second text"}})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var batch models.BatchAnalyzeResponse
+	decodeData(t, w, &batch)
+	assert.Equal(t, 2, batch.SuccessCount)
+}
+
+func TestHandler_BatchWorkerPanicIsolated(t *testing.T) {
+	provider := &panicProvider{stubProvider: *newStubProvider(), panicOn: "bad text"}
+	h, _ := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	r.POST("/analyze/chunked", h.AnalyzeChunked)
+	
+	w := postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: []string{"good text", "bad text", "more good text"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	var batch models.BatchAnalyzeResponse
+	decodeData(t, w, &batch)
+	assert.Equal(t, 2, batch.SuccessCount)
+	require.Len(t, batch.Failed, 1)
+	assert.Equal(t, 1, batch.Failed[0].Index)
+	assert.Contains(t, batch.Failed[0].Error, "internal error")
+	
+	w = postJSON(r, "/analyze/chunked", models.ChunkedAnalyzeRequest{Chunks: []string{"good text", "bad text"}})
+	assert.NotEqual(t, http.StatusOK, w.Code)
+	assert.Contains(t, decodeError(t, w).Details, "chunk 1")
+}
\ No newline at end of file
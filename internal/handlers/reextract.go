@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+// ReextractKeywords recomputes the keywords of a stored analysis from its
+// original text. The LLM is not called, so summary and topics are unchanged.
+func (h *Handler) ReextractKeywords(c *gin.Context) {
+	analysis, ok := h.loadAnalysisPrimary(c)
+	if !ok {
+		return
+	}
+	
+	keywords, err := h.reextractKeywords(c.Request.Context(), analysis)
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	response.JSON(c, http.StatusOK, gin.H{
+		"id":       analysis.ID,
+		"keywords": keywords,
+	})
+}
+
+// BulkReextractKeywords re-extracts keywords for every analysis matching the
+// query filter. Like bulk delete it refuses to run without a filter unless
+// all=true is passed.
+func (h *Handler) BulkReextractKeywords(c *gin.Context) {
+	var query models.ReextractQuery
+	
+	if err := c.ShouldBindQuery(&query); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	if !h.prepareFilter(c, &query.SearchFilter) {
+		return
+	}
+	
+	if query.SearchFilter.IsEmpty() && !query.All {
+		response.RespondError(c, http.StatusBadRequest, models.CodeFilterRequired, errors.New("at least one filter is required, or pass all=true to re-extract everything"))
+		return
+	}
+	
+	ctx := c.Request.Context()
+	
+	// Load the matches before updating so no read cursor is held open while
+	// writing; an in-memory database only has a single connection. They come
+	// from the primary, since the updates write back whole metadata.
+	analyses, err := h.store(ctx).SearchAnalysesPrimaryContext(ctx, models.SearchQuery{SearchFilter: query.SearchFilter})
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	var updated int
+	for _, analysis := range analyses {
+		if _, err := h.reextractKeywords(ctx, analysis); err != nil {
+			response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+			return
+		}
+		updated++
+	}
+	
+	response.JSON(c, http.StatusOK, models.ReextractResponse{
+		Updated: updated,
+	})
+}
+
+func (h *Handler) reextractKeywords(ctx context.Context, analysis *models.TextAnalysis) ([]string, error) {
+	language, keywords := h.extractKeywords(analysis.Text, analysis.MetadataStrings("topics"), 0)
+	
+	if analysis.Metadata == nil {
+		analysis.Metadata = map[string]interface{}{}
+	}
+	analysis.Metadata["keywords"] = keywords
+	analysis.Metadata["language"] = language
+	
+	if err := h.store(ctx).UpdateAnalysisContext(ctx, analysis); err != nil {
+		return nil, err
+	}
+	
+	return keywords, nil
+}
\ No newline at end of file
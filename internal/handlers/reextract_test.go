@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/database"
+	"github.com/user/llm-knowledge-extractor/internal/llm"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func seedStaleKeywords(t *testing.T, db *database.DB, id, topic string) {
+	t.Helper()
+	
+	err := db.SaveAnalysis(&models.TextAnalysis{
+		ID:      id,
+		Text:    "The server crashed. The server restarted and the database recovered the server state.",
+		Summary: "Summary for " + id,
+		Metadata: map[string]interface{}{
+			"topics":   []string{topic},
+			"keywords": []string{"stale"},
+		},
+		Confidence: 0.8,
+		CreatedAt:  time.Now(),
+	})
+	require.NoError(t, err)
+}
+
+func TestHandler_ReextractKeywords(t *testing.T) {
+	// Re-extraction never reaches the LLM, so an unavailable provider is fine.
+	provider := newStubProvider()
+	provider.err = llm.ErrLLMUnavailable
+	h, db := newTestHandler(t, provider)
+	seedStaleKeywords(t, db, "a1", "infrastructure")
+	
+	r := gin.New()
+	r.POST("/analyses/:id/reextract-keywords", h.ReextractKeywords)
+	
+	w := postJSON(r, "/analyses/a1/reextract-keywords", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var resp struct {
+		ID       string   `json:"id"`
+		Keywords []string `json:"keywords"`
+	}
+	decodeData(t, w, &resp)
+	assert.Equal(t, "a1", resp.ID)
+	assert.Contains(t, resp.Keywords, "server")
+	
+	stored, err := db.GetAnalysis("a1")
+	require.NoError(t, err)
+	assert.Equal(t, resp.Keywords, stored.MetadataStrings("keywords"))
+	assert.Equal(t, "Summary for a1", stored.Summary)
+	assert.Equal(t, []string{"infrastructure"}, stored.MetadataStrings("topics"))
+	
+	history, err := db.GetAnalysisHistory("a1")
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	
+	w = postJSON(r, "/analyses/missing/reextract-keywords", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandler_BulkReextractKeywords(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	seedStaleKeywords(t, db, "a1", "infrastructure")
+	seedStaleKeywords(t, db, "a2", "infrastructure")
+	seedStaleKeywords(t, db, "a3", "databases")
+	
+	r := gin.New()
+	r.POST("/analyses/reextract-keywords", h.BulkReextractKeywords)
+	
+	w := postJSON(r, "/analyses/reextract-keywords", nil)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	
+	w = postJSON(r, "/analyses/reextract-keywords?topic=Infrastructure", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var resp models.ReextractResponse
+	decodeData(t, w, &resp)
+	assert.Equal(t, 2, resp.Updated)
+	
+	for _, id := range []string{"a1", "a2"} {
+		stored, err := db.GetAnalysis(id)
+		require.NoError(t, err)
+		assert.Contains(t, stored.MetadataStrings("keywords"), "server", id)
+	}
+	
+	untouched, err := db.GetAnalysis("a3")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stale"}, untouched.MetadataStrings("keywords"))
+}
+
+func TestHandler_ReadModifyWriteUsesPrimary(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	
+	// The replica holds an old copy of a1 and has not seen a2 at all.
+	replicaPath := filepath.Join(dir, "replica.db")
+	replica, err := database.New(replicaPath)
+	require.NoError(t, err)
+	seedStaleKeywords(t, replica, "a1", "infrastructure")
+	require.NoError(t, replica.Close())
+	
+	opts := database.DefaultOptions()
+	opts.ReadPath = replicaPath
+	db, err := database.NewWithOptions(filepath.Join(dir, "primary.db"), opts)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	seedStaleKeywords(t, db, "a1", "infrastructure")
+	seedStaleKeywords(t, db, "a2", "infrastructure")
+	h := New(db, newStubProvider(), Config{})
+	
+	r := gin.New()
+	r.POST("/analyses/:id/correct-sentiment", h.CorrectSentiment)
+	r.POST("/analyses/:id/reextract-keywords", h.ReextractKeywords)
+	r.POST("/analyses/reextract-keywords", h.BulkReextractKeywords)
+	r.POST("/analyses/:id/notes", h.AddNote)
+	
+	w := postJSON(r, "/analyses/a1/reextract-keywords", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	w = postJSON(r, "/analyses/a1/correct-sentiment", models.SentimentCorrectionRequest{Sentiment: "negative", CorrectedBy: "alice"})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	stored, err := db.GetAnalysisPrimary("a1")
+	require.NoError(t, err)
+	assert.Equal(t, "negative", stored.Metadata["sentiment"])
+	assert.NotEqual(t, []string{"stale"}, stored.MetadataStrings("keywords"), "the correction kept the re-extracted keywords")
+	
+	w = postJSON(r, "/analyses/a2/correct-sentiment", models.SentimentCorrectionRequest{Sentiment: "negative", CorrectedBy: "alice"})
+	assert.Equal(t, http.StatusOK, w.Code, "analyses missing from the replica are still found")
+	w = postJSON(r, "/analyses/a2/notes", models.NoteRequest{Note: "checked"})
+	assert.Equal(t, http.StatusCreated, w.Code)
+	
+	w = postJSON(r, "/analyses/reextract-keywords?all=true", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp models.ReextractResponse
+	decodeData(t, w, &resp)
+	assert.Equal(t, 2, resp.Updated)
+	
+	stored, err = db.GetAnalysisPrimary("a1")
+	require.NoError(t, err)
+	assert.Equal(t, "negative", stored.Metadata["sentiment"], "bulk re-extraction kept the correction")
+}
\ No newline at end of file
@@ -0,0 +1,128 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	
+	"github.com/user/llm-knowledge-extractor/internal/llm"
+)
+
+const maxCachedResults = 1000
+
+// cachedResult is what an analysis computes from its text: the provider's
+// answer and the keywords and language extracted locally.
+type cachedResult struct {
+	llm      llm.AnalysisResult
+	language string
+	keywords []string
+	expires  time.Time
+}
+
+// resultCache remembers analysis results for ttl so repeating a text skips
+// the provider. Entries are keyed by the text and every request option that
+// changes the result, so requests that differ only in, say, include or
+// keyword_min_length never share one. A nil cache caches nothing.
+type resultCache struct {
+	ttl time.Duration
+	now func() time.Time
+	
+	mu      sync.Mutex
+	entries map[string]cachedResult
+}
+
+func newResultCache(ttl time.Duration) *resultCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &resultCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]cachedResult),
+	}
+}
+
+// resultCacheKey hashes the text with the provider and options that shape its
+// result. Tags are left out: they are stored as given and don't change the
+// analysis.
+func resultCacheKey(text, provider string, components componentSet, keywordMinLength int) string {
+	included := "all"
+	if components != nil {
+		names := make([]string, 0, len(components))
+		for name := range components {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		included = strings.Join(names, ",")
+	}
+	
+	sum := sha256.Sum256([]byte(provider + "\x00" + included + "\x00" + strconv.Itoa(keywordMinLength) + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// get returns a copy of the entry for key, so callers can modify it freely.
+func (c *resultCache) get(key string) (cachedResult, bool) {
+	if c == nil {
+		return cachedResult{}, false
+	}
+	
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	entry, ok := c.entries[key]
+	if !ok {
+		return cachedResult{}, false
+	}
+	if !c.now().Before(entry.expires) {
+		delete(c.entries, key)
+		return cachedResult{}, false
+	}
+	return entry.clone(), true
+}
+
+func (c *resultCache) put(key string, entry cachedResult) {
+	if c == nil {
+		return
+	}
+	
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	
+	now := c.now()
+	if len(c.entries) >= maxCachedResults {
+		c.evict(now)
+	}
+	entry = entry.clone()
+	entry.expires = now.Add(c.ttl)
+	c.entries[key] = entry
+}
+
+// evict drops expired entries, or the one closest to expiring when none
+// have. The caller holds mu.
+func (c *resultCache) evict(now time.Time) {
+	var soonest string
+	for key, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, key)
+			continue
+		}
+		if soonest == "" || entry.expires.Before(c.entries[soonest].expires) {
+			soonest = key
+		}
+	}
+	if len(c.entries) >= maxCachedResults {
+		delete(c.entries, soonest)
+	}
+}
+
+// clone copies the slices, keeping empty ones non-nil so they still encode
+// as [].
+func (r cachedResult) clone() cachedResult {
+	r.llm.Topics = append(r.llm.Topics[:0:0], r.llm.Topics...)
+	r.keywords = append(r.keywords[:0:0], r.keywords...)
+	return r
+}
\ No newline at end of file
@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"net/http"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_AnalyzeText_ResultCache(t *testing.T) {
+	const text = "Caching layers speed up repeated database queries considerably."
+	provider := newFlakyProvider(nil)
+	h, _ := newTestHandlerWithConfig(t, provider, Config{ResultCacheTTL: time.Minute})
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.POST("/batch-analyze", h.BatchAnalyzeText)
+	
+	analyze := func(req models.AnalyzeRequest) models.AnalyzeResponse {
+		t.Helper()
+		w := postJSON(r, "/analyze", req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var resp models.AnalyzeResponse
+		decodeData(t, w, &resp)
+		return resp
+	}
+	
+	first := analyze(models.AnalyzeRequest{Text: text})
+	assert.Equal(t, 1, provider.calls[text])
+	
+	repeat := analyze(models.AnalyzeRequest{Text: text, Tags: []string{"cached"}})
+	assert.Equal(t, 1, provider.calls[text], "same options are served from the cache")
+	assert.NotEqual(t, first.ID, repeat.ID)
+	assert.Equal(t, first.Metadata["keywords"], repeat.Metadata["keywords"])
+	assert.Equal(t, []interface{}{"cached"}, repeat.Metadata["tags"])
+	
+	short := analyze(models.AnalyzeRequest{Text: text, KeywordMinLength: 8})
+	assert.Equal(t, 2, provider.calls[text], "a different keyword_min_length is analyzed separately")
+	assert.NotEqual(t, first.Metadata["keywords"], short.Metadata["keywords"])
+	
+	analyze(models.AnalyzeRequest{Text: text, Include: []string{"summary", "topics"}})
+	assert.Equal(t, 3, provider.calls[text], "a different include is analyzed separately")
+	
+	again := analyze(models.AnalyzeRequest{Text: text, KeywordMinLength: 8})
+	assert.Equal(t, 3, provider.calls[text])
+	assert.Equal(t, short.Metadata["keywords"], again.Metadata["keywords"])
+	analyze(models.AnalyzeRequest{Text: text, Include: []string{"topics", "summary"}})
+	assert.Equal(t, 3, provider.calls[text], "include order doesn't matter")
+	
+	w := postJSON(r, "/batch-analyze", models.BatchAnalyzeRequest{Texts: []string{text}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, 3, provider.calls[text], "batch items share entries with option-free requests")
+}
+
+func TestHandler_AnalyzeText_ResultCacheDisabled(t *testing.T) {
+	const text = "Caching layers speed up repeated database queries considerably."
+	provider := newFlakyProvider(nil)
+	h, _ := newTestHandler(t, provider)
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	
+	for i := 0; i < 2; i++ {
+		w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: text})
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	}
+	assert.Equal(t, 2, provider.calls[text])
+}
+
+func TestResultCache_Expiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := newResultCache(time.Minute)
+	cache.now = func() time.Time { return now }
+	
+	key := resultCacheKey("text", "mock", nil, 0)
+	cache.put(key, cachedResult{keywords: []string{"text"}})
+	
+	entry, ok := cache.get(key)
+	require.True(t, ok)
+	entry.keywords[0] = "changed"
+	entry, _ = cache.get(key)
+	assert.Equal(t, []string{"text"}, entry.keywords, "callers get a copy")
+	
+	now = now.Add(time.Minute)
+	_, ok = cache.get(key)
+	assert.False(t, ok)
+	
+	assert.NotEqual(t, key, resultCacheKey("text", "mock", requestedComponents([]string{"keywords"}), 0))
+	assert.NotEqual(t, key, resultCacheKey("text", "mock", nil, 5))
+	assert.NotEqual(t, key, resultCacheKey("text", "gemini", nil, 0))
+}
\ No newline at end of file
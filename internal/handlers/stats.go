@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/database"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+// Stats reports totals and averages over all stored analyses. The database
+// caches the result briefly, so dashboards can poll it.
+func (h *Handler) Stats(c *gin.Context) {
+	stats, err := h.store(c.Request.Context()).GetStatsContext(c.Request.Context())
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	response.JSON(c, http.StatusOK, stats)
+}
+
+// TopicTimeline counts the analyses listing :topic per day, week or month
+// (?interval=, default day). Only buckets with analyses are returned.
+func (h *Handler) TopicTimeline(c *gin.Context) {
+	topic := h.topicNormalizer.Normalize(c.Param("topic"))
+	if topic == "" {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, errors.New("topic is required"))
+		return
+	}
+	interval := c.DefaultQuery("interval", "day")
+	
+	buckets, err := h.store(c.Request.Context()).GetTopicTimelineContext(c.Request.Context(), topic, interval)
+	if errors.Is(err, database.ErrInvalidInterval) {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	total := 0
+	for _, bucket := range buckets {
+		total += bucket.Count
+	}
+	
+	response.List(c, http.StatusOK, "buckets", buckets, gin.H{
+		"topic":    topic,
+		"interval": interval,
+		"total":    total,
+	})
+}
\ No newline at end of file
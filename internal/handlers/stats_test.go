@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_Stats(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.GET("/stats", h.Stats)
+	
+	getStats := func() map[string]interface{} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stats", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		
+		var stats map[string]interface{}
+		decodeData(t, w, &stats)
+		return stats
+	}
+	
+	assert.Equal(t, float64(0), getStats()["total_analyses"])
+	
+	seedAnalysis(t, db, "a1", []string{"golang"})
+	seedAnalysis(t, db, "a2", []string{"sqlite"})
+	stats := getStats()
+	assert.Equal(t, float64(2), stats["total_analyses"])
+	assert.Contains(t, stats, "last_analysis")
+}
+
+func TestHandler_TopicTimeline(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	for i, createdAt := range []time.Time{
+		time.Date(2024, time.May, 1, 9, 0, 0, 0, time.UTC),
+		time.Date(2024, time.May, 1, 18, 0, 0, 0, time.UTC),
+		time.Date(2024, time.May, 3, 9, 0, 0, 0, time.UTC),
+	} {
+		require.NoError(t, db.SaveAnalysis(&models.TextAnalysis{
+			ID:        fmt.Sprintf("a%d", i),
+			Text:      "Text",
+			Metadata:  map[string]interface{}{"topics": []string{"machine learning"}},
+			CreatedAt: createdAt,
+		}))
+	}
+	
+	r := gin.New()
+	r.GET("/stats/topics/:topic/timeline", h.TopicTimeline)
+	
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	
+	tests := []struct {
+		path     string
+		interval string
+		expected []models.TimelineBucket
+	}{
+		{"/stats/topics/machine-learning/timeline", "day", []models.TimelineBucket{{Start: "2024-05-01", Count: 2}, {Start: "2024-05-03", Count: 1}}},
+		{"/stats/topics/Machine%20Learning/timeline?interval=week", "week", []models.TimelineBucket{{Start: "2024-04-29", Count: 3}}},
+		{"/stats/topics/machine_learning/timeline?interval=month", "month", []models.TimelineBucket{{Start: "2024-05-01", Count: 3}}},
+		{"/stats/topics/robotics/timeline", "day", []models.TimelineBucket{}},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := get(tt.path)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			
+			var buckets []models.TimelineBucket
+			decodeData(t, w, &buckets)
+			assert.Equal(t, tt.expected, buckets)
+			
+			var meta struct {
+				Interval string `json:"interval"`
+				Total    int    `json:"total"`
+			}
+			decodeMeta(t, w, &meta)
+			assert.Equal(t, tt.interval, meta.Interval)
+			total := 0
+			for _, bucket := range tt.expected {
+				total += bucket.Count
+			}
+			assert.Equal(t, total, meta.Total)
+		})
+	}
+	
+	w := get("/stats/topics/machine-learning/timeline?interval=hour")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, models.CodeInvalidRequest, decodeError(t, w).Code)
+}
\ No newline at end of file
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/analyzer"
+	"github.com/user/llm-knowledge-extractor/internal/llm"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+// Summarize returns only the provider's summary and title. Keyword
+// extraction, confidence scoring and persistence are skipped.
+func (h *Handler) Summarize(c *gin.Context) {
+	var req models.SummarizeRequest
+	
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	req.Text = analyzer.NormalizeText(req.Text)
+	if h.rejectText(c, "text", req.Text) {
+		return
+	}
+	
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 45*time.Second)
+	defer cancel()
+	
+	result, err := h.analyzeWithPool(ctx, req.Text)
+	if err != nil {
+		if errors.Is(err, llm.ErrEmptyInput) {
+			response.RespondError(c, http.StatusBadRequest, models.CodeEmptyInput, nil)
+			return
+		}
+		
+		h.respondLLMError(c, err)
+		return
+	}
+	
+	response.JSON(c, http.StatusOK, models.SummarizeResponse{
+		Summary: result.Summary,
+		Title:   result.Title,
+	})
+}
\ No newline at end of file
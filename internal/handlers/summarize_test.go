@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/llm"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_Summarize(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/summarize", h.Summarize)
+	
+	w := postJSON(r, "/summarize", models.SummarizeRequest{Text: "A long article about testing software."})
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var data map[string]json.RawMessage
+	decodeData(t, w, &data)
+	assert.Len(t, data, 2)
+	assert.JSONEq(t, `"A short summary of the text."`, string(data["summary"]))
+	assert.JSONEq(t, `"Test Title"`, string(data["title"]))
+	
+	stored, err := db.GetRecentAnalyses(10)
+	require.NoError(t, err)
+	assert.Empty(t, stored)
+}
+
+func TestHandler_Summarize_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		text        string
+		providerErr error
+		status      int
+		code        models.ErrorCode
+	}{
+		{"empty text", "  ", nil, http.StatusBadRequest, models.CodeEmptyInput},
+		{"provider rejects input", "Some text.", llm.ErrEmptyInput, http.StatusBadRequest, models.CodeEmptyInput},
+		{"provider unavailable", "Some text.", llm.ErrLLMUnavailable, http.StatusServiceUnavailable, models.CodeLLMUnavailable},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newStubProvider()
+			provider.err = tt.providerErr
+			h, _ := newTestHandler(t, provider)
+			
+			r := gin.New()
+			r.POST("/summarize", h.Summarize)
+			
+			w := postJSON(r, "/summarize", models.SummarizeRequest{Text: tt.text})
+			require.Equal(t, tt.status, w.Code)
+			assert.Equal(t, tt.code, decodeError(t, w).Code)
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/database"
+	"github.com/user/llm-knowledge-extractor/internal/middleware"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_TenantIsolation(t *testing.T) {
+	h, db := newTestHandler(t, newStubProvider())
+	tenants, err := database.NewTenants(t.TempDir(), database.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { tenants.Close() })
+	for _, id := range []string{"acme", "globex"} {
+		_, err := tenants.Provision(id)
+		require.NoError(t, err)
+	}
+	
+	r := gin.New()
+	r.Use(middleware.Tenant(tenants))
+	r.POST("/analyze", h.AnalyzeText)
+	r.GET("/search", h.SearchAnalyses)
+	r.GET("/analyses/:id/typed", h.GetAnalysisTyped)
+	
+	do := func(method, path, tenant string, payload interface{}) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		if payload != nil {
+			require.NoError(t, json.NewEncoder(&body).Encode(payload))
+		}
+		req := httptest.NewRequest(method, path, &body)
+		req.Header.Set(middleware.TenantHeader, tenant)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+	
+	w := do(http.MethodPost, "/analyze", "acme", models.AnalyzeRequest{Text: "Acme launches a new rocket engine."})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var created models.AnalyzeResponse
+	decodeData(t, w, &created)
+	
+	w = do(http.MethodGet, "/analyses/"+created.ID+"/typed", "acme", nil)
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	
+	w = do(http.MethodGet, "/analyses/"+created.ID+"/typed", "globex", nil)
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+	
+	var found []models.TextAnalysis
+	w = do(http.MethodGet, "/search", "acme", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	decodeData(t, w, &found)
+	assert.Len(t, found, 1)
+	
+	w = do(http.MethodGet, "/search", "globex", nil)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	decodeData(t, w, &found)
+	assert.Empty(t, found)
+	
+	// The handler's own database is only the fallback for untenanted
+	// requests and never sees tenant writes.
+	stored, err := db.GetAnalysis(created.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+}
\ No newline at end of file
@@ -0,0 +1,30 @@
+package handlers
+
+import (
+	"net/http"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+// GetAnalysisTyped returns a stored analysis with typed metadata, for clients
+// that want a fixed shape instead of the free-form map.
+func (h *Handler) GetAnalysisTyped(c *gin.Context) {
+	analysis, err := h.store(c.Request.Context()).GetAnalysisTypedContext(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		response.RespondError(c, http.StatusInternalServerError, models.CodeDBError, err)
+		return
+	}
+	
+	if analysis == nil {
+		response.RespondError(c, http.StatusNotFound, models.CodeNotFound, nil)
+		return
+	}
+	
+	if !includeText(c, true) {
+		analysis.Text = ""
+	}
+	
+	response.JSON(c, http.StatusOK, analysis)
+}
\ No newline at end of file
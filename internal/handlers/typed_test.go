@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_GetAnalysisTyped(t *testing.T) {
+	h, db := newTestHandlerWithConfig(t, newStubProvider(), Config{LLMProvider: "mock"})
+	
+	r := gin.New()
+	r.POST("/analyze", h.AnalyzeText)
+	r.GET("/analyses/:id/typed", h.GetAnalysisTyped)
+	
+	get := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+	
+	w := postJSON(r, "/analyze", models.AnalyzeRequest{Text: "Database migrations need careful planning.", Tags: []string{"Ops"}})
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var created models.AnalyzeResponse
+	decodeData(t, w, &created)
+	stored, err := db.GetAnalysis(created.ID)
+	require.NoError(t, err)
+	
+	w = get("/analyses/" + created.ID + "/typed")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var typed models.TypedAnalysis
+	decodeData(t, w, &typed)
+	assert.Equal(t, created.ID, typed.ID)
+	assert.Equal(t, "Database migrations need careful planning.", typed.Text)
+	assert.Equal(t, stored.Metadata["title"], typed.Metadata.Title)
+	assert.Equal(t, stored.MetadataStrings("topics"), typed.Metadata.Topics)
+	assert.Equal(t, stored.MetadataStrings("keywords"), typed.Metadata.Keywords)
+	assert.Equal(t, stored.Metadata["sentiment"], typed.Metadata.Sentiment)
+	assert.Equal(t, []string{"ops"}, typed.Metadata.Tags)
+	assert.Equal(t, "mock", typed.Metadata.Provider)
+	assert.Equal(t, "en", typed.Metadata.Language)
+	
+	w = get("/analyses/" + created.ID + "/typed?include_text=false")
+	require.Equal(t, http.StatusOK, w.Code)
+	var withoutText models.TypedAnalysis
+	decodeData(t, w, &withoutText)
+	assert.Empty(t, withoutText.Text)
+	
+	w = get("/analyses/missing/typed")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, models.CodeNotFound, decodeError(t, w).Code)
+}
\ No newline at end of file
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/analyzer"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+// ValidateText runs the input checks an analysis starts with (encoding,
+// emptiness after normalization, length) and detects the language, so
+// clients can vet a text before paying for the LLM call. Nothing is
+// analyzed or stored. Problems with the text are listed in the report
+// rather than returned as errors; only a malformed body is a 400.
+func (h *Handler) ValidateText(c *gin.Context) {
+	body, err := c.GetRawData()
+	if err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, fmt.Errorf("failed to read request body: %w", err))
+		return
+	}
+	
+	report := models.ValidationReport{
+		MaxCharacters: h.config.MaxTextLength,
+		Issues:        make([]models.ValidationIssue, 0),
+	}
+	
+	cleaned, offset := h.validateEncoding(body)
+	if offset >= 0 {
+		report.Issues = append(report.Issues, models.ValidationIssue{
+			Code:    models.CodeInvalidEncoding,
+			Details: fmt.Sprintf("invalid UTF-8 at byte %d of the request body", offset),
+		})
+	} else if len(cleaned) != len(body) {
+		report.Sanitized = true
+	}
+	
+	// Decoding replaces invalid bytes with U+FFFD, so the remaining checks
+	// still run on a body that failed the encoding check.
+	var req models.ValidateRequest
+	if err := json.Unmarshal(cleaned, &req); err != nil {
+		response.RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+		return
+	}
+	
+	text := analyzer.NormalizeText(req.Text)
+	report.Characters = utf8.RuneCountInString(text)
+	
+	if code, reason := h.checkText(text); code != "" {
+		report.Issues = append(report.Issues, models.ValidationIssue{
+			Code:    code,
+			Details: "text " + reason,
+		})
+	}
+	
+	if text != "" {
+		report.Language, _ = h.keywordExtractor(text)
+	}
+	report.Valid = len(report.Issues) == 0
+	
+	response.JSON(c, http.StatusOK, report)
+}
+
+// checkText runs the checks every analysis makes on a normalized text: it
+// must not be empty and may have at most MaxTextLength characters. It
+// returns the code the text is rejected with and why, or "" when it passes.
+// The analyze endpoints reject with it and /validate reports it, so the two
+// cannot disagree.
+func (h *Handler) checkText(text string) (models.ErrorCode, string) {
+	switch {
+	case text == "":
+		return models.CodeEmptyInput, "is empty after normalization"
+	case utf8.RuneCountInString(text) > h.config.MaxTextLength:
+		return models.CodeTextTooLong, fmt.Sprintf("exceeds %d characters", h.config.MaxTextLength)
+	}
+	return "", ""
+}
+
+// rejectText responds with 400 when text fails checkText and reports whether
+// it did. subject names the text in the details, e.g. "text at index 2".
+func (h *Handler) rejectText(c *gin.Context, subject, text string) bool {
+	code, reason := h.checkText(text)
+	if code == "" {
+		return false
+	}
+	response.RespondError(c, http.StatusBadRequest, code, fmt.Errorf("%s %s", subject, reason))
+	return true
+}
\ No newline at end of file
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestHandler_ValidateText(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		body       string
+		valid      bool
+		codes      []models.ErrorCode
+		characters int
+		language   string
+		sanitized  bool
+	}{
+		{
+			name:       "Valid English text",
+			body:       `{"text": "The team shipped the new release and the users are happy with it."}`,
+			valid:      true,
+			characters: 65,
+			language:   "en",
+		},
+		{
+			name:       "Valid Spanish text",
+			body:       `{"text": "El equipo lanzó la nueva versión y los usuarios están contentos con el cambio."}`,
+			valid:      true,
+			characters: 78,
+			language:   "es",
+		},
+		{
+			name:  "Empty text",
+			body:  `{"text": ""}`,
+			codes: []models.ErrorCode{models.CodeEmptyInput},
+		},
+		{
+			name:  "Only invisible characters",
+			body:  "{\"text\": \"\u200b\u00a0 \"}",
+			codes: []models.ErrorCode{models.CodeEmptyInput},
+		},
+		{
+			name:       "Too long",
+			config:     Config{MaxTextLength: 10},
+			body:       `{"text": "This text is longer than ten characters."}`,
+			codes:      []models.ErrorCode{models.CodeTextTooLong},
+			characters: 40,
+			language:   "en",
+		},
+		{
+			name:       "Invalid UTF-8",
+			body:       "{\"text\": \"caf\x80 au lait\"}",
+			codes:      []models.ErrorCode{models.CodeInvalidEncoding},
+			characters: 12,
+			language:   "en",
+		},
+		{
+			name:       "Invalid UTF-8 and too long",
+			config:     Config{MaxTextLength: 5},
+			body:       "{\"text\": \"caf\x80 au lait\"}",
+			codes:      []models.ErrorCode{models.CodeInvalidEncoding, models.CodeTextTooLong},
+			characters: 12,
+			language:   "en",
+		},
+		{
+			name:       "Invalid UTF-8 sanitized",
+			config:     Config{InvalidUTF8: InvalidUTF8Sanitize},
+			body:       "{\"text\": \"caf\x80 au lait\"}",
+			valid:      true,
+			characters: 11,
+			language:   "en",
+			sanitized:  true,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := newFlakyProvider(nil)
+			h, db := newTestHandlerWithConfig(t, provider, tt.config)
+			
+			r := gin.New()
+			r.POST("/validate", h.ValidateText)
+			
+			w := postRaw(r, "/validate", tt.body)
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			
+			var report models.ValidationReport
+			decodeData(t, w, &report)
+			assert.Equal(t, tt.valid, report.Valid)
+			assert.Equal(t, tt.characters, report.Characters)
+			assert.Equal(t, tt.language, report.Language)
+			assert.Equal(t, tt.sanitized, report.Sanitized)
+			
+			codes := []models.ErrorCode{}
+			for _, issue := range report.Issues {
+				codes = append(codes, issue.Code)
+				assert.NotEmpty(t, issue.Details)
+			}
+			if tt.codes == nil {
+				tt.codes = []models.ErrorCode{}
+			}
+			assert.Equal(t, tt.codes, codes)
+			
+			assert.Empty(t, provider.calls)
+			stored, err := db.SearchAnalyses(models.SearchQuery{})
+			require.NoError(t, err)
+			assert.Empty(t, stored)
+		})
+	}
+}
+
+func TestHandler_ValidateText_MalformedBody(t *testing.T) {
+	h, _ := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.POST("/validate", h.ValidateText)
+	
+	w := postRaw(r, "/validate", `{"text": `+strings.Repeat("[", 3))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, models.CodeInvalidRequest, decodeError(t, w).Code)
+}
+
+// TestHandler_ValidateText_MatchesAnalyze checks that /validate accepts
+// exactly the texts /analyze does, and reports the code /analyze rejects with.
+func TestHandler_ValidateText_MatchesAnalyze(t *testing.T) {
+	h, _ := newTestHandlerWithConfig(t, newStubProvider(), Config{MaxTextLength: 20})
+	
+	r := gin.New()
+	r.POST("/validate", h.ValidateText)
+	r.POST("/analyze", h.AnalyzeText)
+	
+	for _, body := range []string{
+		`{"text": "Short and fine."}`,
+		`{"text": ""}`,
+		"{\"text\": \"\u200b \"}",
+		`{"text": "Twenty characters ok"}`,
+		`{"text": "This one is past twenty characters."}`,
+		"{\"text\": \"caf\x80 au lait\"}",
+	} {
+		w := postRaw(r, "/validate", body)
+		require.Equal(t, http.StatusOK, w.Code, body)
+		var report models.ValidationReport
+		decodeData(t, w, &report)
+		
+		w = postRaw(r, "/analyze", body)
+		if report.Valid {
+			assert.Equal(t, http.StatusOK, w.Code, body)
+			continue
+		}
+		require.Equal(t, http.StatusBadRequest, w.Code, body)
+		assert.Equal(t, report.Issues[0].Code, decodeError(t, w).Code, body)
+	}
+}
\ No newline at end of file
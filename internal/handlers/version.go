@@ -0,0 +1,14 @@
+package handlers
+
+import (
+	"net/http"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+	"github.com/user/llm-knowledge-extractor/internal/version"
+)
+
+// Version reports the build that is serving requests.
+func (h *Handler) Version(c *gin.Context) {
+	response.JSON(c, http.StatusOK, version.Get())
+}
\ No newline at end of file
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/version"
+)
+
+func TestHandler_Version(t *testing.T) {
+	h, _ := newTestHandler(t, newStubProvider())
+	
+	r := gin.New()
+	r.GET("/version", h.Version)
+	
+	get := func() version.Info {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		
+		var info version.Info
+		decodeData(t, w, &info)
+		return info
+	}
+	
+	assert.Equal(t, version.Info{Version: "dev", Commit: "unknown", BuildTime: "unknown"}, get())
+	
+	original := version.Get()
+	t.Cleanup(func() {
+		version.Version, version.Commit, version.BuildTime = original.Version, original.Commit, original.BuildTime
+	})
+	version.Version = "v1.4.0"
+	version.Commit = "abc1234"
+	version.BuildTime = "2024-05-01T12:00:00Z"
+	
+	assert.Equal(t, version.Info{Version: "v1.4.0", Commit: "abc1234", BuildTime: "2024-05-01T12:00:00Z"}, get())
+}
\ No newline at end of file
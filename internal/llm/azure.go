@@ -0,0 +1,119 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	DefaultAzureAPIVersion = "2024-02-01"
+	defaultMaxTokens       = 500
+	defaultTemperature     = 0.3
+)
+
+// AzureOpenAIProvider calls a chat completions deployment on Azure OpenAI.
+// Unlike OpenAI, the model is chosen by the deployment in the URL and the key
+// goes in the api-key header.
+type AzureOpenAIProvider struct {
+	url         string
+	apiKey      string
+	maxTokens   int
+	temperature float32
+	client      *http.Client
+	sentiments  SentimentSet
+	debug       *debugLogger
+}
+
+type azureMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type azureRequest struct {
+	Messages       []azureMessage    `json:"messages"`
+	MaxTokens      int               `json:"max_tokens"`
+	Temperature    float32           `json:"temperature"`
+	ResponseFormat map[string]string `json:"response_format"`
+}
+
+type azureResponse struct {
+	Choices []struct {
+		Message azureMessage `json:"message"`
+	} `json:"choices"`
+}
+
+func NewAzureOpenAIProvider(config Config) (*AzureOpenAIProvider, error) {
+	endpoint := strings.TrimRight(strings.TrimSpace(config.AzureEndpoint), "/")
+	switch {
+	case endpoint == "":
+		return nil, fmt.Errorf("azure provider requires AZURE_OPENAI_ENDPOINT")
+	case config.AzureAPIKey == "":
+		return nil, fmt.Errorf("azure provider requires AZURE_OPENAI_KEY")
+	case config.AzureDeployment == "":
+		return nil, fmt.Errorf("azure provider requires AZURE_OPENAI_DEPLOYMENT")
+	}
+	
+	apiVersion := config.AzureAPIVersion
+	if apiVersion == "" {
+		apiVersion = DefaultAzureAPIVersion
+	}
+	maxTokens := config.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultMaxTokens
+	}
+	temperature := config.Temperature
+	if temperature <= 0 {
+		temperature = defaultTemperature
+	}
+	
+	return &AzureOpenAIProvider{
+		url: fmt.Sprintf("%s/openai/deployments/%s/chat/completions?%s",
+			endpoint, url.PathEscape(config.AzureDeployment), url.Values{"api-version": {apiVersion}}.Encode()),
+		apiKey:      config.AzureAPIKey,
+		maxTokens:   maxTokens,
+		temperature: temperature,
+		client:      config.HTTPClient(),
+		sentiments:  NewSentimentSet(config.ValidSentiments),
+		debug:       newDebugLogger(config),
+	}, nil
+}
+
+func (p *AzureOpenAIProvider) Analyze(ctx context.Context, text string) (*AnalysisResult, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrEmptyInput
+	}
+	
+	system, user := analysisPrompt(text, p.sentiments)
+	data, err := postJSON(ctx, p.client, "azure", p.url, http.Header{"Api-Key": {p.apiKey}}, azureRequest{
+		Messages: []azureMessage{
+			{Role: "system", Content: system},
+			{Role: "user", Content: user},
+		},
+		MaxTokens:      p.maxTokens,
+		Temperature:    p.temperature,
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	var completion azureResponse
+	if err := json.Unmarshal(data, &completion); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("%w: azure response has no choices", ErrInvalidJSON)
+	}
+	
+	return parseLoggedResponse(p.debug, "azure", user, completion.Choices[0].Message.Content, p.sentiments)
+}
+
+// IsAvailable reports whether the provider is configured; it doesn't call
+// the API, so health checks cost nothing.
+func (p *AzureOpenAIProvider) IsAvailable() bool {
+	return p.url != "" && p.apiKey != ""
+}
\ No newline at end of file
@@ -0,0 +1,136 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func azureConfig(endpoint string) Config {
+	return Config{
+		Provider:        "azure",
+		AzureEndpoint:   endpoint,
+		AzureAPIKey:     "secret-key",
+		AzureDeployment: "gpt4o-prod",
+	}
+}
+
+func azureCompletion(content string) string {
+	data, _ := json.Marshal(map[string]interface{}{
+		"choices": []map[string]interface{}{
+			{"message": map[string]string{"role": "assistant", "content": content}},
+		},
+	})
+	return string(data)
+}
+
+func TestAzureOpenAIProvider_Analyze(t *testing.T) {
+	var got *http.Request
+	var body azureRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(azureCompletion(`{"summary": "Revenue grew.", "title": "Q3", "topics": ["finance", "growth", "revenue"], "sentiment": "positive", "sentiment_score": 0.6}`)))
+	}))
+	defer server.Close()
+	
+	provider, err := NewProvider(azureConfig(server.URL + "/"))
+	require.NoError(t, err)
+	assert.True(t, provider.IsAvailable())
+	
+	result, err := provider.Analyze(context.Background(), "Revenue grew 20% in Q3.")
+	require.NoError(t, err)
+	assert.Equal(t, "Revenue grew.", result.Summary)
+	assert.Equal(t, []string{"finance", "growth", "revenue"}, result.Topics)
+	assert.Equal(t, "positive", result.Sentiment)
+	
+	require.NotNil(t, got)
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "/openai/deployments/gpt4o-prod/chat/completions", got.URL.Path)
+	assert.Equal(t, DefaultAzureAPIVersion, got.URL.Query().Get("api-version"))
+	assert.Equal(t, "secret-key", got.Header.Get("api-key"))
+	assert.Empty(t, got.Header.Get("Authorization"))
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	
+	require.Len(t, body.Messages, 2)
+	assert.Equal(t, "system", body.Messages[0].Role)
+	assert.Contains(t, body.Messages[0].Content, `"positive", "neutral", "negative"`)
+	assert.Equal(t, azureMessage{Role: "user", Content: "Revenue grew 20% in Q3."}, body.Messages[1])
+	assert.Equal(t, "json_object", body.ResponseFormat["type"])
+}
+
+func TestAzureOpenAIProvider_APIVersion(t *testing.T) {
+	var version string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version = r.URL.Query().Get("api-version")
+		w.Write([]byte(azureCompletion(`{"summary": "s", "topics": ["a"]}`)))
+	}))
+	defer server.Close()
+	
+	config := azureConfig(server.URL)
+	config.AzureAPIVersion = "2024-06-01"
+	provider, err := NewAzureOpenAIProvider(config)
+	require.NoError(t, err)
+	
+	_, err = provider.Analyze(context.Background(), "Some text.")
+	require.NoError(t, err)
+	assert.Equal(t, "2024-06-01", version)
+}
+
+func TestAzureOpenAIProvider_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		unavailable bool
+		invalidJSON bool
+	}{
+		{name: "Rate limited", status: http.StatusTooManyRequests, body: `{"error": "slow down"}`, unavailable: true},
+		{name: "Server error", status: http.StatusBadGateway, body: `bad gateway`, unavailable: true},
+		{name: "Bad key", status: http.StatusUnauthorized, body: `{"error": "invalid key"}`},
+		{name: "No choices", status: http.StatusOK, body: `{"choices": []}`, invalidJSON: true},
+		{name: "Malformed content", status: http.StatusOK, body: azureCompletion(`not json`), invalidJSON: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			
+			provider, err := NewAzureOpenAIProvider(azureConfig(server.URL))
+			require.NoError(t, err)
+			
+			result, err := provider.Analyze(context.Background(), "Some text.")
+			require.Error(t, err)
+			assert.Nil(t, result)
+			assert.Equal(t, tt.unavailable, errors.Is(err, ErrLLMUnavailable), err)
+			assert.Equal(t, tt.invalidJSON, errors.Is(err, ErrInvalidJSON), err)
+		})
+	}
+}
+
+func TestNewAzureOpenAIProvider_RequiresSettings(t *testing.T) {
+	for _, unset := range []func(*Config){
+		func(c *Config) { c.AzureEndpoint = "" },
+		func(c *Config) { c.AzureAPIKey = "" },
+		func(c *Config) { c.AzureDeployment = "" },
+	} {
+		config := azureConfig("https://example.openai.azure.com")
+		unset(&config)
+		_, err := NewAzureOpenAIProvider(config)
+		assert.Error(t, err)
+	}
+	
+	_, err := NewAzureOpenAIProvider(Config{})
+	assert.ErrorContains(t, err, "AZURE_OPENAI_ENDPOINT")
+}
\ No newline at end of file
@@ -0,0 +1,145 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// CircuitBreakerProvider stops calling a provider that keeps failing. After
+// threshold consecutive outages (ErrLLMUnavailable or a timeout) it opens and
+// fails every call at once with ErrLLMUnavailable. Once cooldown has passed it
+// half-opens and lets a single trial call through: success closes it again,
+// another outage reopens it for a fresh cooldown.
+type CircuitBreakerProvider struct {
+	provider  Provider
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func NewCircuitBreakerProvider(provider Provider, threshold int, cooldown time.Duration) *CircuitBreakerProvider {
+	return &CircuitBreakerProvider{
+		provider:  provider,
+		threshold: threshold,
+		cooldown:  cooldown,
+		now:       time.Now,
+	}
+}
+
+func (p *CircuitBreakerProvider) Analyze(ctx context.Context, text string) (*AnalysisResult, error) {
+	if !p.allow() {
+		return nil, fmt.Errorf("%w: circuit breaker open", ErrLLMUnavailable)
+	}
+	
+	result, err := p.provider.Analyze(ctx, text)
+	p.record(err)
+	return result, err
+}
+
+// IsAvailable is false while the breaker is open and cooling down, without
+// asking the provider.
+func (p *CircuitBreakerProvider) IsAvailable() bool {
+	p.mu.Lock()
+	open := p.state == BreakerOpen && p.now().Sub(p.openedAt) < p.cooldown
+	p.mu.Unlock()
+	
+	return !open && p.provider.IsAvailable()
+}
+
+func (p *CircuitBreakerProvider) State() BreakerState {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	if p.state == BreakerOpen && p.now().Sub(p.openedAt) >= p.cooldown {
+		return BreakerHalfOpen
+	}
+	return p.state
+}
+
+// allow reports whether a call may go through, moving an open breaker whose
+// cooldown has passed to half-open and admitting one trial call.
+func (p *CircuitBreakerProvider) allow() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	if p.state == BreakerOpen && p.now().Sub(p.openedAt) >= p.cooldown {
+		p.state = BreakerHalfOpen
+	}
+	
+	switch p.state {
+	case BreakerOpen:
+		return false
+	case BreakerHalfOpen:
+		if p.probing {
+			return false
+		}
+		p.probing = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (p *CircuitBreakerProvider) record(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	
+	trial := p.state == BreakerHalfOpen
+	p.probing = false
+	
+	switch {
+	case isOutage(err):
+		p.failures++
+		if trial || p.failures >= p.threshold {
+			if p.state != BreakerOpen {
+				log.Printf("LLM circuit breaker opened after %d consecutive failures: %v", p.failures, err)
+			}
+			p.state = BreakerOpen
+			p.openedAt = p.now()
+		}
+	case errors.Is(err, context.Canceled):
+		// The caller gave up, which says nothing about the provider; a
+		// cancelled trial leaves the breaker half-open for the next call.
+	default:
+		if trial {
+			log.Printf("LLM circuit breaker closed")
+		}
+		p.state = BreakerClosed
+		p.failures = 0
+	}
+}
+
+// isOutage reports whether err means the provider is down rather than the
+// request being bad.
+func isOutage(err error) bool {
+	return errors.Is(err, ErrLLMUnavailable) || errors.Is(err, context.DeadlineExceeded)
+}
\ No newline at end of file
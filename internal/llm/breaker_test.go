@@ -0,0 +1,135 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreakerProvider_Transitions(t *testing.T) {
+	unavailable := fmt.Errorf("%w: upstream down", ErrLLMUnavailable)
+	inner := &scriptedProvider{errs: []error{unavailable, unavailable, unavailable, unavailable}}
+	breaker := NewCircuitBreakerProvider(inner, 3, time.Minute)
+	clock := time.Unix(0, 0)
+	breaker.now = func() time.Time { return clock }
+	ctx := context.Background()
+	
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, BreakerClosed, breaker.State())
+		_, err := breaker.Analyze(ctx, "text")
+		assert.ErrorIs(t, err, ErrLLMUnavailable)
+	}
+	
+	// Open: calls fail without reaching the provider.
+	assert.Equal(t, BreakerOpen, breaker.State())
+	assert.False(t, breaker.IsAvailable())
+	_, err := breaker.Analyze(ctx, "text")
+	assert.ErrorIs(t, err, ErrLLMUnavailable)
+	assert.ErrorContains(t, err, "circuit breaker open")
+	assert.Equal(t, 3, inner.calls)
+	
+	// Half-open: the trial fails and the breaker reopens for a new cooldown.
+	clock = clock.Add(time.Minute)
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	assert.True(t, breaker.IsAvailable())
+	_, err = breaker.Analyze(ctx, "text")
+	assert.ErrorIs(t, err, unavailable)
+	assert.Equal(t, 4, inner.calls)
+	assert.Equal(t, BreakerOpen, breaker.State())
+	
+	clock = clock.Add(30 * time.Second)
+	_, err = breaker.Analyze(ctx, "text")
+	assert.ErrorContains(t, err, "circuit breaker open")
+	assert.Equal(t, 4, inner.calls)
+	
+	// Half-open again: the trial succeeds and the breaker closes.
+	clock = clock.Add(30 * time.Second)
+	assert.Equal(t, BreakerHalfOpen, breaker.State())
+	result, err := breaker.Analyze(ctx, "text")
+	require.NoError(t, err)
+	assert.Equal(t, "ok", result.Summary)
+	assert.Equal(t, BreakerClosed, breaker.State())
+	
+	_, err = breaker.Analyze(ctx, "text")
+	assert.NoError(t, err)
+	assert.Equal(t, 6, inner.calls)
+}
+
+func TestCircuitBreakerProvider_CountsOnlyConsecutiveOutages(t *testing.T) {
+	unavailable := fmt.Errorf("%w: upstream down", ErrLLMUnavailable)
+	inner := &scriptedProvider{errs: []error{
+		unavailable,
+		context.DeadlineExceeded,
+		nil,
+		unavailable,
+		ErrInvalidJSON,
+		unavailable,
+		context.Canceled,
+		unavailable,
+	}}
+	breaker := NewCircuitBreakerProvider(inner, 2, time.Minute)
+	
+	for i := 0; i < 2; i++ {
+		breaker.Analyze(context.Background(), "text")
+	}
+	assert.Equal(t, BreakerOpen, breaker.State(), "timeouts count as outages")
+	
+	breaker.state = BreakerClosed
+	for i := 0; i < 3; i++ {
+		breaker.Analyze(context.Background(), "text")
+	}
+	assert.Equal(t, BreakerClosed, breaker.State(), "a success or a bad response resets the count")
+	
+	for i := 0; i < 3; i++ {
+		breaker.Analyze(context.Background(), "text")
+	}
+	assert.Equal(t, BreakerOpen, breaker.State(), "cancellations neither count nor reset")
+}
+
+// gatedProvider blocks each call until release is closed.
+type gatedProvider struct {
+	release chan struct{}
+	started chan struct{}
+}
+
+func (p *gatedProvider) Analyze(ctx context.Context, text string) (*AnalysisResult, error) {
+	p.started <- struct{}{}
+	<-p.release
+	return &AnalysisResult{Summary: "ok"}, nil
+}
+
+func (p *gatedProvider) IsAvailable() bool {
+	return true
+}
+
+func TestCircuitBreakerProvider_SingleTrialWhileHalfOpen(t *testing.T) {
+	inner := &gatedProvider{release: make(chan struct{}), started: make(chan struct{}, 1)}
+	breaker := NewCircuitBreakerProvider(inner, 1, time.Minute)
+	breaker.state = BreakerOpen
+	breaker.openedAt = time.Now().Add(-time.Hour)
+	
+	done := make(chan error, 1)
+	go func() {
+		_, err := breaker.Analyze(context.Background(), "trial")
+		done <- err
+	}()
+	<-inner.started
+	
+	_, err := breaker.Analyze(context.Background(), "concurrent")
+	assert.ErrorIs(t, err, ErrLLMUnavailable, "only the trial reaches the provider")
+	
+	close(inner.release)
+	require.NoError(t, <-done)
+	assert.Equal(t, BreakerClosed, breaker.State())
+}
+
+func TestNewProvider_CircuitBreaker(t *testing.T) {
+	provider, err := NewProvider(Config{Provider: "mock", MaxRetries: 2, BreakerFailures: 3})
+	require.NoError(t, err)
+	require.IsType(t, &CircuitBreakerProvider{}, provider)
+	assert.IsType(t, &RetryingProvider{}, provider.(*CircuitBreakerProvider).provider, "retries happen inside the breaker")
+}
\ No newline at end of file
@@ -0,0 +1,68 @@
+package llm
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"unicode/utf8"
+)
+
+// maxDebugChars caps each logged prompt or response.
+const maxDebugChars = 2000
+
+var (
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+	numberPattern = regexp.MustCompile(`\+?\d[\d -]{5,}\d`)
+)
+
+// debugLogger records the raw prompt and response of each provider call
+// when Config.Debug is set, so an unexpected response can be inspected after
+// the fact. Both are scrubbed of e-mail addresses and long digit runs such
+// as phone and card numbers, then truncated. Disabled loggers cost nothing.
+type debugLogger struct {
+	enabled bool
+	logger  *log.Logger
+}
+
+func newDebugLogger(config Config) *debugLogger {
+	logger := config.DebugLogger
+	if logger == nil {
+		logger = log.Default()
+	}
+	return &debugLogger{enabled: config.Debug, logger: logger}
+}
+
+// exchange logs a prompt and the provider's raw response. Real providers
+// call it with the response body before parsing it.
+func (d *debugLogger) exchange(provider, prompt, response string) {
+	if d == nil || !d.enabled {
+		return
+	}
+	d.logger.Printf("DEBUG llm %s prompt: %s", provider, redactDebug(prompt))
+	d.logger.Printf("DEBUG llm %s response: %s", provider, redactDebug(response))
+}
+
+// result logs the response of a provider that answers with a parsed result
+// rather than a body, as JSON.
+func (d *debugLogger) result(provider, prompt string, result *AnalysisResult) {
+	if d == nil || !d.enabled {
+		return
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		data = []byte(fmt.Sprintf("unencodable result: %v", err))
+	}
+	d.exchange(provider, prompt, string(data))
+}
+
+func redactDebug(s string) string {
+	s = emailPattern.ReplaceAllString(s, "[email]")
+	s = numberPattern.ReplaceAllString(s, "[number]")
+	
+	if utf8.RuneCountInString(s) <= maxDebugChars {
+		return s
+	}
+	runes := []rune(s)
+	return fmt.Sprintf("%s... (%d more characters)", string(runes[:maxDebugChars]), len(runes)-maxDebugChars)
+}
\ No newline at end of file
@@ -0,0 +1,63 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDebugLogging(t *testing.T) {
+	path := writeFixture(t, fixtureFile{
+		Default: &AnalysisResult{Summary: "Raw fixture summary", Topics: []string{"debugging"}},
+	})
+	text := "Contact jane.doe@example.com or +1 555-123-4567 about the outage."
+	
+	for _, enabled := range []bool{true, false} {
+		var buf bytes.Buffer
+		provider, err := NewProvider(Config{
+			Provider:    "fixture",
+			FixturePath: path,
+			Debug:       enabled,
+			DebugLogger: log.New(&buf, "", 0),
+		})
+		require.NoError(t, err)
+		
+		_, err = provider.Analyze(context.Background(), text)
+		require.NoError(t, err)
+		
+		if !enabled {
+			assert.Empty(t, buf.String())
+			continue
+		}
+		
+		output := buf.String()
+		assert.Contains(t, output, "DEBUG llm fixture response: ")
+		assert.Contains(t, output, `"summary":"Raw fixture summary"`)
+		assert.Contains(t, output, "about the outage")
+		assert.NotContains(t, output, "jane.doe@example.com")
+		assert.NotContains(t, output, "555-123-4567")
+		assert.Contains(t, output, "[email]")
+		assert.Contains(t, output, "[number]")
+	}
+}
+
+func TestParseLoggedResponse_LogsInvalidJSON(t *testing.T) {
+	var buf bytes.Buffer
+	debug := newDebugLogger(Config{Debug: true, DebugLogger: log.New(&buf, "", 0)})
+	
+	_, err := parseLoggedResponse(debug, "openai", "prompt", "Sorry, I can't help with that.", NewSentimentSet(nil))
+	assert.ErrorIs(t, err, ErrInvalidJSON)
+	assert.Contains(t, buf.String(), "DEBUG llm openai response: Sorry, I can't help with that.")
+}
+
+func TestRedactDebug_Truncates(t *testing.T) {
+	redacted := redactDebug(strings.Repeat("a", maxDebugChars+10))
+	assert.True(t, strings.HasPrefix(redacted, strings.Repeat("a", maxDebugChars)+"..."))
+	assert.Contains(t, redacted, "(10 more characters)")
+	assert.Equal(t, "short", redactDebug("short"))
+}
\ No newline at end of file
@@ -0,0 +1,62 @@
+package llm
+
+import (
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONResponse_EmbeddedJSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "Leading prose",
+			input: `Here is your analysis: {"summary": "A summary", "title": "Nested {braces}", "topics": ["a", "b", "c"], "sentiment": "positive"}`,
+		},
+		{
+			name:  "Trailing prose",
+			input: `{"summary": "A summary", "title": "Nested {braces}", "topics": ["a", "b", "c"], "sentiment": "positive"}. Let me know if you need anything else!`,
+		},
+		{
+			name:  "Leading and trailing prose",
+			input: `Sure! {"summary": "A summary", "title": "Nested {braces}", "topics": ["a", "b", "c"], "sentiment": "positive"} Hope this helps.`,
+		},
+		{
+			name:  "Code fenced",
+			input: "```json\n{\n  \"summary\": \"A summary\",\n  \"title\": \"Nested {braces}\",\n  \"topics\": [\"a\", \"b\", \"c\"],\n  \"sentiment\": \"positive\"\n}\n```",
+		},
+		{
+			name:  "Prose containing a non-JSON brace first",
+			input: `Using the {template} you gave: {"summary": "A summary", "title": "Nested {braces}", "topics": ["a", "b", "c"], "sentiment": "positive"}`,
+		},
+		{
+			name:  "Nested objects",
+			input: `Result: {"summary": "A summary", "title": "Nested {braces}", "extra": {"inner": {"deep": true}}, "topics": ["a", "b", "c"], "sentiment": "positive"} done`,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseJSONResponse(tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, "A summary", result.Summary)
+			assert.Equal(t, "Nested {braces}", result.Title)
+			assert.Equal(t, []string{"a", "b", "c"}, result.Topics)
+			assert.Equal(t, "positive", result.Sentiment)
+			assert.False(t, result.Truncated)
+		})
+	}
+}
+
+func TestParseJSONResponse_NoEmbeddedObject(t *testing.T) {
+	for _, input := range []string{
+		"I'm sorry, I can't analyze this text.",
+		"The answer is [1, 2, 3].",
+	} {
+		_, err := parseJSONResponse(input)
+		assert.ErrorIs(t, err, ErrInvalidJSON, input)
+	}
+}
\ No newline at end of file
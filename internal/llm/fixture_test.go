@@ -0,0 +1,88 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFixture(t *testing.T, fixtures fixtureFile) string {
+	t.Helper()
+	
+	data, err := json.Marshal(fixtures)
+	require.NoError(t, err)
+	
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	require.NoError(t, os.WriteFile(path, data, 0644))
+	return path
+}
+
+func TestFixtureProvider_Analyze(t *testing.T) {
+	known := &AnalysisResult{
+		Summary:   "Rust adoption is growing in systems programming.",
+		Title:     "Rust Adoption",
+		Topics:    []string{"rust", "systems", "programming"},
+		Sentiment: "positive",
+	}
+	fallback := &AnalysisResult{
+		Summary:   "Unknown input",
+		Title:     "Fallback",
+		Topics:    []string{"unknown"},
+		Sentiment: "neutral",
+	}
+	
+	path := writeFixture(t, fixtureFile{
+		Default: fallback,
+		Results: map[string]*AnalysisResult{
+			FixtureKey("Rust is great for systems work."): known,
+		},
+	})
+	
+	provider, err := NewProvider(Config{Provider: "fixture", FixturePath: path})
+	require.NoError(t, err)
+	
+	ctx := context.Background()
+	
+	result, err := provider.Analyze(ctx, "Rust is great for systems work.")
+	require.NoError(t, err)
+	assert.Equal(t, known, result)
+	
+	again, err := provider.Analyze(ctx, "Rust is great for systems work.")
+	require.NoError(t, err)
+	assert.Equal(t, result, again)
+	
+	result, err = provider.Analyze(ctx, "Something not in the fixture file.")
+	require.NoError(t, err)
+	assert.Equal(t, fallback, result)
+	
+	_, err = provider.Analyze(ctx, "   ")
+	assert.ErrorIs(t, err, ErrEmptyInput)
+}
+
+func TestFixtureProvider_BuiltInDefault(t *testing.T) {
+	provider, err := NewFixtureProvider(writeFixture(t, fixtureFile{}))
+	require.NoError(t, err)
+	
+	result, err := provider.Analyze(context.Background(), "anything")
+	require.NoError(t, err)
+	assert.Equal(t, "Fixture Default", result.Title)
+	assert.Len(t, result.Topics, 3)
+}
+
+func TestNewFixtureProvider_Errors(t *testing.T) {
+	_, err := NewFixtureProvider("")
+	assert.Error(t, err)
+	
+	_, err = NewFixtureProvider(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+	
+	path := filepath.Join(t.TempDir(), "bad.json")
+	require.NoError(t, os.WriteFile(path, []byte("{not json"), 0644))
+	_, err = NewFixtureProvider(path)
+	assert.Error(t, err)
+}
\ No newline at end of file
@@ -0,0 +1,139 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const DefaultGeminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// ErrPromptBlocked means the provider refused to answer the prompt, e.g. on
+// safety grounds. Sending the same text again gets the same refusal, so it is
+// never retried.
+var ErrPromptBlocked = errors.New("prompt blocked by the LLM provider")
+
+// GeminiProvider calls the Gemini generateContent API, asking for a JSON
+// response in the shape every provider returns.
+type GeminiProvider struct {
+	url         string
+	apiKey      string
+	maxTokens   int
+	temperature float32
+	client      *http.Client
+	sentiments  SentimentSet
+	debug       *debugLogger
+}
+
+type geminiPart struct {
+	Text string `json:"text"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role,omitempty"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiRequest struct {
+	SystemInstruction geminiContent          `json:"systemInstruction"`
+	Contents          []geminiContent        `json:"contents"`
+	GenerationConfig  geminiGenerationConfig `json:"generationConfig"`
+}
+
+type geminiGenerationConfig struct {
+	ResponseMimeType string  `json:"responseMimeType"`
+	MaxOutputTokens  int     `json:"maxOutputTokens"`
+	Temperature      float32 `json:"temperature"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content      geminiContent `json:"content"`
+		FinishReason string        `json:"finishReason"`
+	} `json:"candidates"`
+	PromptFeedback struct {
+		BlockReason string `json:"blockReason"`
+	} `json:"promptFeedback"`
+}
+
+func NewGeminiProvider(config Config) (*GeminiProvider, error) {
+	if config.GeminiAPIKey == "" {
+		return nil, fmt.Errorf("gemini provider requires GEMINI_API_KEY")
+	}
+	
+	baseURL := strings.TrimRight(strings.TrimSpace(config.GeminiBaseURL), "/")
+	if baseURL == "" {
+		baseURL = DefaultGeminiBaseURL
+	}
+	model := config.Model
+	if model == "" {
+		model = defaultModels["gemini"]
+	}
+	maxTokens := config.MaxTokens
+	if maxTokens <= 0 {
+		maxTokens = defaultMaxTokens
+	}
+	temperature := config.Temperature
+	if temperature <= 0 {
+		temperature = defaultTemperature
+	}
+	
+	return &GeminiProvider{
+		url:         fmt.Sprintf("%s/models/%s:generateContent", baseURL, url.PathEscape(model)),
+		apiKey:      config.GeminiAPIKey,
+		maxTokens:   maxTokens,
+		temperature: temperature,
+		client:      config.HTTPClient(),
+		sentiments:  NewSentimentSet(config.ValidSentiments),
+		debug:       newDebugLogger(config),
+	}, nil
+}
+
+func (p *GeminiProvider) Analyze(ctx context.Context, text string) (*AnalysisResult, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, ErrEmptyInput
+	}
+	
+	system, user := analysisPrompt(text, p.sentiments)
+	data, err := postJSON(ctx, p.client, "gemini", p.url, http.Header{"X-Goog-Api-Key": {p.apiKey}}, geminiRequest{
+		SystemInstruction: geminiContent{Parts: []geminiPart{{Text: system}}},
+		Contents:          []geminiContent{{Role: "user", Parts: []geminiPart{{Text: user}}}},
+		GenerationConfig: geminiGenerationConfig{
+			ResponseMimeType: "application/json",
+			MaxOutputTokens:  p.maxTokens,
+			Temperature:      p.temperature,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	
+	var generated geminiResponse
+	if err := json.Unmarshal(data, &generated); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if reason := generated.PromptFeedback.BlockReason; reason != "" {
+		return nil, fmt.Errorf("%w: gemini block reason %s", ErrPromptBlocked, reason)
+	}
+	if len(generated.Candidates) == 0 {
+		return nil, fmt.Errorf("%w: gemini response has no candidates", ErrInvalidJSON)
+	}
+	
+	// A candidate's answer may be split across several parts.
+	var content strings.Builder
+	for _, part := range generated.Candidates[0].Content.Parts {
+		content.WriteString(part.Text)
+	}
+	
+	return parseLoggedResponse(p.debug, "gemini", user, content.String(), p.sentiments)
+}
+
+// IsAvailable reports whether a Gemini API key is set. Gemini is not asked,
+// so polling health doesn't spend quota.
+func (p *GeminiProvider) IsAvailable() bool {
+	return p.apiKey != ""
+}
\ No newline at end of file
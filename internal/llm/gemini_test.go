@@ -0,0 +1,126 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sampleGeminiResponse is a generateContent reply with the JSON answer split
+// across two parts, as Gemini sometimes returns it.
+const sampleGeminiResponse = `{
+  "candidates": [
+    {
+      "content": {
+        "role": "model",
+        "parts": [
+          {"text": "{\"summary\": \"The team shipped the new search index.\", \"title\": \"Search launch\", "},
+          {"text": "\"topics\": [\"search\", \"infrastructure\", \"release\"], \"sentiment\": \"positive\", \"sentiment_score\": 0.7}"}
+        ]
+      },
+      "finishReason": "STOP",
+      "index": 0
+    }
+  ],
+  "usageMetadata": {"promptTokenCount": 120, "candidatesTokenCount": 48, "totalTokenCount": 168}
+}`
+
+func geminiServer(t *testing.T, status int, body string, got **http.Request, payload *geminiRequest) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got != nil {
+			*got = r
+		}
+		if payload != nil {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(payload))
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGeminiProvider_Analyze(t *testing.T) {
+	var got *http.Request
+	var payload geminiRequest
+	server := geminiServer(t, http.StatusOK, sampleGeminiResponse, &got, &payload)
+	
+	provider, err := NewProvider(Config{Provider: "gemini", GeminiAPIKey: "gemini-key", GeminiBaseURL: server.URL})
+	require.NoError(t, err)
+	assert.True(t, provider.IsAvailable())
+	
+	result, err := provider.Analyze(context.Background(), "We shipped the new search index today.")
+	require.NoError(t, err)
+	assert.Equal(t, "The team shipped the new search index.", result.Summary)
+	assert.Equal(t, "Search launch", result.Title)
+	assert.Equal(t, []string{"search", "infrastructure", "release"}, result.Topics)
+	assert.Equal(t, "positive", result.Sentiment)
+	assert.Equal(t, 0.7, result.SentimentScore)
+	
+	require.NotNil(t, got)
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "/models/gemini-1.5-flash:generateContent", got.URL.Path)
+	assert.Equal(t, "gemini-key", got.Header.Get("x-goog-api-key"))
+	assert.Empty(t, got.URL.Query().Get("key"), "the key is not sent in the URL")
+	
+	assert.Equal(t, "application/json", payload.GenerationConfig.ResponseMimeType)
+	require.Len(t, payload.Contents, 1)
+	assert.Equal(t, "user", payload.Contents[0].Role)
+	assert.Equal(t, "We shipped the new search index today.", payload.Contents[0].Parts[0].Text)
+	assert.Contains(t, payload.SystemInstruction.Parts[0].Text, `"topics"`)
+}
+
+func TestGeminiProvider_Model(t *testing.T) {
+	var got *http.Request
+	server := geminiServer(t, http.StatusOK, sampleGeminiResponse, &got, nil)
+	
+	provider, err := NewGeminiProvider(Config{GeminiAPIKey: "k", GeminiBaseURL: server.URL + "/", Model: "gemini-1.5-pro"})
+	require.NoError(t, err)
+	_, err = provider.Analyze(context.Background(), "Some text.")
+	require.NoError(t, err)
+	assert.Equal(t, "/models/gemini-1.5-pro:generateContent", got.URL.Path)
+}
+
+func TestGeminiProvider_Errors(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		unavailable bool
+		invalidJSON bool
+		blocked     bool
+	}{
+		{name: "Quota exhausted", status: http.StatusTooManyRequests, body: `{"error": {"code": 429}}`, unavailable: true},
+		{name: "Server error", status: http.StatusServiceUnavailable, body: `{"error": {"code": 503}}`, unavailable: true},
+		{name: "Bad key", status: http.StatusBadRequest, body: `{"error": {"message": "API key not valid"}}`},
+		{name: "Blocked prompt", status: http.StatusOK, body: `{"promptFeedback": {"blockReason": "SAFETY"}}`, blocked: true},
+		{name: "No candidates", status: http.StatusOK, body: `{"candidates": []}`, invalidJSON: true},
+		{name: "Non-JSON answer", status: http.StatusOK, body: `{"candidates": [{"content": {"parts": [{"text": "I cannot help."}]}}]}`, invalidJSON: true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := geminiServer(t, tt.status, tt.body, nil, nil)
+			provider, err := NewGeminiProvider(Config{GeminiAPIKey: "k", GeminiBaseURL: server.URL})
+			require.NoError(t, err)
+			
+			result, err := provider.Analyze(context.Background(), "Some text.")
+			require.Error(t, err)
+			assert.Nil(t, result)
+			assert.Equal(t, tt.unavailable, errors.Is(err, ErrLLMUnavailable), err)
+			assert.Equal(t, tt.invalidJSON, errors.Is(err, ErrInvalidJSON), err)
+			assert.Equal(t, tt.blocked, errors.Is(err, ErrPromptBlocked), err)
+		})
+	}
+}
+
+func TestNewGeminiProvider_RequiresKey(t *testing.T) {
+	_, err := NewGeminiProvider(Config{})
+	assert.ErrorContains(t, err, "GEMINI_API_KEY")
+}
\ No newline at end of file
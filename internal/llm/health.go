@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// HealthCachingProvider memoizes IsAvailable for ttl so frequent health
+// checks don't each reach the upstream provider. The first call checks
+// synchronously; once the cached value is stale it is still returned while a
+// single background check refreshes it.
+type HealthCachingProvider struct {
+	provider Provider
+	ttl      time.Duration
+	now      func() time.Time
+	
+	mu         sync.Mutex
+	checked    bool
+	available  bool
+	checkedAt  time.Time
+	refreshing bool
+}
+
+func NewHealthCachingProvider(provider Provider, ttl time.Duration) *HealthCachingProvider {
+	return &HealthCachingProvider{
+		provider: provider,
+		ttl:      ttl,
+		now:      time.Now,
+	}
+}
+
+func (p *HealthCachingProvider) Analyze(ctx context.Context, text string) (*AnalysisResult, error) {
+	return p.provider.Analyze(ctx, text)
+}
+
+func (p *HealthCachingProvider) IsAvailable() bool {
+	p.mu.Lock()
+	
+	if !p.checked {
+		// Hold the lock so concurrent first callers share one check.
+		defer p.mu.Unlock()
+		p.store(p.provider.IsAvailable())
+		return p.available
+	}
+	
+	if !p.refreshing && p.now().Sub(p.checkedAt) >= p.ttl {
+		p.refreshing = true
+		go p.refresh()
+	}
+	
+	available := p.available
+	p.mu.Unlock()
+	return available
+}
+
+func (p *HealthCachingProvider) refresh() {
+	available := p.provider.IsAvailable()
+	
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.store(available)
+	p.refreshing = false
+}
+
+// store records a check result; p.mu must be held.
+func (p *HealthCachingProvider) store(available bool) {
+	p.checked = true
+	p.available = available
+	p.checkedAt = p.now()
+}
\ No newline at end of file
@@ -0,0 +1,80 @@
+package llm
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type healthProvider struct {
+	available atomic.Bool
+	calls     atomic.Int32
+}
+
+func (p *healthProvider) Analyze(ctx context.Context, text string) (*AnalysisResult, error) {
+	return &AnalysisResult{Summary: "ok"}, nil
+}
+
+func (p *healthProvider) IsAvailable() bool {
+	p.calls.Add(1)
+	return p.available.Load()
+}
+
+func TestHealthCachingProvider_IsAvailable(t *testing.T) {
+	inner := &healthProvider{}
+	inner.available.Store(true)
+	
+	now := time.Now()
+	var clock sync.Mutex
+	provider := NewHealthCachingProvider(inner, time.Second)
+	provider.now = func() time.Time {
+		clock.Lock()
+		defer clock.Unlock()
+		return now
+	}
+	advance := func(d time.Duration) {
+		clock.Lock()
+		defer clock.Unlock()
+		now = now.Add(d)
+	}
+	
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.True(t, provider.IsAvailable())
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), inner.calls.Load())
+	
+	inner.available.Store(false)
+	advance(500 * time.Millisecond)
+	assert.True(t, provider.IsAvailable())
+	assert.Equal(t, int32(1), inner.calls.Load())
+	
+	// Once stale, the cached value is still served while one background
+	// check refreshes it.
+	advance(time.Second)
+	for i := 0; i < 10; i++ {
+		provider.IsAvailable()
+	}
+	require.Eventually(t, func() bool { return !provider.IsAvailable() }, time.Second, time.Millisecond)
+	assert.Equal(t, int32(2), inner.calls.Load())
+}
+
+func TestNewProvider_HealthCache(t *testing.T) {
+	provider, err := NewProvider(Config{Provider: "mock", HealthCacheTTL: time.Second})
+	require.NoError(t, err)
+	assert.IsType(t, &HealthCachingProvider{}, provider)
+	
+	provider, err = NewProvider(Config{Provider: "mock"})
+	require.NoError(t, err)
+	assert.IsType(t, &MockProvider{}, provider)
+}
\ No newline at end of file
@@ -0,0 +1,58 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const maxResponseBytes = 1 << 20
+
+// postJSON sends payload to an HTTP-backed provider's API and returns the
+// response body. Network failures, rate limits and server errors wrap
+// ErrLLMUnavailable so they are retried; any other error status means the
+// request itself is wrong, e.g. a bad key or model, and is returned as is.
+func postJSON(ctx context.Context, client *http.Client, provider, url string, header http.Header, payload interface{}) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s request: %w", provider, err)
+	}
+	
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build %s request: %w", provider, err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: %v", ErrLLMUnavailable, err)
+	}
+	defer resp.Body.Close()
+	
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("%w: failed to read %s response: %v", ErrLLMUnavailable, provider, err)
+	}
+	
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, fmt.Errorf("%w: %s returned status %d", ErrLLMUnavailable, provider, resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%s returned status %d: %s", provider, resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	return data, nil
+}
\ No newline at end of file
@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"log"
+	"strings"
+	"time"
 )
 
 var (
@@ -19,52 +22,151 @@ type Provider interface {
 }
 
 type AnalysisResult struct {
-	Summary   string   `json:"summary"`
-	Title     string   `json:"title"`
-	Topics    []string `json:"topics"`
-	Sentiment string   `json:"sentiment"`
+	Summary        string   `json:"summary"`
+	Title          string   `json:"title"`
+	Topics         []string `json:"topics"`
+	Sentiment      string   `json:"sentiment"`
+	SentimentScore float64  `json:"sentiment_score"`
+	Truncated      bool     `json:"truncated,omitempty"`
 }
 
 type Config struct {
-	Provider       string
-	Model          string
-	MaxTokens      int
-	Temperature    float32
+	Provider        string
+	Model           string
+	MaxTokens       int
+	Temperature     float32
+	FixturePath     string
+	ValidSentiments []string
+	MaxRetries      int
+	RetryBackoff    time.Duration
+	HealthCacheTTL  time.Duration
+	RequestTimeout  time.Duration
+	BreakerFailures int
+	BreakerCooldown time.Duration
+	Debug           bool
+	DebugLogger     *log.Logger
+	AzureEndpoint   string
+	AzureAPIKey     string
+	AzureDeployment string
+	AzureAPIVersion string
+	GeminiAPIKey    string
+	GeminiBaseURL   string
 }
 
+// NewProvider builds the configured provider, wrapped in a TimeoutProvider
+// when RequestTimeout is positive, in a RetryingProvider when MaxRetries is
+// positive, in a CircuitBreakerProvider when BreakerFailures is positive and
+// in a HealthCachingProvider when HealthCacheTTL is positive. The timeout
+// applies to each attempt, not to all retries together, and the breaker
+// counts a call that failed every retry as one failure.
 func NewProvider(config Config) (Provider, error) {
+	provider, err := newBaseProvider(config)
+	if err != nil {
+		return nil, err
+	}
+	
+	if config.RequestTimeout > 0 {
+		provider = NewTimeoutProvider(provider, config.RequestTimeout)
+	}
+	if config.MaxRetries > 0 {
+		provider = NewRetryingProvider(provider, config.MaxRetries, config.RetryBackoff)
+	}
+	if config.BreakerFailures > 0 {
+		provider = NewCircuitBreakerProvider(provider, config.BreakerFailures, config.BreakerCooldown)
+	}
+	if config.HealthCacheTTL > 0 {
+		provider = NewHealthCachingProvider(provider, config.HealthCacheTTL)
+	}
+	return provider, nil
+}
+
+func newBaseProvider(config Config) (Provider, error) {
 	switch config.Provider {
 	case "mock":
-		return NewMockProvider(), nil
+		provider := NewMockProvider()
+		provider.sentiments = NewSentimentSet(config.ValidSentiments)
+		provider.debug = newDebugLogger(config)
+		return provider, nil
+	case "fixture":
+		provider, err := NewFixtureProvider(config.FixturePath)
+		if err != nil {
+			return nil, err
+		}
+		provider.debug = newDebugLogger(config)
+		return provider, nil
+	case "azure":
+		return NewAzureOpenAIProvider(config)
+	case "gemini":
+		return NewGeminiProvider(config)
 	default:
 		return nil, fmt.Errorf("unsupported LLM provider: %s", config.Provider)
 	}
 }
 
+// parseLoggedResponse is how HTTP-backed providers should parse a response
+// body: it goes to the debug log first, so it is on record even when parsing
+// fails.
+func parseLoggedResponse(debug *debugLogger, provider, prompt, content string, sentiments SentimentSet) (*AnalysisResult, error) {
+	debug.exchange(provider, prompt, content)
+	return parseJSONResponseWithSentiments(content, sentiments)
+}
+
 func parseJSONResponse(content string) (*AnalysisResult, error) {
+	return parseJSONResponseWithSentiments(content, NewSentimentSet(nil))
+}
+
+// dedupeTopics trims topics and drops blank ones and case-insensitive
+// repeats, keeping the first spelling of each, so repeats don't use up the
+// topic limit.
+func dedupeTopics(topics []string) []string {
+	seen := make(map[string]bool, len(topics))
+	result := make([]string, 0, len(topics))
+	for _, topic := range topics {
+		topic = strings.TrimSpace(topic)
+		key := strings.ToLower(topic)
+		if topic == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, topic)
+	}
+	return result
+}
+
+func parseJSONResponseWithSentiments(content string, sentiments SentimentSet) (*AnalysisResult, error) {
+	object, truncated, err := locateJSONObject(content)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	if err := validateSchema(object, analysisSchema); err != nil {
+		return nil, err
+	}
+	
 	var result AnalysisResult
-	if err := json.Unmarshal([]byte(content), &result); err != nil {
+	if err := json.Unmarshal([]byte(object), &result); err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrInvalidJSON, err)
 	}
+	result.Truncated = truncated
 	
 	if result.Summary == "" {
 		result.Summary = "No summary available"
 	}
 	
+	result.Topics = dedupeTopics(result.Topics)
 	if len(result.Topics) == 0 {
 		result.Topics = []string{"general", "uncategorized", "text"}
 	} else if len(result.Topics) > 3 {
 		result.Topics = result.Topics[:3]
 	}
 	
-	validSentiments := map[string]bool{
-		"positive": true,
-		"neutral":  true,
-		"negative": true,
-	}
+	// An unusable sentiment is left empty rather than resolved here, so the
+	// caller can fall back to the lexicon instead of a flat neutral.
+	result.Sentiment = sentiments.Match(result.Sentiment)
 	
-	if !validSentiments[result.Sentiment] {
-		result.Sentiment = "neutral"
+	if result.SentimentScore > 1 {
+		result.SentimentScore = 1
+	} else if result.SentimentScore < -1 {
+		result.SentimentScore = -1
 	}
 	
 	return &result, nil
@@ -6,17 +6,22 @@ import (
 	"math/rand"
 	"strings"
 	"time"
+	
+	"github.com/user/llm-knowledge-extractor/internal/analyzer"
 )
 
 type MockProvider struct {
 	failureRate float64
 	delay       time.Duration
+	sentiments  SentimentSet
+	debug       *debugLogger
 }
 
 func NewMockProvider() *MockProvider {
 	return &MockProvider{
 		failureRate: 0.1,
 		delay:       100 * time.Millisecond,
+		sentiments:  NewSentimentSet(nil),
 	}
 }
 
@@ -58,20 +63,22 @@ func (p *MockProvider) Analyze(ctx context.Context, text string) (*AnalysisResul
 		"analysis",
 	}
 	
-	sentiments := []string{"positive", "neutral", "negative"}
-	sentiment := sentiments[rand.Intn(len(sentiments))]
+	sentiment, sentimentScore := analyzer.AnalyzeSentiment(text)
 	
 	title := ""
 	if len(words) > 3 {
 		title = strings.Title(strings.Join(words[:min(3, len(words))], " "))
 	}
 	
-	return &AnalysisResult{
-		Summary:   summary,
-		Title:     title,
-		Topics:    topics,
-		Sentiment: sentiment,
-	}, nil
+	result := &AnalysisResult{
+		Summary:        summary,
+		Title:          title,
+		Topics:         topics,
+		Sentiment:      p.sentiments.Resolve(sentiment),
+		SentimentScore: sentimentScore,
+	}
+	p.debug.result("mock", text, result)
+	return result, nil
 }
 
 func (p *MockProvider) IsAvailable() bool {
@@ -53,6 +53,8 @@ func TestMockProvider_Analyze(t *testing.T) {
 					assert.NotEmpty(t, result.Summary)
 					assert.Len(t, result.Topics, 3)
 					assert.Contains(t, []string{"positive", "neutral", "negative"}, result.Sentiment)
+					assert.GreaterOrEqual(t, result.SentimentScore, -1.0)
+					assert.LessOrEqual(t, result.SentimentScore, 1.0)
 				}
 			}
 		})
@@ -109,7 +111,43 @@ func TestParseJSONResponse(t *testing.T) {
 			},
 		},
 		{
-			name: "Invalid sentiment defaults to neutral",
+			name: "Duplicate topics removed before truncating",
+			input: `{
+				"summary": "Summary",
+				"topics": ["AI", "ai", " AI ", "Artificial Intelligence", "AI", "robotics", "ethics"],
+				"sentiment": "neutral"
+			}`,
+			expectError: false,
+			validate: func(t *testing.T, result *AnalysisResult) {
+				assert.Equal(t, []string{"AI", "Artificial Intelligence", "robotics"}, result.Topics)
+			},
+		},
+		{
+			name: "Blank and repeated topics only",
+			input: `{
+				"summary": "Summary",
+				"topics": ["Cloud", "cloud", "  ", "CLOUD"],
+				"sentiment": "neutral"
+			}`,
+			expectError: false,
+			validate: func(t *testing.T, result *AnalysisResult) {
+				assert.Equal(t, []string{"Cloud"}, result.Topics)
+			},
+		},
+		{
+			name: "Only blank topics gets defaults",
+			input: `{
+				"summary": "Summary",
+				"topics": ["", " "],
+				"sentiment": "neutral"
+			}`,
+			expectError: false,
+			validate: func(t *testing.T, result *AnalysisResult) {
+				assert.Equal(t, []string{"general", "uncategorized", "text"}, result.Topics)
+			},
+		},
+		{
+			name: "Invalid sentiment left empty",
 			input: `{
 				"summary": "Summary",
 				"topics": ["t1"],
@@ -117,7 +155,43 @@ func TestParseJSONResponse(t *testing.T) {
 			}`,
 			expectError: false,
 			validate: func(t *testing.T, result *AnalysisResult) {
-				assert.Equal(t, "neutral", result.Sentiment)
+				assert.Empty(t, result.Sentiment)
+			},
+		},
+		{
+			name: "Missing sentiment left empty",
+			input: `{
+				"summary": "Summary",
+				"topics": ["t1"]
+			}`,
+			expectError: false,
+			validate: func(t *testing.T, result *AnalysisResult) {
+				assert.Empty(t, result.Sentiment)
+			},
+		},
+		{
+			name: "Sentiment case is normalized",
+			input: `{
+				"summary": "Summary",
+				"topics": ["t1"],
+				"sentiment": " Positive "
+			}`,
+			expectError: false,
+			validate: func(t *testing.T, result *AnalysisResult) {
+				assert.Equal(t, "positive", result.Sentiment)
+			},
+		},
+		{
+			name: "Sentiment score clamped to range",
+			input: `{
+				"summary": "Summary",
+				"topics": ["t1"],
+				"sentiment": "positive",
+				"sentiment_score": 3.5
+			}`,
+			expectError: false,
+			validate: func(t *testing.T, result *AnalysisResult) {
+				assert.Equal(t, 1.0, result.SentimentScore)
 			},
 		},
 		{
@@ -199,4 +273,51 @@ func TestNewProvider(t *testing.T) {
 			}
 		})
 	}
+}
+
+func TestParseJSONResponse_CustomSentiments(t *testing.T) {
+	sentiments := NewSentimentSet([]string{"very_negative", "negative", "neutral", "positive", "very_positive"})
+	
+	tests := []struct {
+		name      string
+		sentiment string
+		expected  string
+	}{
+		{
+			name:      "Five-point label accepted",
+			sentiment: "very_positive",
+			expected:  "very_positive",
+		},
+		{
+			name:      "Default label still accepted",
+			sentiment: "negative",
+			expected:  "negative",
+		},
+		{
+			name:      "Unknown label left empty",
+			sentiment: "ecstatic",
+			expected:  "",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseJSONResponseWithSentiments(`{"summary": "s", "topics": ["t"], "sentiment": "`+tt.sentiment+`"}`, sentiments)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Sentiment)
+		})
+	}
+	
+	result, err := parseJSONResponse(`{"summary": "s", "sentiment": "very_positive"}`)
+	assert.NoError(t, err)
+	assert.Empty(t, result.Sentiment, "default set rejects five-point labels")
+}
+
+func TestSentimentSet_FallbackWithoutNeutral(t *testing.T) {
+	sentiments := NewSentimentSet([]string{"Bad", "OK", "Good"})
+	
+	assert.True(t, sentiments.Contains("good"))
+	assert.Equal(t, "ok", sentiments.Resolve("positive"))
+	assert.Equal(t, "good", sentiments.Match("GOOD"))
+	assert.Empty(t, sentiments.Match("positive"))
 }
\ No newline at end of file
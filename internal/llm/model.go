@@ -0,0 +1,37 @@
+package llm
+
+import "strings"
+
+// defaultModels holds each provider type's model when none is configured.
+// Providers that don't take a model, like mock and fixture, have no entry.
+var defaultModels = map[string]string{
+	"openai": "gpt-4o-mini",
+	"claude": "claude-3-haiku-20240307",
+	"ollama": "llama3",
+	"gemini": "gemini-1.5-flash",
+}
+
+// ModelEnvKey is the variable that sets the model for one provider type,
+// e.g. LLM_MODEL_OPENAI.
+func ModelEnvKey(provider string) string {
+	key := strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return '_'
+	}, strings.ToUpper(provider))
+	return "LLM_MODEL_" + key
+}
+
+// ResolveModel picks the model for provider from LLM_MODEL_<PROVIDER>, then
+// LLM_MODEL, then the provider's built-in default, so each provider in a
+// chain can run its own model. lookup reads a variable, normally os.Getenv.
+func ResolveModel(provider string, lookup func(key string) string) string {
+	if model := strings.TrimSpace(lookup(ModelEnvKey(provider))); model != "" {
+		return model
+	}
+	if model := strings.TrimSpace(lookup("LLM_MODEL")); model != "" {
+		return model
+	}
+	return defaultModels[strings.ToLower(provider)]
+}
\ No newline at end of file
@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveModel(t *testing.T) {
+	tests := []struct {
+		name     string
+		provider string
+		env      map[string]string
+		expected string
+	}{
+		{"provider default", "openai", nil, "gpt-4o-mini"},
+		{"other provider default", "ollama", nil, "llama3"},
+		{"global model overrides default", "openai", map[string]string{"LLM_MODEL": "gpt-4o"}, "gpt-4o"},
+		{
+			name:     "provider model overrides global",
+			provider: "openai",
+			env:      map[string]string{"LLM_MODEL": "gpt-4o", "LLM_MODEL_OPENAI": "gpt-4.1-mini"},
+			expected: "gpt-4.1-mini",
+		},
+		{
+			name:     "other provider's model is ignored",
+			provider: "ollama",
+			env:      map[string]string{"LLM_MODEL_OPENAI": "gpt-4.1-mini"},
+			expected: "llama3",
+		},
+		{"blank values are unset", "claude", map[string]string{"LLM_MODEL_CLAUDE": "  ", "LLM_MODEL": ""}, "claude-3-haiku-20240307"},
+		{"no default", "mock", nil, ""},
+		{"configured model without default", "mock", map[string]string{"LLM_MODEL_MOCK": "canned"}, "canned"},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lookup := func(key string) string { return tt.env[key] }
+			assert.Equal(t, tt.expected, ResolveModel(tt.provider, lookup))
+		})
+	}
+}
+
+func TestModelEnvKey(t *testing.T) {
+	assert.Equal(t, "LLM_MODEL_OPENAI", ModelEnvKey("openai"))
+	assert.Equal(t, "LLM_MODEL_AZURE_OPENAI", ModelEnvKey("azure-openai"))
+}
\ No newline at end of file
@@ -0,0 +1,24 @@
+package llm
+
+import (
+	"fmt"
+	"strings"
+)
+
+// analysisInstructions asks for exactly the fields analysisSchema accepts.
+const analysisInstructions = `You analyze text and reply with a single JSON object and nothing else, with these fields:
+- "summary": a one- or two-sentence summary
+- "title": a short title, or "" if none fits
+- "topics": an array of exactly 3 short topic strings
+- "sentiment": one of %s
+- "sentiment_score": a number from -1 (most negative) to 1 (most positive)`
+
+// analysisPrompt returns the system and user messages HTTP-backed providers
+// send for text, so every provider asks for the same shape.
+func analysisPrompt(text string, sentiments SentimentSet) (system, user string) {
+	labels := make([]string, 0, len(sentiments.Labels()))
+	for _, label := range sentiments.Labels() {
+		labels = append(labels, fmt.Sprintf("%q", label))
+	}
+	return fmt.Sprintf(analysisInstructions, strings.Join(labels, ", ")), text
+}
\ No newline at end of file
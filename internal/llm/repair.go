@@ -0,0 +1,192 @@
+package llm
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// locateJSONObject finds the JSON object in a response: the whole content
+// when it is one, else an object embedded in prose, else a repaired
+// truncated object, in which case truncated is set.
+func locateJSONObject(content string) (object string, truncated bool, err error) {
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal([]byte(content), &fields)
+	if err == nil {
+		return content, false, nil
+	}
+	if embedded, ok := extractJSONObject(content); ok {
+		return embedded, false, nil
+	}
+	if repaired, ok := repairTruncatedJSON(content); ok {
+		return repaired, true, nil
+	}
+	return "", false, err
+}
+
+// extractJSONObject returns the first balanced, valid JSON object embedded in
+// content, skipping any prose or code fences the model wrapped around it.
+func extractJSONObject(content string) (string, bool) {
+	for start := strings.Index(content, "{"); start >= 0; {
+		if end, ok := matchingBrace(content, start); ok {
+			candidate := content[start : end+1]
+			if json.Valid([]byte(candidate)) {
+				return candidate, true
+			}
+		}
+		
+		next := strings.Index(content[start+1:], "{")
+		if next < 0 {
+			break
+		}
+		start += next + 1
+	}
+	
+	return "", false
+}
+
+func matchingBrace(content string, start int) (int, bool) {
+	depth := 0
+	inString := false
+	escaped := false
+	
+	for i := start; i < len(content); i++ {
+		ch := content[i]
+		
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+			continue
+		}
+		
+		switch ch {
+		case '"':
+			inString = true
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i, true
+			}
+		}
+	}
+	
+	return 0, false
+}
+
+// repairTruncatedJSON closes any open string and brackets, and if that is not
+// enough drops trailing members one at a time until the object parses.
+func repairTruncatedJSON(content string) (string, bool) {
+	start := strings.Index(content, "{")
+	if start < 0 {
+		return "", false
+	}
+	content = strings.TrimSpace(content[start:])
+	
+	if repaired, ok := closeJSON(content); ok {
+		return repaired, true
+	}
+	
+	commas := topLevelCommas(content)
+	for i := len(commas) - 1; i >= 0; i-- {
+		if repaired, ok := closeJSON(content[:commas[i]]); ok {
+			return repaired, true
+		}
+	}
+	
+	return "", false
+}
+
+func closeJSON(content string) (string, bool) {
+	var stack []byte
+	inString := false
+	escaped := false
+	
+	for i := 0; i < len(content); i++ {
+		ch := content[i]
+		
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+			continue
+		}
+		
+		switch ch {
+		case '"':
+			inString = true
+		case '{':
+			stack = append(stack, '}')
+		case '[':
+			stack = append(stack, ']')
+		case '}', ']':
+			if len(stack) == 0 || stack[len(stack)-1] != ch {
+				return "", false
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	
+	var b strings.Builder
+	b.WriteString(content)
+	
+	if inString {
+		if escaped {
+			trimmed := b.String()
+			b.Reset()
+			b.WriteString(trimmed[:len(trimmed)-1])
+		}
+		b.WriteByte('"')
+	}
+	
+	repaired := strings.TrimRight(b.String(), " \t\r\n")
+	repaired = strings.TrimSuffix(repaired, ",")
+	
+	for i := len(stack) - 1; i >= 0; i-- {
+		repaired += string(stack[i])
+	}
+	
+	return repaired, json.Valid([]byte(repaired))
+}
+
+func topLevelCommas(content string) []int {
+	var commas []int
+	inString := false
+	escaped := false
+	
+	for i := 0; i < len(content); i++ {
+		ch := content[i]
+		
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			}
+			continue
+		}
+		
+		switch ch {
+		case '"':
+			inString = true
+		case ',':
+			commas = append(commas, i)
+		}
+	}
+	
+	return commas
+}
\ No newline at end of file
@@ -0,0 +1,83 @@
+package llm
+
+import (
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONResponse_Truncated(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		validate func(*testing.T, *AnalysisResult)
+	}{
+		{
+			name:  "Cut off inside summary string",
+			input: `{"summary": "The report covers quarterly revenue gro`,
+			validate: func(t *testing.T, result *AnalysisResult) {
+				assert.Equal(t, "The report covers quarterly revenue gro", result.Summary)
+			},
+		},
+		{
+			name:  "Cut off inside topics array",
+			input: `{"summary": "Summary", "title": "Title", "topics": ["finance", "revenue", "gro`,
+			validate: func(t *testing.T, result *AnalysisResult) {
+				assert.Equal(t, "Title", result.Title)
+				assert.Equal(t, []string{"finance", "revenue", "gro"}, result.Topics)
+			},
+		},
+		{
+			name:  "Cut off after a key and colon",
+			input: `{"summary": "Summary", "title": "Title", "sentiment": `,
+			validate: func(t *testing.T, result *AnalysisResult) {
+				assert.Equal(t, "Title", result.Title)
+				assert.Empty(t, result.Sentiment, "left for the caller's fallback")
+			},
+		},
+		{
+			name:  "Cut off inside a key",
+			input: `{"summary": "Summary", "topics": ["a", "b"], "senti`,
+			validate: func(t *testing.T, result *AnalysisResult) {
+				assert.Equal(t, []string{"a", "b"}, result.Topics)
+			},
+		},
+		{
+			name:  "Trailing comma",
+			input: "{\n  \"summary\": \"Summary\",\n  \"title\": \"Title\",\n",
+			validate: func(t *testing.T, result *AnalysisResult) {
+				assert.Equal(t, "Title", result.Title)
+			},
+		},
+		{
+			name:  "Cut off after escape character",
+			input: `{"summary": "He said \"hello\" and \`,
+			validate: func(t *testing.T, result *AnalysisResult) {
+				assert.Equal(t, `He said "hello" and `, result.Summary)
+			},
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseJSONResponse(tt.input)
+			require.NoError(t, err)
+			assert.True(t, result.Truncated)
+			tt.validate(t, result)
+		})
+	}
+}
+
+func TestParseJSONResponse_StrictNotTruncated(t *testing.T) {
+	result, err := parseJSONResponse(`{"summary": "Complete", "topics": ["a"], "sentiment": "positive"}`)
+	require.NoError(t, err)
+	assert.False(t, result.Truncated)
+}
+
+func TestParseJSONResponse_Unrecoverable(t *testing.T) {
+	for _, input := range []string{`{"summary": "x"]`, `not json at all`, `{invalid json}`} {
+		_, err := parseJSONResponse(input)
+		assert.ErrorIs(t, err, ErrInvalidJSON, input)
+	}
+}
\ No newline at end of file
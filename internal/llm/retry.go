@@ -0,0 +1,46 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RetryingProvider retries calls that fail with ErrLLMUnavailable, waiting
+// with exponential backoff between attempts. Other errors, including context
+// cancellation and ErrEmptyInput, are returned immediately.
+type RetryingProvider struct {
+	provider   Provider
+	maxRetries int
+	backoff    time.Duration
+}
+
+func NewRetryingProvider(provider Provider, maxRetries int, backoff time.Duration) *RetryingProvider {
+	return &RetryingProvider{
+		provider:   provider,
+		maxRetries: maxRetries,
+		backoff:    backoff,
+	}
+}
+
+func (p *RetryingProvider) Analyze(ctx context.Context, text string) (*AnalysisResult, error) {
+	delay := p.backoff
+	
+	for attempt := 0; ; attempt++ {
+		result, err := p.provider.Analyze(ctx, text)
+		if err == nil || !errors.Is(err, ErrLLMUnavailable) || attempt >= p.maxRetries {
+			return result, err
+		}
+		
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+func (p *RetryingProvider) IsAvailable() bool {
+	return p.provider.IsAvailable()
+}
\ No newline at end of file
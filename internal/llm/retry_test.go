@@ -0,0 +1,97 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type scriptedProvider struct {
+	errs  []error
+	calls int
+}
+
+func (p *scriptedProvider) Analyze(ctx context.Context, text string) (*AnalysisResult, error) {
+	p.calls++
+	if p.calls <= len(p.errs) && p.errs[p.calls-1] != nil {
+		return nil, p.errs[p.calls-1]
+	}
+	return &AnalysisResult{Summary: "ok"}, nil
+}
+
+func (p *scriptedProvider) IsAvailable() bool {
+	return true
+}
+
+func TestRetryingProvider_Analyze(t *testing.T) {
+	unavailable := fmt.Errorf("%w: transient", ErrLLMUnavailable)
+	
+	tests := []struct {
+		name        string
+		errs        []error
+		maxRetries  int
+		expectError error
+		calls       int
+	}{
+		{
+			name:       "Transient failure is retried",
+			errs:       []error{unavailable},
+			maxRetries: 2,
+			calls:      2,
+		},
+		{
+			name:        "Gives up after max retries",
+			errs:        []error{unavailable, unavailable, unavailable},
+			maxRetries:  2,
+			expectError: ErrLLMUnavailable,
+			calls:       3,
+		},
+		{
+			name:        "Non-transient error is not retried",
+			errs:        []error{ErrEmptyInput},
+			maxRetries:  2,
+			expectError: ErrEmptyInput,
+			calls:       1,
+		},
+		{
+			name:        "Zero retries calls once",
+			errs:        []error{unavailable},
+			maxRetries:  0,
+			expectError: ErrLLMUnavailable,
+			calls:       1,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &scriptedProvider{errs: tt.errs}
+			provider := NewRetryingProvider(inner, tt.maxRetries, time.Millisecond)
+			
+			result, err := provider.Analyze(context.Background(), "text")
+			if tt.expectError != nil {
+				assert.ErrorIs(t, err, tt.expectError)
+				assert.Nil(t, result)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, "ok", result.Summary)
+			}
+			assert.Equal(t, tt.calls, inner.calls)
+		})
+	}
+}
+
+func TestRetryingProvider_StopsOnCancelledContext(t *testing.T) {
+	inner := &scriptedProvider{errs: []error{ErrLLMUnavailable, ErrLLMUnavailable}}
+	provider := NewRetryingProvider(inner, 5, time.Hour)
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	
+	_, err := provider.Analyze(ctx, "text")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, inner.calls)
+}
\ No newline at end of file
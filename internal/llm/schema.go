@@ -0,0 +1,111 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrSchemaMismatch means the response was valid JSON but a field had the
+// wrong type. It is reported as a *SchemaError naming the field.
+var ErrSchemaMismatch = errors.New("LLM response does not match schema")
+
+type fieldType string
+
+const (
+	fieldString      fieldType = "string"
+	fieldNumber      fieldType = "number"
+	fieldStringArray fieldType = "array of strings"
+)
+
+// analysisSchema is the shape expected from providers. Fields may be missing
+// or null, in which case the usual defaults apply; unknown fields are ignored.
+var analysisSchema = map[string]fieldType{
+	"summary":         fieldString,
+	"title":           fieldString,
+	"topics":          fieldStringArray,
+	"sentiment":       fieldString,
+	"sentiment_score": fieldNumber,
+}
+
+type SchemaError struct {
+	Field    string
+	Expected fieldType
+	Got      string
+}
+
+func (e *SchemaError) Error() string {
+	return fmt.Sprintf("%v: field %q must be %s, got %s", ErrSchemaMismatch, e.Field, e.Expected, e.Got)
+}
+
+func (e *SchemaError) Unwrap() error {
+	return ErrSchemaMismatch
+}
+
+// validateSchema checks a JSON object against the schema, reporting fields in
+// sorted order so the same response always yields the same error.
+func validateSchema(object string, schema map[string]fieldType) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(object), &fields); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+	}
+	
+	names := make([]string, 0, len(schema))
+	for name := range schema {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	
+	for _, name := range names {
+		raw, ok := fields[name]
+		if !ok || jsonKind(raw) == "null" {
+			continue
+		}
+		
+		expected := schema[name]
+		switch expected {
+		case fieldString, fieldNumber:
+			if got := jsonKind(raw); got != string(expected) {
+				return &SchemaError{Field: name, Expected: expected, Got: got}
+			}
+		case fieldStringArray:
+			if got := jsonKind(raw); got != "array" {
+				return &SchemaError{Field: name, Expected: expected, Got: got}
+			}
+			var items []json.RawMessage
+			if err := json.Unmarshal(raw, &items); err != nil {
+				return fmt.Errorf("%w: %v", ErrInvalidJSON, err)
+			}
+			for i, item := range items {
+				if got := jsonKind(item); got != string(fieldString) {
+					return &SchemaError{Field: fmt.Sprintf("%s[%d]", name, i), Expected: fieldString, Got: got}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// jsonKind names the JSON type of a valid encoded value.
+func jsonKind(raw json.RawMessage) string {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 {
+		return "null"
+	}
+	switch raw[0] {
+	case '"':
+		return "string"
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	default:
+		return "number"
+	}
+}
\ No newline at end of file
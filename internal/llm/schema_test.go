@@ -0,0 +1,71 @@
+package llm
+
+import (
+	"errors"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseJSONResponse_SchemaMismatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		field   string
+		message string
+	}{
+		{
+			name:    "Topics as a string",
+			input:   `{"summary": "s", "topics": "ai, ml", "sentiment": "positive"}`,
+			field:   "topics",
+			message: `LLM response does not match schema: field "topics" must be array of strings, got string`,
+		},
+		{
+			name:    "Topic element not a string",
+			input:   `{"summary": "s", "topics": ["ai", 42], "sentiment": "positive"}`,
+			field:   "topics[1]",
+			message: `LLM response does not match schema: field "topics[1]" must be string, got number`,
+		},
+		{
+			name:    "Summary as an object",
+			input:   `{"summary": {"text": "s"}, "topics": ["ai"]}`,
+			field:   "summary",
+			message: `LLM response does not match schema: field "summary" must be string, got object`,
+		},
+		{
+			name:    "Score as a string",
+			input:   `{"summary": "s", "topics": ["ai"], "sentiment_score": "0.5"}`,
+			field:   "sentiment_score",
+			message: `LLM response does not match schema: field "sentiment_score" must be number, got string`,
+		},
+		{
+			name:    "Embedded object",
+			input:   `Here you go: {"summary": "s", "title": true} Hope this helps.`,
+			field:   "title",
+			message: `LLM response does not match schema: field "title" must be string, got boolean`,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := parseJSONResponse(tt.input)
+			require.Error(t, err)
+			assert.Nil(t, result)
+			assert.ErrorIs(t, err, ErrSchemaMismatch)
+			assert.NotErrorIs(t, err, ErrInvalidJSON)
+			
+			var schemaErr *SchemaError
+			require.True(t, errors.As(err, &schemaErr))
+			assert.Equal(t, tt.field, schemaErr.Field)
+			assert.Equal(t, tt.message, err.Error())
+		})
+	}
+}
+
+func TestParseJSONResponse_SchemaAllowsNullAndMissing(t *testing.T) {
+	result, err := parseJSONResponse(`{"summary": null, "topics": null, "extra": 1}`)
+	require.NoError(t, err)
+	assert.Equal(t, "No summary available", result.Summary)
+	assert.Equal(t, []string{"general", "uncategorized", "text"}, result.Topics)
+}
\ No newline at end of file
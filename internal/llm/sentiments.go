@@ -0,0 +1,66 @@
+package llm
+
+import "strings"
+
+var DefaultSentiments = []string{"positive", "neutral", "negative"}
+
+// SentimentSet is the set of labels an analysis may carry. Labels outside the
+// set resolve to "neutral" when the set has it, otherwise to the middle label.
+type SentimentSet struct {
+	labels   map[string]bool
+	ordered  []string
+	fallback string
+}
+
+func NewSentimentSet(labels []string) SentimentSet {
+	cleaned := make([]string, 0, len(labels))
+	for _, label := range labels {
+		if label = strings.ToLower(strings.TrimSpace(label)); label != "" {
+			cleaned = append(cleaned, label)
+		}
+	}
+	if len(cleaned) == 0 {
+		cleaned = DefaultSentiments
+	}
+	
+	set := SentimentSet{
+		labels:   make(map[string]bool, len(cleaned)),
+		ordered:  cleaned,
+		fallback: cleaned[len(cleaned)/2],
+	}
+	for _, label := range cleaned {
+		set.labels[label] = true
+	}
+	if set.labels["neutral"] {
+		set.fallback = "neutral"
+	}
+	
+	return set
+}
+
+func (s SentimentSet) Contains(label string) bool {
+	return s.labels[label]
+}
+
+// Labels returns the labels in configured order.
+func (s SentimentSet) Labels() []string {
+	return s.ordered
+}
+
+// Match returns label, trimmed and lower-cased, when it is in the set and ""
+// otherwise, so a caller can tell a missing or unknown label apart from a
+// real one and pick its own fallback.
+func (s SentimentSet) Match(label string) string {
+	label = strings.ToLower(strings.TrimSpace(label))
+	if s.Contains(label) {
+		return label
+	}
+	return ""
+}
+
+func (s SentimentSet) Resolve(label string) string {
+	if s.Contains(label) {
+		return label
+	}
+	return s.fallback
+}
\ No newline at end of file
@@ -0,0 +1,70 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"log"
+)
+
+// Startup check modes. StartupCheckAvailable only asks IsAvailable, which for
+// most providers checks configuration without calling the API; use
+// StartupCheckAnalyze to also send one short analysis and catch a rejected
+// API key or wrong endpoint.
+const (
+	StartupCheckOff       = "off"
+	StartupCheckAvailable = "available"
+	StartupCheckAnalyze   = "analyze"
+)
+
+const startupProbeText = "Startup check: confirm the language model is reachable."
+
+// StartupCheck probes a provider once at boot so a misconfigured provider is
+// reported before the first request fails. With FailFast an unreachable
+// provider is an error; otherwise it is logged as a warning and the server
+// starts anyway.
+type StartupCheck struct {
+	Mode     string
+	FailFast bool
+	Logger   *log.Logger
+}
+
+// Run probes provider, named name in log messages. It returns an error for an
+// unreachable provider only with FailFast, and for an unknown Mode always.
+func (s StartupCheck) Run(ctx context.Context, name string, provider Provider) error {
+	switch s.Mode {
+	case StartupCheckOff:
+		return nil
+	case "", StartupCheckAvailable, StartupCheckAnalyze:
+	default:
+		return fmt.Errorf("unknown startup check %q, expected %s, %s or %s", s.Mode, StartupCheckOff, StartupCheckAvailable, StartupCheckAnalyze)
+	}
+	
+	err := s.probe(ctx, provider)
+	if err == nil {
+		return nil
+	}
+	
+	err = fmt.Errorf("provider %s failed its startup check: %w", name, err)
+	if s.FailFast {
+		return err
+	}
+	
+	logger := s.Logger
+	if logger == nil {
+		logger = log.Default()
+	}
+	logger.Printf("WARNING: %v; analyses will fail until it recovers", err)
+	return nil
+}
+
+func (s StartupCheck) probe(ctx context.Context, provider Provider) error {
+	if !provider.IsAvailable() {
+		return ErrLLMUnavailable
+	}
+	if s.Mode == StartupCheckAnalyze {
+		if _, err := provider.Analyze(ctx, startupProbeText); err != nil {
+			return fmt.Errorf("%w: test analysis: %v", ErrLLMUnavailable, err)
+		}
+	}
+	return nil
+}
\ No newline at end of file
@@ -0,0 +1,84 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"testing"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type probedProvider struct {
+	available   bool
+	analyzeErr  error
+	analyzeCall int
+}
+
+func (p *probedProvider) Analyze(ctx context.Context, text string) (*AnalysisResult, error) {
+	p.analyzeCall++
+	if p.analyzeErr != nil {
+		return nil, p.analyzeErr
+	}
+	return &AnalysisResult{Summary: "ok"}, nil
+}
+
+func (p *probedProvider) IsAvailable() bool {
+	return p.available
+}
+
+func TestStartupCheck_Run(t *testing.T) {
+	rejected := errors.New("401 invalid API key")
+	
+	tests := []struct {
+		name        string
+		mode        string
+		failFast    bool
+		provider    *probedProvider
+		expectError bool
+		expectWarn  bool
+		analyzed    int
+	}{
+		{"available passes", StartupCheckAvailable, true, &probedProvider{available: true}, false, false, 0},
+		{"default mode is available", "", true, &probedProvider{available: true}, false, false, 0},
+		{"unavailable warns and continues", StartupCheckAvailable, false, &probedProvider{}, false, true, 0},
+		{"unavailable fails fast", StartupCheckAvailable, true, &probedProvider{}, true, false, 0},
+		{"analyze passes", StartupCheckAnalyze, true, &probedProvider{available: true}, false, false, 1},
+		{"analyze error warns and continues", StartupCheckAnalyze, false, &probedProvider{available: true, analyzeErr: rejected}, false, true, 1},
+		{"analyze error fails fast", StartupCheckAnalyze, true, &probedProvider{available: true, analyzeErr: rejected}, true, false, 1},
+		{"analyze skipped when unavailable", StartupCheckAnalyze, true, &probedProvider{}, true, false, 0},
+		{"off never probes", StartupCheckOff, true, &probedProvider{}, false, false, 0},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			check := StartupCheck{Mode: tt.mode, FailFast: tt.failFast, Logger: log.New(&logs, "", 0)}
+			
+			err := check.Run(context.Background(), "gemini", tt.provider)
+			if tt.expectError {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, ErrLLMUnavailable)
+				assert.Contains(t, err.Error(), "gemini")
+			} else {
+				assert.NoError(t, err)
+			}
+			if tt.expectWarn {
+				assert.Contains(t, logs.String(), "WARNING: provider gemini failed its startup check")
+			} else {
+				assert.Empty(t, logs.String())
+			}
+			assert.Equal(t, tt.analyzed, tt.provider.analyzeCall)
+		})
+	}
+}
+
+func TestStartupCheck_UnknownMode(t *testing.T) {
+	provider := &probedProvider{available: true}
+	err := StartupCheck{Mode: "ping"}.Run(context.Background(), "mock", provider)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrLLMUnavailable)
+	assert.Contains(t, err.Error(), `"ping"`)
+}
\ No newline at end of file
@@ -0,0 +1,47 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+// NewHTTPClient returns the client HTTP-backed providers send requests with.
+// Its Timeout covers the whole exchange, including reading the body, so a
+// hung connection gives up after timeout even when the caller's context has
+// no deadline; a sooner context deadline still cancels the request first.
+// A zero timeout means no client limit.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout}
+}
+
+// HTTPClient is the client a provider built from this config should use.
+func (c Config) HTTPClient() *http.Client {
+	return NewHTTPClient(c.RequestTimeout)
+}
+
+// TimeoutProvider bounds every Analyze call to timeout, independently of the
+// deadline on the caller's context. Whichever expires first cancels the call,
+// and both surface as context.DeadlineExceeded.
+type TimeoutProvider struct {
+	provider Provider
+	timeout  time.Duration
+}
+
+func NewTimeoutProvider(provider Provider, timeout time.Duration) *TimeoutProvider {
+	return &TimeoutProvider{
+		provider: provider,
+		timeout:  timeout,
+	}
+}
+
+func (p *TimeoutProvider) Analyze(ctx context.Context, text string) (*AnalysisResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	
+	return p.provider.Analyze(ctx, text)
+}
+
+func (p *TimeoutProvider) IsAvailable() bool {
+	return p.provider.IsAvailable()
+}
\ No newline at end of file
@@ -0,0 +1,103 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowServer holds every request until the client gives up or a second has
+// passed.
+func slowServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestNewHTTPClient_TimeoutFires(t *testing.T) {
+	server := slowServer(t)
+	client := Config{RequestTimeout: 50 * time.Millisecond}.HTTPClient()
+	
+	start := time.Now()
+	_, err := client.Get(server.URL)
+	require.Error(t, err)
+	
+	var netErr net.Error
+	require.True(t, errors.As(err, &netErr), err)
+	assert.True(t, netErr.Timeout())
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestNewHTTPClient_ShorterContextWins(t *testing.T) {
+	server := slowServer(t)
+	client := NewHTTPClient(5 * time.Second)
+	
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	
+	start := time.Now()
+	_, err = client.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+// blockingProvider waits for its context and reports how long that took.
+type blockingProvider struct {
+	waited time.Duration
+}
+
+func (p *blockingProvider) Analyze(ctx context.Context, text string) (*AnalysisResult, error) {
+	start := time.Now()
+	<-ctx.Done()
+	p.waited = time.Since(start)
+	return nil, ctx.Err()
+}
+
+func (p *blockingProvider) IsAvailable() bool {
+	return true
+}
+
+func TestTimeoutProvider_ShorterDeadlineWins(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		deadline time.Duration
+	}{
+		{"provider timeout first", 20 * time.Millisecond, 5 * time.Second},
+		{"context deadline first", 5 * time.Second, 20 * time.Millisecond},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &blockingProvider{}
+			provider := NewTimeoutProvider(inner, tt.timeout)
+			
+			ctx, cancel := context.WithTimeout(context.Background(), tt.deadline)
+			defer cancel()
+			
+			_, err := provider.Analyze(ctx, "text")
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.Less(t, inner.waited, time.Second)
+		})
+	}
+}
+
+func TestNewProvider_RequestTimeout(t *testing.T) {
+	provider, err := NewProvider(Config{Provider: "mock", RequestTimeout: time.Second})
+	require.NoError(t, err)
+	assert.IsType(t, &TimeoutProvider{}, provider)
+}
\ No newline at end of file
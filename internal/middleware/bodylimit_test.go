@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestMaxBodySize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	
+	r := gin.New()
+	r.Use(MaxBodySize(64))
+	r.POST("/analyze", func(c *gin.Context) {
+		body, err := io.ReadAll(c.Request.Body)
+		require.NoError(t, err)
+		c.String(http.StatusOK, string(body))
+	})
+	
+	tests := []struct {
+		name       string
+		body       string
+		dropLength bool
+		status     int
+	}{
+		{
+			name:   "Body within limit",
+			body:   `{"text": "short"}`,
+			status: http.StatusOK,
+		},
+		{
+			name:   "Oversized body with Content-Length",
+			body:   `{"text": "` + strings.Repeat("a", 100) + `"}`,
+			status: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:       "Oversized body without Content-Length",
+			body:       `{"text": "` + strings.Repeat("a", 100) + `"}`,
+			dropLength: true,
+			status:     http.StatusRequestEntityTooLarge,
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/analyze", strings.NewReader(tt.body))
+			if tt.dropLength {
+				req.ContentLength = -1
+			}
+			
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			assert.Equal(t, tt.status, w.Code)
+			
+			if tt.status == http.StatusOK {
+				assert.Equal(t, tt.body, w.Body.String())
+				return
+			}
+			
+			var envelope models.Envelope
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+			require.NotNil(t, envelope.Error)
+			assert.Equal(t, models.CodePayloadTooLarge, envelope.Error.Code)
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+// ContentTypes rejects requests whose Content-Type is not one of allowed
+// with 415, before binding can misread the body. Parameters such as charset
+// and boundary are ignored when matching, and a missing header is rejected.
+func ContentTypes(allowed ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		mediaType, _, err := mime.ParseMediaType(c.GetHeader("Content-Type"))
+		if err == nil {
+			for _, candidate := range allowed {
+				if strings.EqualFold(mediaType, candidate) {
+					c.Next()
+					return
+				}
+			}
+		}
+		
+		response.AbortError(c, http.StatusUnsupportedMediaType, models.CodeUnsupportedMedia,
+			fmt.Errorf("content type %q is not accepted, expected %s", c.GetHeader("Content-Type"), strings.Join(allowed, " or ")))
+	}
+}
\ No newline at end of file
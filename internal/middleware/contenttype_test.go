@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestContentTypes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	
+	r := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.POST("/analyze", ContentTypes("application/json"), ok)
+	r.POST("/batch-analyze", ContentTypes("application/json"), ok)
+	r.POST("/batch-analyze/file", ContentTypes("multipart/form-data"), ok)
+	
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		status      int
+	}{
+		{"JSON", "/analyze", "application/json", http.StatusOK},
+		{"JSON with charset", "/analyze", "application/json; charset=utf-8", http.StatusOK},
+		{"Upper case", "/batch-analyze", "Application/JSON", http.StatusOK},
+		{"Plain text", "/analyze", "text/plain", http.StatusUnsupportedMediaType},
+		{"Form encoded", "/batch-analyze", "application/x-www-form-urlencoded", http.StatusUnsupportedMediaType},
+		{"Missing", "/analyze", "", http.StatusUnsupportedMediaType},
+		{"Malformed", "/analyze", "application/json; =", http.StatusUnsupportedMediaType},
+		{"Multipart upload", "/batch-analyze/file", "multipart/form-data; boundary=xyz", http.StatusOK},
+		{"JSON to file endpoint", "/batch-analyze/file", "application/json", http.StatusUnsupportedMediaType},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(`{"text": "hello"}`))
+			if tt.contentType != "" {
+				req.Header.Set("Content-Type", tt.contentType)
+			}
+			
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			require.Equal(t, tt.status, w.Code)
+			
+			if tt.status == http.StatusOK {
+				return
+			}
+			var envelope models.Envelope
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+			require.NotNil(t, envelope.Error)
+			assert.Equal(t, models.CodeUnsupportedMedia, envelope.Error.Code)
+			assert.False(t, envelope.Error.Retryable)
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,92 @@
+package middleware
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+const (
+	// FaultHeader forces a fault on a single request. Its value is the
+	// status to respond with, or "true" for FaultConfig.Status.
+	FaultHeader = "X-Inject-Fault"
+	// FaultInjectedHeader marks responses produced by Faults.
+	FaultInjectedHeader = "X-Fault-Injected"
+)
+
+// faultCodes gives injected faults the code the real failure would carry, so
+// clients see the same retryable hints.
+var faultCodes = map[int]models.ErrorCode{
+	http.StatusBadRequest:            models.CodeInvalidRequest,
+	http.StatusNotFound:              models.CodeNotFound,
+	http.StatusRequestEntityTooLarge: models.CodePayloadTooLarge,
+	http.StatusUnsupportedMediaType:  models.CodeUnsupportedMedia,
+	http.StatusBadGateway:            models.CodeLLMBadResponse,
+	http.StatusServiceUnavailable:    models.CodeLLMUnavailable,
+	http.StatusGatewayTimeout:        models.CodeTimeout,
+}
+
+type FaultConfig struct {
+	// Rate is the fraction of requests, from 0 to 1, that fail at random.
+	Rate float64
+	// Status is the status random faults respond with; 503 when unset.
+	Status int
+	// Random replaces rand.Float64, so tests can use a seeded source.
+	Random func() float64
+}
+
+// Faults fails requests before they reach a handler, for exercising client
+// retry and error handling in integration tests. It must never be installed
+// in production.
+func Faults(cfg FaultConfig) gin.HandlerFunc {
+	if cfg.Status == 0 {
+		cfg.Status = http.StatusServiceUnavailable
+	}
+	random := cfg.Random
+	if random == nil {
+		random = rand.Float64
+	}
+	var mu sync.Mutex
+	
+	return func(c *gin.Context) {
+		status, forced := forcedFault(c.GetHeader(FaultHeader), cfg.Status)
+		if !forced {
+			mu.Lock()
+			roll := random()
+			mu.Unlock()
+			if roll >= cfg.Rate {
+				c.Next()
+				return
+			}
+			status = cfg.Status
+		}
+		
+		code, ok := faultCodes[status]
+		if !ok {
+			code = models.CodeInjectedFault
+		}
+		
+		c.Header(FaultInjectedHeader, "true")
+		response.AbortError(c, status, code, fmt.Errorf("fault injected with status %d", status))
+	}
+}
+
+func forcedFault(value string, fallback int) (int, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if forced, err := strconv.ParseBool(value); err == nil {
+		return fallback, forced
+	}
+	status, err := strconv.Atoi(value)
+	if err != nil || status < 400 || status > 599 {
+		return 0, false
+	}
+	return status, true
+}
\ No newline at end of file
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func newFaultRouter(cfg FaultConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	
+	r := gin.New()
+	r.Use(Faults(cfg))
+	r.GET("/ok", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	return r
+}
+
+func requestFault(r http.Handler, header string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+	if header != "" {
+		req.Header.Set(FaultHeader, header)
+	}
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestFaults_Rate(t *testing.T) {
+	for _, rate := range []float64{0, 0.1, 0.25, 1} {
+		r := newFaultRouter(FaultConfig{Rate: rate, Random: rand.New(rand.NewSource(1)).Float64})
+		
+		const requests = 2000
+		injected := 0
+		for i := 0; i < requests; i++ {
+			w := requestFault(r, "")
+			if w.Header().Get(FaultInjectedHeader) == "true" {
+				assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+				injected++
+				continue
+			}
+			assert.Equal(t, http.StatusOK, w.Code)
+		}
+		
+		assert.InDelta(t, rate, float64(injected)/requests, 0.03, "rate %v", rate)
+	}
+}
+
+func TestFaults_Header(t *testing.T) {
+	r := newFaultRouter(FaultConfig{Status: http.StatusGatewayTimeout})
+	
+	tests := []struct {
+		header    string
+		status    int
+		code      models.ErrorCode
+		retryable bool
+	}{
+		{"", http.StatusOK, "", false},
+		{"false", http.StatusOK, "", false},
+		{"not-a-status", http.StatusOK, "", false},
+		{"200", http.StatusOK, "", false},
+		{"true", http.StatusGatewayTimeout, models.CodeTimeout, true},
+		{"503", http.StatusServiceUnavailable, models.CodeLLMUnavailable, true},
+		{"400", http.StatusBadRequest, models.CodeInvalidRequest, false},
+		{"500", http.StatusInternalServerError, models.CodeInjectedFault, false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			w := requestFault(r, tt.header)
+			require.Equal(t, tt.status, w.Code)
+			if tt.code == "" {
+				assert.Empty(t, w.Header().Get(FaultInjectedHeader))
+				assert.Equal(t, "ok", w.Body.String())
+				return
+			}
+			
+			assert.Equal(t, "true", w.Header().Get(FaultInjectedHeader))
+			var envelope models.Envelope
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+			require.NotNil(t, envelope.Error)
+			assert.Equal(t, tt.code, envelope.Error.Code)
+			assert.Equal(t, tt.retryable, envelope.Error.Retryable)
+		})
+	}
+}
\ No newline at end of file
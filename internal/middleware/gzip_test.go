@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newGzipRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	
+	r := gin.New()
+	r.Use(Gzip(1024))
+	r.GET("/search", func(c *gin.Context) {
+		results := make([]gin.H, 200)
+		for i := range results {
+			results[i] = gin.H{"summary": "This text discusses a fairly repetitive search result"}
+		}
+		c.JSON(http.StatusOK, gin.H{"results": results, "count": len(results)})
+	})
+	r.GET("/small", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+	r.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "text/event-stream")
+		c.SSEvent("progress", strings.Repeat("x", 2048))
+		c.Writer.Flush()
+	})
+	
+	return r
+}
+
+func TestGzip(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		acceptGzip bool
+		compressed bool
+	}{
+		{
+			name:       "Large response compressed when requested",
+			path:       "/search",
+			acceptGzip: true,
+			compressed: true,
+		},
+		{
+			name:       "Large response untouched without Accept-Encoding",
+			path:       "/search",
+			acceptGzip: false,
+			compressed: false,
+		},
+		{
+			name:       "Small response below threshold untouched",
+			path:       "/small",
+			acceptGzip: true,
+			compressed: false,
+		},
+		{
+			name:       "SSE stream never compressed",
+			path:       "/stream",
+			acceptGzip: true,
+			compressed: false,
+		},
+	}
+	
+	r := newGzipRouter()
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.acceptGzip {
+				req.Header.Set("Accept-Encoding", "gzip, deflate")
+			}
+			
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			require.Equal(t, http.StatusOK, w.Code)
+			
+			if !tt.compressed {
+				assert.Empty(t, w.Header().Get("Content-Encoding"))
+				assert.NotEmpty(t, w.Body.String())
+				return
+			}
+			
+			assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+			
+			gz, err := gzip.NewReader(w.Body)
+			require.NoError(t, err)
+			body, err := io.ReadAll(gz)
+			require.NoError(t, err)
+			assert.Contains(t, string(body), `"count":200`)
+		})
+	}
+}
+
+func TestAcceptsGzip(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected bool
+	}{
+		{"gzip", true},
+		{"gzip, deflate", true},
+		{"deflate, GZIP;q=0.5", true},
+		{"gzip; q=1.0", true},
+		{"", false},
+		{"deflate, br", false},
+		{"gzip;q=0", false},
+		{"gzip; q=0.000, deflate", false},
+		{"x-gzip", false},
+		{"gzipped", false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.expected, acceptsGzip(tt.header))
+		})
+	}
+}
+
+func TestGzip_RefusedWithZeroQuality(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/search", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0, deflate")
+	
+	w := httptest.NewRecorder()
+	newGzipRouter().ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Contains(t, w.Body.String(), `"count":200`)
+}
\ No newline at end of file
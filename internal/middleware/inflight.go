@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+// MaxInFlight caps how many requests the server handles at once, across all
+// clients. A request over the limit is turned away immediately with 503
+// SERVER_BUSY and a Retry-After hint rather than queued, so a burst can't
+// exhaust memory or the LLM quota. A limit of zero or less disables it.
+func MaxInFlight(limit int, retryAfter time.Duration) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	
+	slots := make(chan struct{}, limit)
+	return func(c *gin.Context) {
+		select {
+		case slots <- struct{}{}:
+		default:
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			response.AbortError(c, http.StatusServiceUnavailable, models.CodeServerBusy, fmt.Errorf("%d requests already in flight", limit))
+			return
+		}
+		defer func() { <-slots }()
+		
+		c.Next()
+	}
+}
\ No newline at end of file
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestMaxInFlight(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	
+	const limit = 3
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	
+	r := gin.New()
+	r.Use(MaxInFlight(limit, 2*time.Second))
+	r.POST("/analyze", func(c *gin.Context) {
+		entered <- struct{}{}
+		<-release
+		c.String(http.StatusOK, "ok")
+	})
+	
+	serve := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/analyze", nil))
+		return w
+	}
+	
+	var wg sync.WaitGroup
+	held := make([]*httptest.ResponseRecorder, limit)
+	for i := range held {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			held[i] = serve()
+		}(i)
+		<-entered
+	}
+	
+	for i := 0; i < 5; i++ {
+		w := serve()
+		require.Equal(t, http.StatusServiceUnavailable, w.Code)
+		assert.Equal(t, "2", w.Header().Get("Retry-After"))
+		
+		var envelope models.Envelope
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+		require.NotNil(t, envelope.Error)
+		assert.Equal(t, models.CodeServerBusy, envelope.Error.Code)
+		assert.True(t, envelope.Error.Retryable)
+	}
+	
+	close(release)
+	wg.Wait()
+	for _, w := range held {
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	
+	// Finished requests give their slots back.
+	go func() { <-entered }()
+	assert.Equal(t, http.StatusOK, serve().Code)
+}
+
+func TestMaxInFlight_Disabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	
+	r := gin.New()
+	r.Use(MaxInFlight(0, time.Second))
+	r.GET("/version", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
\ No newline at end of file
@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// TrustProxies makes c.ClientIP, and with it the request log, report the
+// real client address. X-Forwarded-For is only honoured when the direct peer
+// is one of proxies (IPs or CIDRs); the chain is then read right to left and
+// the first address that is not itself a trusted proxy wins. With no proxies
+// the header is ignored and the peer address is used as is.
+func TrustProxies(r *gin.Engine, proxies []string) error {
+	r.ForwardedByClientIP = true
+	r.RemoteIPHeaders = []string{"X-Forwarded-For"}
+	return r.SetTrustedProxies(proxies)
+}
\ No newline at end of file
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTrustProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	
+	tests := []struct {
+		name       string
+		proxies    []string
+		remoteAddr string
+		forwarded  string
+		expected   string
+	}{
+		{
+			name:       "No proxies ignores forwarded header",
+			remoteAddr: "10.0.0.1:4321",
+			forwarded:  "203.0.113.7",
+			expected:   "10.0.0.1",
+		},
+		{
+			name:       "Trusted proxy without header",
+			proxies:    []string{"10.0.0.1"},
+			remoteAddr: "10.0.0.1:4321",
+			expected:   "10.0.0.1",
+		},
+		{
+			name:       "Trusted proxy forwards client",
+			proxies:    []string{"10.0.0.1"},
+			remoteAddr: "10.0.0.1:4321",
+			forwarded:  "203.0.113.7",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "Untrusted peer cannot spoof header",
+			proxies:    []string{"10.0.0.1"},
+			remoteAddr: "198.51.100.2:4321",
+			forwarded:  "203.0.113.7",
+			expected:   "198.51.100.2",
+		},
+		{
+			name:       "Proxy chain within trusted CIDR",
+			proxies:    []string{"10.0.0.0/8"},
+			remoteAddr: "10.0.0.1:4321",
+			forwarded:  "203.0.113.7, 10.0.0.2",
+			expected:   "203.0.113.7",
+		},
+		{
+			name:       "Client-supplied prefix is skipped",
+			proxies:    []string{"10.0.0.1"},
+			remoteAddr: "10.0.0.1:4321",
+			forwarded:  "192.0.2.99, 203.0.113.7",
+			expected:   "203.0.113.7",
+		},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			require.NoError(t, TrustProxies(r, tt.proxies))
+			r.GET("/ip", func(c *gin.Context) {
+				c.String(http.StatusOK, c.ClientIP())
+			})
+			
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				req.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			
+			assert.Equal(t, tt.expected, w.Body.String())
+		})
+	}
+}
+
+func TestTrustProxies_InvalidProxy(t *testing.T) {
+	assert.Error(t, TrustProxies(gin.New(), []string{"not-an-ip"}))
+}
\ No newline at end of file
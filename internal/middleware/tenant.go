@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/database"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"github.com/user/llm-knowledge-extractor/internal/response"
+)
+
+const TenantHeader = "X-Tenant-ID"
+
+// Tenant routes each request to the database of the tenant named in the
+// X-Tenant-ID header, so handlers only ever see that tenant's analyses.
+// Requests without a tenant are rejected with 400 TENANT_REQUIRED, and
+// requests for a tenant that was never provisioned with 403 UNKNOWN_TENANT.
+func Tenant(tenants *database.Tenants) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := strings.TrimSpace(c.GetHeader(TenantHeader))
+		if id == "" {
+			response.AbortError(c, http.StatusBadRequest, models.CodeTenantRequired, fmt.Errorf("the %s header is required", TenantHeader))
+			return
+		}
+		
+		db, err := tenants.Get(id)
+		switch {
+		case errors.Is(err, database.ErrInvalidTenant):
+			response.AbortError(c, http.StatusBadRequest, models.CodeInvalidRequest, err)
+			return
+		case errors.Is(err, database.ErrUnknownTenant):
+			response.AbortError(c, http.StatusForbidden, models.CodeUnknownTenant, err)
+			return
+		case err != nil:
+			response.AbortError(c, http.StatusInternalServerError, models.CodeDBError, err)
+			return
+		}
+		
+		c.Request = c.Request.WithContext(database.WithContext(c.Request.Context(), db))
+		c.Next()
+	}
+}
\ No newline at end of file
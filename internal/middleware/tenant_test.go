@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/database"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func TestTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	
+	tenants, err := database.NewTenants(t.TempDir(), database.Options{})
+	require.NoError(t, err)
+	t.Cleanup(func() { tenants.Close() })
+	_, err = tenants.Provision("acme")
+	require.NoError(t, err)
+	
+	r := gin.New()
+	r.Use(Tenant(tenants))
+	r.GET("/search", func(c *gin.Context) {
+		_, ok := database.FromContext(c.Request.Context())
+		assert.True(t, ok)
+		c.String(http.StatusOK, "ok")
+	})
+	
+	tests := []struct {
+		name   string
+		tenant string
+		status int
+		code   models.ErrorCode
+	}{
+		{name: "Valid tenant", tenant: "acme", status: http.StatusOK},
+		{name: "Missing tenant", status: http.StatusBadRequest, code: models.CodeTenantRequired},
+		{name: "Path in tenant", tenant: "../acme", status: http.StatusBadRequest, code: models.CodeInvalidRequest},
+		{name: "Unknown tenant", tenant: "acme-typo", status: http.StatusForbidden, code: models.CodeUnknownTenant},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/search", nil)
+			if tt.tenant != "" {
+				req.Header.Set(TenantHeader, tt.tenant)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+			
+			assert.Equal(t, tt.status, w.Code)
+			if tt.code != "" {
+				var envelope models.Envelope
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope))
+				require.NotNil(t, envelope.Error)
+				assert.Equal(t, tt.code, envelope.Error.Code)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,67 @@
+package models
+
+// ErrorCode is the machine-readable code carried by every error response.
+type ErrorCode string
+
+const (
+	CodeInvalidRequest       ErrorCode = "INVALID_REQUEST"
+	CodeEmptyInput           ErrorCode = "EMPTY_INPUT"
+	CodeTextTooLong          ErrorCode = "TEXT_TOO_LONG"
+	CodeInvalidEncoding      ErrorCode = "INVALID_ENCODING"
+	CodeBatchSizeExceeded    ErrorCode = "BATCH_SIZE_EXCEEDED"
+	CodeBatchBudgetExceeded  ErrorCode = "BATCH_BUDGET_EXCEEDED"
+	CodePayloadTooLarge      ErrorCode = "PAYLOAD_TOO_LARGE"
+	CodeUnsupportedMedia     ErrorCode = "UNSUPPORTED_MEDIA_TYPE"
+	CodeConfirmationRequired ErrorCode = "CONFIRMATION_REQUIRED"
+	CodeFilterRequired       ErrorCode = "FILTER_REQUIRED"
+	CodeTenantRequired       ErrorCode = "TENANT_REQUIRED"
+	CodeUnknownTenant        ErrorCode = "UNKNOWN_TENANT"
+	CodeNotFound             ErrorCode = "NOT_FOUND"
+	CodeLLMUnavailable       ErrorCode = "LLM_UNAVAILABLE"
+	CodeLLMBadResponse       ErrorCode = "LLM_BAD_RESPONSE"
+	CodeTimeout              ErrorCode = "TIMEOUT"
+	CodeDBError              ErrorCode = "DB_ERROR"
+	CodeRenderError          ErrorCode = "RENDER_ERROR"
+	CodeInjectedFault        ErrorCode = "INJECTED_FAULT"
+	CodeHookFailed           ErrorCode = "HOOK_FAILED"
+	CodeServerBusy           ErrorCode = "SERVER_BUSY"
+)
+
+var errorMessages = map[ErrorCode]string{
+	CodeInvalidRequest:       "Invalid request",
+	CodeEmptyInput:           "Text cannot be empty",
+	CodeTextTooLong:          "Text is too long",
+	CodeInvalidEncoding:      "Text is not valid UTF-8",
+	CodeBatchSizeExceeded:    "Too many texts in batch",
+	CodeBatchBudgetExceeded:  "Batch text is too long in total",
+	CodePayloadTooLarge:      "Request body too large",
+	CodeUnsupportedMedia:     "Unsupported content type",
+	CodeConfirmationRequired: "Confirmation required",
+	CodeFilterRequired:       "At least one filter is required",
+	CodeTenantRequired:       "Tenant ID is required",
+	CodeUnknownTenant:        "Unknown tenant",
+	CodeNotFound:             "Analysis not found",
+	CodeLLMUnavailable:       "LLM service unavailable",
+	CodeLLMBadResponse:       "LLM request failed",
+	CodeTimeout:              "LLM request timed out",
+	CodeDBError:              "Database error",
+	CodeRenderError:          "Failed to render response",
+	CodeInjectedFault:        "Injected fault",
+	CodeHookFailed:           "Analysis hook failed",
+	CodeServerBusy:           "Server is busy",
+}
+
+// Message is the human-readable summary sent with the code. Specifics go in
+// the response details.
+func (c ErrorCode) Message() string {
+	if message, ok := errorMessages[c]; ok {
+		return message
+	}
+	return "Request failed"
+}
+
+// Retryable reports whether the same request may succeed if sent again
+// later; every other error needs a different request.
+func (c ErrorCode) Retryable() bool {
+	return c == CodeLLMUnavailable || c == CodeTimeout || c == CodeServerBusy
+}
\ No newline at end of file
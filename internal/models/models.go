@@ -6,7 +6,7 @@ import (
 
 type TextAnalysis struct {
 	ID           string                 `json:"id" db:"id"`
-	Text         string                 `json:"text" db:"text"`
+	Text         string                 `json:"text,omitempty" db:"text"`
 	Summary      string                 `json:"summary" db:"summary"`
 	Metadata     map[string]interface{} `json:"metadata" db:"metadata"`
 	Confidence   float64                `json:"confidence" db:"confidence"`
@@ -14,31 +14,217 @@ type TextAnalysis struct {
 	ProcessingMS int64                  `json:"processing_ms" db:"processing_ms"`
 }
 
+func (a *TextAnalysis) MetadataStrings(key string) []string {
+	switch values := a.Metadata[key].(type) {
+	case []string:
+		return values
+	case []interface{}:
+		result := make([]string, 0, len(values))
+		for _, value := range values {
+			if s, ok := value.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	default:
+		return nil
+	}
+}
+
+type AnalysisVersion struct {
+	AnalysisID string                 `json:"analysis_id"`
+	Version    int                    `json:"version"`
+	Summary    string                 `json:"summary"`
+	Metadata   map[string]interface{} `json:"metadata"`
+	Confidence float64                `json:"confidence"`
+	CreatedAt  time.Time              `json:"created_at"`
+}
+
+// Note is a reviewer's free-text annotation on an analysis. Notes are kept
+// apart from the analysis and never change its metadata.
+type Note struct {
+	ID         int64     `json:"id"`
+	AnalysisID string    `json:"analysis_id"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type NoteRequest struct {
+	Note string `json:"note" binding:"required,max=5000"`
+}
+
+// Correction records an analyst's fix to a metadata field the LLM got wrong,
+// keeping the original value so the model can be evaluated against it.
+type Correction struct {
+	ID          int64     `json:"id"`
+	AnalysisID  string    `json:"analysis_id"`
+	Field       string    `json:"field"`
+	Original    string    `json:"original"`
+	Corrected   string    `json:"corrected"`
+	CorrectedBy string    `json:"corrected_by"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type SentimentCorrectionRequest struct {
+	Sentiment   string `json:"sentiment" binding:"required"`
+	CorrectedBy string `json:"corrected_by" binding:"required,max=100"`
+}
+
+// AnalysisMetadata is the typed form of the well-known metadata fields.
+// Stored metadata is a free-form map, so keys not listed here (hook output,
+// chunk details, ...) are only available from the map.
 type AnalysisMetadata struct {
-	Title     string   `json:"title"`
-	Topics    []string `json:"topics"`
-	Sentiment string   `json:"sentiment"`
-	Keywords  []string `json:"keywords"`
+	Title          string   `json:"title"`
+	Topics         []string `json:"topics"`
+	Sentiment      string   `json:"sentiment"`
+	SentimentScore float64  `json:"sentiment_score"`
+	Keywords       []string `json:"keywords"`
+	Language       string   `json:"language"`
+	Tags           []string `json:"tags,omitempty"`
+	Provider       string   `json:"provider,omitempty"`
+	Model          string   `json:"model,omitempty"`
+	NodeID         string   `json:"node_id,omitempty"`
+	LLMMS          int64    `json:"llm_ms"`
+	KeywordMS      int64    `json:"keyword_ms"`
+	SchemaVersion  int      `json:"schema_version"`
+	Corrected      bool     `json:"corrected,omitempty"`
+}
+
+// TypedAnalysis is a stored analysis with its metadata decoded into
+// AnalysisMetadata.
+type TypedAnalysis struct {
+	ID           string           `json:"id"`
+	Text         string           `json:"text,omitempty"`
+	Summary      string           `json:"summary"`
+	Metadata     AnalysisMetadata `json:"metadata"`
+	Confidence   float64          `json:"confidence"`
+	CreatedAt    time.Time        `json:"created_at"`
+	ProcessingMS int64            `json:"processing_ms"`
 }
 
 type AnalyzeRequest struct {
-	Text string `json:"text" binding:"required,min=1"`
+	Text             string   `json:"text"`
+	Tags             []string `json:"tags" binding:"max=20,dive,min=1,max=50"`
+	KeywordMinLength int      `json:"keyword_min_length,omitempty" binding:"omitempty,min=1,max=50"`
+	Include          []string `json:"include,omitempty" binding:"omitempty,dive,oneof=summary title topics sentiment keywords language"`
+	Provider         string   `json:"provider,omitempty"`
+}
+
+type KeywordsRequest struct {
+	Text  string `json:"text"`
+	Count int    `json:"count"`
+}
+
+type KeywordsResponse struct {
+	Keywords []string `json:"keywords"`
+	Language string   `json:"language"`
 }
 
+type ValidateRequest struct {
+	Text string `json:"text"`
+}
+
+// ValidationReport describes how /analyze would treat a text without
+// analyzing it. Each issue carries the error code the text would be rejected
+// with; Valid means there are none. Characters counts the text after
+// normalization.
+type ValidationReport struct {
+	Valid         bool              `json:"valid"`
+	Characters    int               `json:"characters"`
+	MaxCharacters int               `json:"max_characters"`
+	Language      string            `json:"language,omitempty"`
+	Sanitized     bool              `json:"sanitized,omitempty"`
+	Issues        []ValidationIssue `json:"issues"`
+}
+
+type ValidationIssue struct {
+	Code    ErrorCode `json:"code"`
+	Details string    `json:"details"`
+}
+
+type SummarizeRequest struct {
+	Text string `json:"text"`
+}
+
+type SummarizeResponse struct {
+	Summary string `json:"summary"`
+	Title   string `json:"title"`
+}
+
+// BatchAnalyzeRequest is the body of the batch endpoints. Dedupe, honored by
+// POST /batch-analyze, analyzes repeated texts once and gives every repeat
+// the same result.
 type BatchAnalyzeRequest struct {
-	Texts []string `json:"texts" binding:"required,min=1,dive,min=1"`
+	Texts  []string `json:"texts" binding:"required,min=1"`
+	Dedupe bool     `json:"dedupe,omitempty"`
 }
 
 type AnalyzeResponse struct {
-	ID         string                 `json:"id"`
-	Summary    string                 `json:"summary"`
-	Metadata   map[string]interface{} `json:"metadata"`
-	Confidence float64                `json:"confidence"`
+	ID                string                 `json:"id"`
+	Text              string                 `json:"text,omitempty"`
+	Summary           string                 `json:"summary"`
+	Metadata          map[string]interface{} `json:"metadata"`
+	Confidence        float64                `json:"confidence"`
+	ProcessingMS      int64                  `json:"processing_ms"`
+	MetadataTruncated bool                   `json:"metadata_truncated,omitempty"`
+	LowConfidence     bool                   `json:"low_confidence,omitempty"`
+	ConfidenceWarning string                 `json:"confidence_warning,omitempty"`
+	Persisted         bool                   `json:"persisted"`
+}
+
+type ChunkedAnalyzeRequest struct {
+	Chunks []string `json:"chunks" binding:"required,min=1"`
+	Tags   []string `json:"tags" binding:"max=20,dive,min=1,max=50"`
 }
 
+// ChunkedAnalyzeResponse is the aggregated analysis plus the IDs of the
+// per-chunk analyses it was built from, in chunk order.
+type ChunkedAnalyzeResponse struct {
+	AnalyzeResponse
+	ChunkIDs []string `json:"chunk_ids"`
+}
+
+// BatchAnalyzeResponse lists at most BATCH_MAX_FAILURES failures in Failed,
+// setting Truncated when there were more; FailedIndices always holds the
+// index of every failure, so a retry can cover the ones left out.
 type BatchAnalyzeResponse struct {
-	Results []AnalyzeResponse `json:"results"`
-	Failed  []BatchError      `json:"failed,omitempty"`
+	Results       []AnalyzeResponse `json:"results"`
+	Failed        []BatchError      `json:"failed,omitempty"`
+	FailedIndices []int             `json:"failed_indices,omitempty"`
+	SuccessCount  int               `json:"success_count"`
+	FailedCount   int               `json:"failed_count"`
+	Truncated     bool              `json:"truncated,omitempty"`
+}
+
+// BatchFileResponse keys results by the 1-based line on which each document
+// starts in the uploaded file; BatchError.Index holds that line too.
+type BatchFileResponse struct {
+	Results      map[int]AnalyzeResponse `json:"results"`
+	Failed       []BatchError            `json:"failed,omitempty"`
+	SuccessCount int                     `json:"success_count"`
+	FailedCount  int                     `json:"failed_count"`
+}
+
+type BatchRetryRequest struct {
+	Texts    []string             `json:"texts" binding:"required,min=1"`
+	Previous BatchAnalyzeResponse `json:"previous"`
+}
+
+// ImportQuery picks what POST /import does with an analysis whose ID is
+// already stored: report it as failed ("error", the default) or skip it.
+type ImportQuery struct {
+	OnDuplicate string `form:"on_duplicate" binding:"omitempty,oneof=error skip"`
+}
+
+// ImportResponse lists the IDs imported and skipped; Failed uses the index
+// of each rejected analysis in the request array.
+type ImportResponse struct {
+	Imported      []string     `json:"imported"`
+	Skipped       []string     `json:"skipped,omitempty"`
+	Failed        []BatchError `json:"failed,omitempty"`
+	ImportedCount int          `json:"imported_count"`
+	SkippedCount  int          `json:"skipped_count"`
+	FailedCount   int          `json:"failed_count"`
 }
 
 type BatchError struct {
@@ -46,15 +232,141 @@ type BatchError struct {
 	Error string `json:"error"`
 }
 
+type SearchFilter struct {
+	Topic             string    `form:"topic"`
+	Keyword           string    `form:"keyword"`
+	Tags              []string  `form:"tag"`
+	From              time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To                time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	MinSentimentScore *float64  `form:"min_sentiment_score" binding:"omitempty,min=-1,max=1"`
+	MaxSentimentScore *float64  `form:"max_sentiment_score" binding:"omitempty,min=-1,max=1"`
+	Sentiment         string    `form:"sentiment"`
+	MinConfidence     *float64  `form:"min_confidence" binding:"omitempty,min=0,max=1"`
+	MaxConfidence     *float64  `form:"max_confidence" binding:"omitempty,min=0,max=1"`
+	Provider          string    `form:"provider"`
+}
+
+func (f SearchFilter) IsEmpty() bool {
+	return f.Topic == "" && f.Keyword == "" && len(f.Tags) == 0 && f.From.IsZero() && f.To.IsZero() &&
+		f.MinSentimentScore == nil && f.MaxSentimentScore == nil && f.Sentiment == "" &&
+		f.MinConfidence == nil && f.MaxConfidence == nil && f.Provider == ""
+}
+
 type SearchQuery struct {
-	Topic   string `form:"topic"`
-	Keyword string `form:"keyword"`
-	Limit   int    `form:"limit,default=50"`
-	Offset  int    `form:"offset,default=0"`
+	SearchFilter
+	Limit     int    `form:"limit"`
+	Offset    int    `form:"offset,default=0"`
+	Cursor    string `form:"cursor"`
+	Facets    bool   `form:"facets"`
+	Highlight bool   `form:"highlight"`
+}
+
+// SearchHighlight is an excerpt of the field where a keyword search matched,
+// "text" or "summary", with the matches wrapped in <mark> tags. Matches
+// counts every match in that field, including those outside the excerpt.
+type SearchHighlight struct {
+	Field   string `json:"field"`
+	Snippet string `json:"snippet"`
+	Matches int    `json:"matches"`
+}
+
+// SearchFacets summarizes every analysis matching a search filter, not just
+// the page returned: how many there are, how many have each sentiment and
+// which topics are most common among them.
+type SearchFacets struct {
+	Total      int            `json:"total"`
+	Sentiments map[string]int `json:"sentiments"`
+	Topics     []TopicCount   `json:"topics"`
+}
+
+type TopicCount struct {
+	Topic string `json:"topic"`
+	Count int    `json:"count"`
+}
+
+type DeleteQuery struct {
+	SearchFilter
+	Confirm bool `form:"confirm"`
+	All     bool `form:"all"`
+}
+
+type DeleteResponse struct {
+	Deleted int64 `json:"deleted"`
+}
+
+type ReextractQuery struct {
+	SearchFilter
+	All bool `form:"all"`
+}
+
+type ReextractResponse struct {
+	Updated int `json:"updated"`
+}
+
+// TimelineBucket counts analyses created in the day, week or month starting
+// on Start (YYYY-MM-DD, UTC).
+type TimelineBucket struct {
+	Start string `json:"start"`
+	Count int    `json:"count"`
+}
+
+// AnalysisCluster is a group of stored analyses with overlapping topics and
+// keywords. Topics are the cluster's most common topics, most frequent first.
+type AnalysisCluster struct {
+	Topics      []string `json:"topics"`
+	Size        int      `json:"size"`
+	AnalysisIDs []string `json:"analysis_ids"`
+}
+
+type ClusterQuery struct {
+	SearchFilter
+	K int `form:"k,default=5" binding:"min=1,max=50"`
+}
+
+type KeywordGraphQuery struct {
+	SearchFilter
+	MinWeight int `form:"min_weight,default=1" binding:"min=1"`
+}
+
+// KeywordGraph links keywords that appear together in stored analyses. An
+// edge's Weight is the number of analyses listing both keywords; a node's
+// Count is the number listing its keyword. Only keywords on some edge are
+// nodes.
+type KeywordGraph struct {
+	Nodes []KeywordNode `json:"nodes"`
+	Edges []KeywordEdge `json:"edges"`
+}
+
+type KeywordNode struct {
+	Keyword string `json:"keyword"`
+	Count   int    `json:"count"`
+}
+
+type KeywordEdge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Weight int    `json:"weight"`
 }
 
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Code    string `json:"code,omitempty"`
-	Details string `json:"details,omitempty"`
+	Error     string    `json:"error"`
+	Code      ErrorCode `json:"code,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	Retryable bool      `json:"retryable"`
+}
+
+// Envelope is the standard body of every JSON response. Exactly one of Data
+// and Error is set; Meta carries list-level details such as counts and
+// cursors.
+type Envelope struct {
+	Data  interface{}            `json:"data"`
+	Meta  map[string]interface{} `json:"meta,omitempty"`
+	Error *APIError              `json:"error,omitempty"`
+}
+
+type APIError struct {
+	Message   string    `json:"message"`
+	Code      ErrorCode `json:"code,omitempty"`
+	Details   string    `json:"details,omitempty"`
+	Retryable bool      `json:"retryable"`
 }
\ No newline at end of file
@@ -0,0 +1,145 @@
+// Package response writes API responses in the standard envelope:
+//
+//	{"data": ..., "meta": {...}, "error": {...}}
+//
+// Clients that still expect the pre-envelope bodies can send
+// "X-Response-Format: legacy", or the server can default to the legacy shape
+// with the Legacy middleware, until they have migrated.
+//
+// Bodies are compact unless the request asks for "?pretty=true" or the
+// Pretty middleware makes indented output the default. GET requests whose
+// Accept header prefers application/yaml or text/yaml get the same body as
+// YAML instead.
+package response
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+const (
+	FormatHeader = "X-Response-Format"
+	FormatLegacy = "legacy"
+	PrettyQuery  = "pretty"
+	
+	legacyContextKey = "response.legacy"
+	prettyContextKey = "response.pretty"
+)
+
+// Legacy makes the legacy body shape the default for every request it wraps.
+// Requests can still opt into the envelope with "X-Response-Format: envelope".
+func Legacy() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(legacyContextKey, true)
+		c.Next()
+	}
+}
+
+// IsLegacy reports whether the response to c should use the legacy shape.
+func IsLegacy(c *gin.Context) bool {
+	if format := c.GetHeader(FormatHeader); format != "" {
+		return format == FormatLegacy
+	}
+	return c.GetBool(legacyContextKey)
+}
+
+// Pretty makes indented JSON the default for every request it wraps.
+// Requests can still ask for compact output with "?pretty=false".
+func Pretty() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(prettyContextKey, true)
+		c.Next()
+	}
+}
+
+// IsPretty reports whether the response to c should be indented.
+func IsPretty(c *gin.Context) bool {
+	if value, ok := c.GetQuery(PrettyQuery); ok {
+		pretty, err := strconv.ParseBool(value)
+		return err == nil && pretty
+	}
+	return c.GetBool(prettyContextKey)
+}
+
+func write(c *gin.Context, status int, body interface{}) {
+	if c.Request.Method == http.MethodGet {
+		c.Writer.Header().Add("Vary", "Accept")
+	}
+	if contentType, ok := yamlType(c); ok {
+		writeYAML(c, status, contentType, body)
+		return
+	}
+	if IsPretty(c) {
+		c.IndentedJSON(status, body)
+		return
+	}
+	c.JSON(status, body)
+}
+
+// JSON writes data as the envelope's data field. The legacy body is data
+// itself.
+func JSON(c *gin.Context, status int, data interface{}) {
+	if IsLegacy(c) {
+		write(c, status, data)
+		return
+	}
+	write(c, status, models.Envelope{Data: data})
+}
+
+// List writes items as the envelope's data field alongside meta. The legacy
+// body is meta with items added under key.
+func List(c *gin.Context, status int, key string, items interface{}, meta gin.H) {
+	if IsLegacy(c) {
+		body := gin.H{key: items}
+		for k, v := range meta {
+			body[k] = v
+		}
+		write(c, status, body)
+		return
+	}
+	write(c, status, models.Envelope{Data: items, Meta: meta})
+}
+
+// RespondError writes an error response for code. The message is the code's
+// standard message; err, when non-nil, supplies the details. Server errors
+// are logged with the request they failed.
+func RespondError(c *gin.Context, status int, code models.ErrorCode, err error) {
+	write(c, status, errorBody(c, status, code, err))
+}
+
+// AbortError is RespondError for middleware: it also stops the handler chain.
+func AbortError(c *gin.Context, status int, code models.ErrorCode, err error) {
+	c.Abort()
+	write(c, status, errorBody(c, status, code, err))
+}
+
+func errorBody(c *gin.Context, status int, code models.ErrorCode, err error) interface{} {
+	var details string
+	if err != nil {
+		details = err.Error()
+	}
+	if status >= http.StatusInternalServerError {
+		log.Printf("%s %s failed with %s: %s", c.Request.Method, c.Request.URL.Path, code, details)
+	}
+	
+	if IsLegacy(c) {
+		return models.ErrorResponse{
+			Error:     code.Message(),
+			Code:      code,
+			Details:   details,
+			Retryable: code.Retryable(),
+		}
+	}
+	return models.Envelope{
+		Error: &models.APIError{
+			Message:   code.Message(),
+			Code:      code,
+			Details:   details,
+			Retryable: code.Retryable(),
+		},
+	}
+}
\ No newline at end of file
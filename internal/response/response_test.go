@@ -0,0 +1,164 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func newRouter(middleware ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	
+	r := gin.New()
+	r.Use(middleware...)
+	r.GET("/item", func(c *gin.Context) {
+		JSON(c, http.StatusOK, gin.H{"id": "a1"})
+	})
+	r.GET("/list", func(c *gin.Context) {
+		List(c, http.StatusOK, "results", []string{"a1", "a2"}, gin.H{"count": 2})
+	})
+	r.GET("/error", func(c *gin.Context) {
+		RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, errors.New("text is required"))
+	})
+	
+	return r
+}
+
+func get(r http.Handler, path, format string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if format != "" {
+		req.Header.Set(FormatHeader, format)
+	}
+	
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestEnvelope(t *testing.T) {
+	r := newRouter()
+	
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/item", `{"data":{"id":"a1"}}`},
+		{"/list", `{"data":["a1","a2"],"meta":{"count":2}}`},
+		{"/error", `{"data":null,"error":{"message":"Invalid request","code":"INVALID_REQUEST","details":"text is required","retryable":false}}`},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.JSONEq(t, tt.expected, get(r, tt.path, "").Body.String())
+		})
+	}
+}
+
+func TestLegacyFormat(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/item", `{"id":"a1"}`},
+		{"/list", `{"results":["a1","a2"],"count":2}`},
+		{"/error", `{"error":"Invalid request","code":"INVALID_REQUEST","details":"text is required","retryable":false}`},
+	}
+	
+	t.Run("header", func(t *testing.T) {
+		r := newRouter()
+		for _, tt := range tests {
+			assert.JSONEq(t, tt.expected, get(r, tt.path, FormatLegacy).Body.String(), tt.path)
+		}
+	})
+	
+	t.Run("middleware", func(t *testing.T) {
+		r := newRouter(Legacy())
+		for _, tt := range tests {
+			assert.JSONEq(t, tt.expected, get(r, tt.path, "").Body.String(), tt.path)
+		}
+		
+		var envelope models.Envelope
+		require.NoError(t, json.Unmarshal(get(r, "/item", "envelope").Body.Bytes(), &envelope))
+		assert.Equal(t, map[string]interface{}{"id": "a1"}, envelope.Data)
+	})
+}
+
+func TestPrettyJSON(t *testing.T) {
+	compact := `{"data":{"id":"a1"}}`
+	pretty := "{\n    \"data\": {\n        \"id\": \"a1\"\n    }\n}"
+	
+	t.Run("query", func(t *testing.T) {
+		r := newRouter()
+		assert.Equal(t, compact, get(r, "/item", "").Body.String())
+		assert.Equal(t, pretty, get(r, "/item?pretty=true", "").Body.String())
+		assert.Equal(t, compact, get(r, "/item?pretty=false", "").Body.String())
+		assert.Equal(t, compact, get(r, "/item?pretty=yes-please", "").Body.String())
+	})
+	
+	t.Run("middleware", func(t *testing.T) {
+		r := newRouter(Pretty())
+		assert.Equal(t, pretty, get(r, "/item", "").Body.String())
+		assert.Equal(t, compact, get(r, "/item?pretty=false", "").Body.String())
+		assert.Contains(t, get(r, "/error", "").Body.String(), "\n    \"error\": {")
+	})
+}
+
+func TestRespondError(t *testing.T) {
+	tests := []struct {
+		status    int
+		code      models.ErrorCode
+		err       error
+		retryable bool
+	}{
+		{http.StatusBadRequest, models.CodeInvalidRequest, errors.New("bad json"), false},
+		{http.StatusBadRequest, models.CodeEmptyInput, nil, false},
+		{http.StatusNotFound, models.CodeNotFound, nil, false},
+		{http.StatusRequestEntityTooLarge, models.CodePayloadTooLarge, errors.New("too big"), false},
+		{http.StatusInternalServerError, models.CodeDBError, errors.New("disk I/O error"), false},
+		{http.StatusServiceUnavailable, models.CodeLLMUnavailable, errors.New("upstream down"), true},
+		{http.StatusGatewayTimeout, models.CodeTimeout, errors.New("deadline exceeded"), true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			r := gin.New()
+			r.GET("/error", func(c *gin.Context) {
+				RespondError(c, tt.status, tt.code, tt.err)
+			})
+			r.GET("/abort", func(c *gin.Context) {
+				AbortError(c, tt.status, tt.code, tt.err)
+			}, func(c *gin.Context) {
+				c.String(http.StatusOK, "unreachable")
+			})
+			
+			for _, path := range []string{"/error", "/abort"} {
+				w := get(r, path, "")
+				assert.Equal(t, tt.status, w.Code, path)
+				
+				var envelope models.Envelope
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &envelope), w.Body.String())
+				require.NotNil(t, envelope.Error, path)
+				assert.Equal(t, tt.code, envelope.Error.Code)
+				assert.Equal(t, tt.code.Message(), envelope.Error.Message)
+				assert.Equal(t, tt.retryable, envelope.Error.Retryable)
+				if tt.err != nil {
+					assert.Equal(t, tt.err.Error(), envelope.Error.Details)
+				} else {
+					assert.Empty(t, envelope.Error.Details)
+				}
+				
+				var legacy models.ErrorResponse
+				require.NoError(t, json.Unmarshal(get(r, path, FormatLegacy).Body.Bytes(), &legacy))
+				assert.Equal(t, tt.code, legacy.Code)
+				assert.Equal(t, tt.retryable, legacy.Retryable)
+			}
+		})
+	}
+}
\ No newline at end of file
@@ -0,0 +1,74 @@
+package response
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	MIMEYAML     = "application/yaml"
+	MIMETextYAML = "text/yaml"
+)
+
+// yamlType returns the YAML media type a GET request prefers over JSON in
+// its Accept header. Other methods, and requests without a preference,
+// always get JSON.
+func yamlType(c *gin.Context) (string, bool) {
+	if c.Request.Method != http.MethodGet {
+		return "", false
+	}
+	switch format := c.NegotiateFormat(gin.MIMEJSON, MIMEYAML, MIMETextYAML); format {
+	case MIMEYAML, MIMETextYAML:
+		return format, true
+	}
+	return "", false
+}
+
+// writeYAML renders body as YAML by way of its JSON encoding, so keys keep
+// their json tag names and order and values their JSON forms (RFC 3339
+// times, omitted empty fields).
+func writeYAML(c *gin.Context, status int, contentType string, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		renderFailed(c, err)
+		return
+	}
+	
+	// JSON is valid YAML, so it decodes straight into a node tree; dropping
+	// the flow and quoting styles it was read with gives block-style output.
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		renderFailed(c, err)
+		return
+	}
+	resetStyle(&node)
+	
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&node); err != nil {
+		renderFailed(c, err)
+		return
+	}
+	encoder.Close()
+	
+	c.Data(status, contentType+"; charset=utf-8", buf.Bytes())
+}
+
+// renderFailed reports a body that couldn't be rendered as YAML with the
+// usual error body, in JSON since YAML is what failed.
+func renderFailed(c *gin.Context, err error) {
+	c.JSON(http.StatusInternalServerError, errorBody(c, http.StatusInternalServerError, models.CodeRenderError, err))
+}
+
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetStyle(child)
+	}
+}
\ No newline at end of file
@@ -0,0 +1,143 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+func request(r http.Handler, method, path, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestYAMLNegotiation(t *testing.T) {
+	r := newRouter()
+	
+	tests := []struct {
+		accept      string
+		contentType string
+	}{
+		{"", gin.MIMEJSON},
+		{"*/*", gin.MIMEJSON},
+		{"application/json", gin.MIMEJSON},
+		{"application/yaml", MIMEYAML},
+		{"text/yaml", MIMETextYAML},
+		{"text/yaml, application/json", MIMETextYAML},
+		{"application/json, application/yaml", gin.MIMEJSON},
+		{"text/html", gin.MIMEJSON},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			w := request(r, http.MethodGet, "/item", tt.accept)
+			require.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.contentType+"; charset=utf-8", w.Header().Get("Content-Type"))
+			assert.Contains(t, w.Header().Values("Vary"), "Accept")
+		})
+	}
+}
+
+func TestYAMLBody(t *testing.T) {
+	r := newRouter()
+	
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/item", "data:\n  id: a1\n"},
+		{"/list", "data:\n  - a1\n  - a2\nmeta:\n  count: 2\n"},
+		{"/error", "data: null\nerror:\n  message: Invalid request\n  code: INVALID_REQUEST\n  details: text is required\n  retryable: false\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, request(r, http.MethodGet, tt.path, MIMEYAML).Body.String())
+		})
+	}
+}
+
+func TestYAMLUsesJSONNames(t *testing.T) {
+	createdAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	r := gin.New()
+	r.GET("/analysis", func(c *gin.Context) {
+		List(c, http.StatusOK, "results", []models.TextAnalysis{{
+			ID:           "a1",
+			Summary:      "Line one.\nLine two.",
+			Metadata:     map[string]interface{}{"topics": []string{"yes", "123"}},
+			Confidence:   0.75,
+			CreatedAt:    createdAt,
+			ProcessingMS: 12,
+		}}, gin.H{"count": 1})
+	})
+	
+	w := request(r, http.MethodGet, "/analysis", "application/yaml")
+	require.Equal(t, http.StatusOK, w.Code)
+	
+	var body struct {
+		Data []struct {
+			ID           string                 `yaml:"id"`
+			Summary      string                 `yaml:"summary"`
+			Metadata     map[string]interface{} `yaml:"metadata"`
+			Confidence   float64                `yaml:"confidence"`
+			CreatedAt    string                 `yaml:"created_at"`
+			ProcessingMS int64                  `yaml:"processing_ms"`
+		} `yaml:"data"`
+		Meta map[string]int `yaml:"meta"`
+	}
+	require.NoError(t, yaml.Unmarshal(w.Body.Bytes(), &body), w.Body.String())
+	require.Len(t, body.Data, 1)
+	assert.Equal(t, "a1", body.Data[0].ID)
+	assert.Equal(t, "Line one.\nLine two.", body.Data[0].Summary)
+	assert.Equal(t, []interface{}{"yes", "123"}, body.Data[0].Metadata["topics"], "strings that look like other types stay strings")
+	assert.Equal(t, 0.75, body.Data[0].Confidence)
+	assert.Equal(t, "2024-03-01T12:00:00Z", body.Data[0].CreatedAt)
+	assert.Equal(t, int64(12), body.Data[0].ProcessingMS)
+	assert.Equal(t, 1, body.Meta["count"])
+	assert.NotContains(t, w.Body.String(), "text:", "omitempty fields stay omitted")
+	assert.False(t, strings.HasPrefix(w.Body.String(), "{"), "block style, not JSON")
+}
+
+func TestYAMLOnlyForGET(t *testing.T) {
+	r := gin.New()
+	r.POST("/analyze", func(c *gin.Context) {
+		RespondError(c, http.StatusBadRequest, models.CodeInvalidRequest, errors.New("text is required"))
+	})
+	
+	w := request(r, http.MethodPost, "/analyze", MIMEYAML)
+	assert.Equal(t, gin.MIMEJSON+"; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Empty(t, w.Header().Values("Vary"))
+}
+
+func TestYAMLRenderFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/broken", func(c *gin.Context) {
+		JSON(c, http.StatusOK, gin.H{"score": math.Inf(1)})
+	})
+	
+	w := request(r, http.MethodGet, "/broken", MIMEYAML)
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, gin.MIMEJSON+"; charset=utf-8", w.Header().Get("Content-Type"))
+	
+	var body models.Envelope
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.NotNil(t, body.Error)
+	assert.Equal(t, models.CodeRenderError, body.Error.Code)
+	assert.Contains(t, body.Error.Details, "unsupported value")
+}
\ No newline at end of file
@@ -0,0 +1,79 @@
+// Package retention removes analyses once they are older than the configured
+// retention period.
+package retention
+
+import (
+	"context"
+	"log"
+	"time"
+	
+	"github.com/user/llm-knowledge-extractor/internal/database"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+type Cleaner struct {
+	dbs    func() ([]*database.DB, error)
+	maxAge time.Duration
+	now    func() time.Time
+}
+
+func NewCleaner(db *database.DB, maxAge time.Duration) *Cleaner {
+	return &Cleaner{
+		dbs:    func() ([]*database.DB, error) { return []*database.DB{db}, nil },
+		maxAge: maxAge,
+		now:    time.Now,
+	}
+}
+
+// NewTenantCleaner cleans every tenant's database, including tenants whose
+// database has not been opened since startup.
+func NewTenantCleaner(tenants *database.Tenants, maxAge time.Duration) *Cleaner {
+	return &Cleaner{
+		dbs:    tenants.All,
+		maxAge: maxAge,
+		now:    time.Now,
+	}
+}
+
+// Cleanup deletes every analysis created more than maxAge ago, together with
+// its tags and history, and returns how many analyses were removed.
+func (c *Cleaner) Cleanup(ctx context.Context) (int64, error) {
+	dbs, err := c.dbs()
+	if err != nil {
+		return 0, err
+	}
+	
+	cutoff := c.now().Add(-c.maxAge)
+	var total int64
+	for _, db := range dbs {
+		deleted, err := db.DeleteAnalysesContext(ctx, models.SearchFilter{To: cutoff})
+		total += deleted
+		if err != nil {
+			return total, err
+		}
+	}
+	return total, nil
+}
+
+// Run calls Cleanup once immediately and then every interval until ctx is
+// cancelled. Failures are logged and retried on the next tick.
+func (c *Cleaner) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	
+	for {
+		deleted, err := c.Cleanup(ctx)
+		switch {
+		case err != nil && ctx.Err() == nil:
+			log.Printf("Retention cleanup failed: %v", err)
+		case deleted > 0:
+			log.Printf("Retention cleanup removed %d analyses older than %s", deleted, c.maxAge)
+		}
+		
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
\ No newline at end of file
@@ -0,0 +1,90 @@
+package retention
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+	
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/user/llm-knowledge-extractor/internal/database"
+	"github.com/user/llm-knowledge-extractor/internal/models"
+)
+
+func newTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	
+	db, err := database.New(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func seedAnalysis(t *testing.T, db *database.DB, id string, createdAt time.Time) {
+	t.Helper()
+	
+	err := db.SaveAnalysis(&models.TextAnalysis{
+		ID:         id,
+		Text:       "Text for " + id,
+		Summary:    "Summary for " + id,
+		Metadata:   map[string]interface{}{"tags": []string{"retained"}},
+		Confidence: 0.8,
+		CreatedAt:  createdAt,
+	})
+	require.NoError(t, err)
+}
+
+func TestCleaner_Cleanup(t *testing.T) {
+	db := newTestDB(t)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	
+	seedAnalysis(t, db, "old", now.Add(-40*24*time.Hour))
+	seedAnalysis(t, db, "edge", now.Add(-29*24*time.Hour))
+	seedAnalysis(t, db, "new", now.Add(-time.Hour))
+	
+	cleaner := NewCleaner(db, 30*24*time.Hour)
+	cleaner.now = func() time.Time { return now }
+	
+	deleted, err := cleaner.Cleanup(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), deleted)
+	
+	old, err := db.GetAnalysis("old")
+	require.NoError(t, err)
+	assert.Nil(t, old)
+	
+	for _, id := range []string{"edge", "new"} {
+		kept, err := db.GetAnalysis(id)
+		require.NoError(t, err)
+		assert.NotNil(t, kept, id)
+	}
+	
+	deleted, err = cleaner.Cleanup(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestCleaner_RunStopsOnCancel(t *testing.T) {
+	db := newTestDB(t)
+	seedAnalysis(t, db, "old", time.Now().Add(-48*time.Hour))
+	
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewCleaner(db, 24*time.Hour).Run(ctx, time.Hour)
+		close(done)
+	}()
+	
+	require.Eventually(t, func() bool {
+		analysis, err := db.GetAnalysis("old")
+		return err == nil && analysis == nil
+	}, time.Second, 10*time.Millisecond)
+	
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+}
\ No newline at end of file
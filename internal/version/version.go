@@ -0,0 +1,26 @@
+package version
+
+// Build details, injected at link time:
+//
+//	go build -ldflags "-X github.com/user/llm-knowledge-extractor/internal/version.Version=v1.2.0 \
+//	  -X github.com/user/llm-knowledge-extractor/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/user/llm-knowledge-extractor/internal/version.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
+
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+}
+
+func Get() Info {
+	return Info{
+		Version:   Version,
+		Commit:    Commit,
+		BuildTime: BuildTime,
+	}
+}
\ No newline at end of file